@@ -0,0 +1,183 @@
+// тут лежит тестовый код
+// менять вам может потребоваться только коннект к базе
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"db_explorer/dbexplorer"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+var (
+	// DSN это соединение с базой
+	// вы можете изменить этот на тот который вам нужен
+	// docker run -p 3306:3306 -v $(PWD):/docker-entrypoint-initdb.d -e MYSQL_ROOT_PASSWORD=1234 -e MYSQL_DATABASE=golang -d mysql
+	DSN = "root:1234@tcp(localhost:3306)/golang?charset=utf8"
+	// DSN = "coursera:5QPbAUufx7@tcp(localhost:3306)/coursera?charset=utf8"
+)
+
+//"mysql:host=xxx;port=xxx;dbname=xxx;user=xxx;password=xxx"
+
+// subjectRolesFlag собирает повторяющиеся флаги вида -client-cert-role "subject=role1,role2"
+type subjectRolesFlag map[string][]string
+
+func (f subjectRolesFlag) String() string {
+	return fmt.Sprint(map[string][]string(f))
+}
+
+func (f subjectRolesFlag) Set(value string) error {
+	subject, roles, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected subject=role1,role2")
+	}
+
+	f[subject] = append(f[subject], strings.Split(roles, ",")...)
+	return nil
+}
+
+// patternsFlag собирает повторяющиеся флаги с регулярными выражениями
+type patternsFlag []string
+
+func (f *patternsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *patternsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// renamesFlag собирает повторяющиеся флаги вида -rename-table "^tbl_="
+type renamesFlag []dbexplorer.RenameRule
+
+func (f *renamesFlag) String() string {
+	return fmt.Sprint([]dbexplorer.RenameRule(*f))
+}
+
+func (f *renamesFlag) Set(value string) error {
+	pattern, replacement, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected pattern=replacement")
+	}
+
+	*f = append(*f, dbexplorer.RenameRule{Pattern: pattern, Replacement: replacement})
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if err := replay(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	addr := flag.String("addr", ":8082", "listen address")
+	dsn := flag.String("dsn", DSN, "database connection string")
+	tlsCert := flag.String("tls-cert", "", "server certificate file, enables https")
+	tlsKey := flag.String("tls-key", "", "server private key file")
+	clientCA := flag.String("client-ca", "", "CA bundle to verify client certificates, enables mutual TLS")
+	clientCertRoles := subjectRolesFlag{}
+	flag.Var(clientCertRoles, "client-cert-role", "map client certificate subject to roles: subject=role1,role2 (repeatable)")
+	readOnly := flag.Bool("read-only", false, "reject every write, for browsing production databases")
+	ui := flag.Bool("ui", false, "serve the admin UI at /_ui")
+	journalPath := flag.String("journal", "", "append every mutating request to this file before executing it")
+	maxBodySize := flag.Int64("max-body-size", 10<<20, "reject request bodies larger than this many bytes with 413")
+	maxLimit := flag.Int("max-limit", 0, "reject list requests with a larger ?limit= with 400, 0 means no cap")
+	opaURL := flag.String("opa-url", "", "authorize requests with an OPA decision, e.g. http://localhost:8181/v1/data/dbexplorer/authz")
+	var rules dbexplorer.SchemaRules
+	flag.Var((*patternsFlag)(&rules.ExcludeTables), "exclude-table", "hide tables matching the regexp (repeatable)")
+	flag.Var((*patternsFlag)(&rules.ExcludeColumns), "exclude-column", "hide columns matching the regexp (repeatable)")
+	flag.Var((*renamesFlag)(&rules.RenameTables), "rename-table", "rename tables: pattern=replacement (repeatable)")
+	flag.Var((*renamesFlag)(&rules.RenameColumns), "rename-column", "rename columns: pattern=replacement (repeatable)")
+	flag.Parse()
+
+	// без -tls-cert сервер слушает http и клиентские сертификаты никто не проверит
+	if *clientCA != "" && *tlsCert == "" {
+		panic("-client-ca requires -tls-cert")
+	}
+
+	db, err := sql.Open("mysql", *dsn)
+	err = db.Ping() // вот тут будет первое подключение к базе
+	if err != nil {
+		panic(err)
+	}
+
+	opts := []dbexplorer.Option{dbexplorer.WithMaxBodySize(*maxBodySize)}
+	if *clientCA != "" {
+		opts = append(opts, dbexplorer.WithClientCertAuth(clientCertRoles))
+	}
+	if *maxLimit > 0 {
+		opts = append(opts, dbexplorer.WithMaxLimit(*maxLimit))
+	}
+	if *readOnly {
+		opts = append(opts, dbexplorer.WithReadOnly())
+	}
+	if *ui {
+		opts = append(opts, dbexplorer.WithUI())
+	}
+	if len(rules.ExcludeTables)+len(rules.ExcludeColumns)+len(rules.RenameTables)+len(rules.RenameColumns) > 0 {
+		opts = append(opts, dbexplorer.WithSchemaRules(rules))
+	}
+	if *opaURL != "" {
+		opa, err := dbexplorer.NewOPAAuthorizer(*opaURL)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, dbexplorer.WithAuthorizer(opa.Authorize))
+	}
+	if *journalPath != "" {
+		journal, err := dbexplorer.NewFileJournal(*journalPath)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, dbexplorer.WithJournal(journal))
+	}
+
+	handler, err := dbexplorer.New(db, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	server := &http.Server{
+		Addr:    *addr,
+		Handler: handler,
+	}
+
+	if *tlsCert == "" {
+		fmt.Println("starting server at " + *addr)
+		server.ListenAndServe()
+		return
+	}
+
+	if *clientCA != "" {
+		pem, err := os.ReadFile(*clientCA)
+		if err != nil {
+			panic(err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			panic("no certificates found in " + *clientCA)
+		}
+
+		server.TLSConfig = &tls.Config{
+			ClientCAs:  pool,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	fmt.Println("starting https server at " + *addr)
+	server.ListenAndServeTLS(*tlsCert, *tlsKey)
+}
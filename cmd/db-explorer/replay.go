@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"db_explorer/dbexplorer"
+)
+
+// replay применяет записанный журнал (-journal) к другой базе:
+// db-explorer replay -journal writes.log -target-dsn ... -on-conflict skip
+func replay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	journalPath := flags.String("journal", "", "journal written by the server's -journal flag")
+	targetDSN := flags.String("target-dsn", "", "database to apply the journal to")
+	onConflict := flags.String("on-conflict", "abort", "what to do with rejected entries: abort or skip")
+	flags.Parse(args)
+
+	if *journalPath == "" || *targetDSN == "" {
+		return fmt.Errorf("-journal and -target-dsn are required")
+	}
+
+	policy, err := dbexplorer.ParseConflictPolicy(*onConflict)
+	if err != nil {
+		return err
+	}
+
+	journal, err := os.Open(*journalPath)
+	if err != nil {
+		return err
+	}
+	defer journal.Close()
+
+	db, err := sql.Open("mysql", *targetDSN)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		return err
+	}
+
+	handler, err := dbexplorer.New(db)
+	if err != nil {
+		return err
+	}
+
+	result, err := dbexplorer.Replay(context.Background(), journal, handler, policy, func(err *dbexplorer.ReplayError) {
+		fmt.Fprintln(os.Stderr, "skipped", err)
+	})
+	fmt.Printf("applied %d, skipped %d\n", result.Applied, result.Skipped)
+
+	return err
+}
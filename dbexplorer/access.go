@@ -0,0 +1,206 @@
+package dbexplorer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AccessRule allows a role the HTTP methods on the tables, e.g. role "reader" GET on orders and users.
+// "*" matches any table or method, GET allows HEAD as well.
+type AccessRule struct {
+	Role    string
+	Tables  []string
+	Methods []string
+}
+
+// RoleResolver returns the roles of a request. Without one the roles of the authenticated principal are used.
+type RoleResolver func(r *http.Request) ([]string, error)
+
+type accessControl struct {
+	rules    []AccessRule
+	resolver RoleResolver
+}
+
+// WithAccessRules restricts the table endpoints to the methods the rules allow for the roles of the request.
+// Tables without a matching rule are forbidden.
+func WithAccessRules(rules ...AccessRule) Option {
+	return func(exp *Explorer) error {
+		if exp.access == nil {
+			exp.access = &accessControl{}
+		}
+
+		exp.access.rules = append(exp.access.rules, rules...)
+		return nil
+	}
+}
+
+// WithRoleResolver replaces the roles of the principal with the ones resolved from the request.
+func WithRoleResolver(resolver RoleResolver) Option {
+	return func(exp *Explorer) error {
+		if exp.access == nil {
+			exp.access = &accessControl{}
+		}
+
+		exp.access.resolver = resolver
+		return nil
+	}
+}
+
+// RolesFromHeader reads comma separated roles from the header. The header must be set by a trusted
+// proxy in front of the explorer, clients could claim any role otherwise.
+func RolesFromHeader(header string) RoleResolver {
+	return func(r *http.Request) ([]string, error) {
+		return splitRoles(r.Header.Get(header)), nil
+	}
+}
+
+// RolesFromJWTClaim reads the roles from a claim of the HS256 JWT in "Authorization: Bearer <token>".
+// The claim is a list of strings or a string of roles separated by spaces or commas.
+func RolesFromJWTClaim(secret []byte, claim string) RoleResolver {
+	return func(r *http.Request) ([]string, error) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return nil, nil
+		}
+
+		claims, err := parseJWT(secret, token)
+		if err != nil {
+			return nil, err
+		}
+
+		switch value := claims[claim].(type) {
+		case string:
+			return splitRoles(value), nil
+		case []any:
+			roles := make([]string, 0, len(value))
+			for _, role := range value {
+				if s, ok := role.(string); ok {
+					roles = append(roles, s)
+				}
+			}
+			return roles, nil
+		}
+
+		return nil, nil
+	}
+}
+
+func splitRoles(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// parseJWT verifies an HS256 token and returns its claims.
+func parseJWT(secret []byte, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	claims := make(map[string]any)
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if expires, ok := claims["exp"].(float64); ok && time.Now().Unix() >= int64(expires) {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	return claims, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func matchesAny(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsMethod matches the method of the action. Actions checked outside of the table endpoints, e.g.
+// the tables of a transaction, have no method and match the methods of their operation.
+func (rule AccessRule) allowsMethod(action Action) bool {
+	for _, method := range rule.Methods {
+		switch {
+		case method == "*":
+			return true
+		case action.Method == "" && actionOp(method) == action.Op:
+			return true
+		case method == action.Method || method == http.MethodGet && action.Method == http.MethodHead:
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *accessControl) allows(principal *Principal, action Action) bool {
+	for _, rule := range a.rules {
+		if principal.HasRole(rule.Role) && matchesAny(rule.Tables, action.Table) && rule.allowsMethod(action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveRoles replaces the roles of the principal of the request by the ones of the resolver.
+func (exp Explorer) resolveRoles(r *http.Request) (*http.Request, error) {
+	if exp.access == nil || exp.access.resolver == nil {
+		return r, nil
+	}
+
+	roles, err := exp.access.resolver(r)
+	if err != nil {
+		return r, err
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if principal == nil && len(roles) == 0 {
+		return r, nil
+	}
+
+	resolved := &Principal{Roles: roles}
+	if principal != nil {
+		resolved.Name = principal.Name
+		resolved.Scopes = principal.Scopes
+	}
+
+	return r.WithContext(ContextWithPrincipal(r.Context(), resolved)), nil
+}
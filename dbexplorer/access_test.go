@@ -0,0 +1,133 @@
+package dbexplorer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAccessRules(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{"orders": {}, "users": {}, "secrets": {}})
+	exp.access = &accessControl{
+		rules: []AccessRule{
+			{Role: "reader", Tables: []string{"orders", "users"}, Methods: []string{http.MethodGet}},
+			{Role: "writer", Tables: []string{"*"}, Methods: []string{"*"}},
+		},
+		resolver: RolesFromHeader("X-Roles"),
+	}
+	handler := exp.permissionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	cases := []struct {
+		method   string
+		path     string
+		roles    string
+		expected int
+	}{
+		{http.MethodGet, "/orders", "reader", http.StatusOK},
+		{http.MethodHead, "/orders/1", "reader", http.StatusOK},
+		{http.MethodPost, "/orders/1", "reader", http.StatusForbidden},
+		{http.MethodGet, "/secrets", "reader", http.StatusForbidden},
+		{http.MethodGet, "/orders", "", http.StatusForbidden},
+		{http.MethodDelete, "/secrets/1", "reader, writer", http.StatusOK},
+		{http.MethodGet, "/_health", "", http.StatusOK},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, c.path, nil)
+		r.Header.Set("X-Roles", c.roles)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != c.expected {
+			t.Errorf("%s %s as %q: expected %d, got %d", c.method, c.path, c.roles, c.expected, w.Code)
+		}
+	}
+
+	if exp.authorize(context.Background(), &Principal{Roles: []string{"reader"}}, Action{Table: "users", Op: OpWrite}) == nil {
+		t.Errorf("expected a nested write to be denied for reader")
+	}
+}
+
+func TestAuthorizer(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{"orders": {}})
+	exp.access = &accessControl{rules: []AccessRule{{Role: "nobody", Tables: []string{"*"}, Methods: []string{"*"}}}}
+	err := WithAuthorizer(func(ctx context.Context, principal *Principal, action Action) error {
+		if action.Op == OpWrite && (len(action.Columns) == 0 || action.Columns[0] == "total") {
+			return fmt.Errorf("%s of %s denied by policy", action.Op, action.Table)
+		}
+		return nil
+	})(&exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := exp.permissionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for method, expected := range map[string]int{http.MethodGet: http.StatusOK, http.MethodDelete: http.StatusForbidden} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, "/orders/1", nil))
+		if w.Code != expected {
+			t.Errorf("%s: expected %d, got %d", method, expected, w.Code)
+		}
+	}
+
+	writable := exp.columnWritable(context.Background(), "orders", nil)
+	if writable == nil || writable("total") || !writable("comment") {
+		t.Errorf("expected the authorizer to decide on every written column")
+	}
+}
+
+func TestRolesFromJWTClaim(t *testing.T) {
+	secret := []byte("secret")
+	sign := func(payload string) string {
+		unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(payload))
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(unsigned))
+		return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	}
+	resolver := RolesFromJWTClaim(secret, "roles")
+
+	r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	r.Header.Set("Authorization", "Bearer "+sign(`{"roles":["reader","writer"]}`))
+	if roles, err := resolver(r); err != nil || len(roles) != 2 || roles[1] != "writer" {
+		t.Errorf("unexpected roles %v, %v", roles, err)
+	}
+
+	r.Header.Set("Authorization", "Bearer "+sign(`{"roles":"reader","exp":1}`))
+	if _, err := resolver(r); err == nil {
+		t.Errorf("expected error for an expired token")
+	}
+
+	r.Header.Set("Authorization", "Bearer "+sign(`{"roles":"reader"}`)+"x")
+	if _, err := resolver(r); err == nil {
+		t.Errorf("expected error for a bad signature")
+	}
+}
+
+func TestAdminEndpointsWithAccessRules(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{"orders": {}})
+	exp.adminRole = "admin"
+	exp.queryOptions = &QueryOptions{MaxRows: 10}
+	exp.access = &accessControl{
+		rules:    []AccessRule{{Role: "reader", Tables: []string{"*"}, Methods: []string{http.MethodGet}}},
+		resolver: RolesFromHeader("X-Roles"),
+	}
+	handler := exp.permissionMiddleware(http.HandlerFunc(exp.handlerQuery))
+
+	for roles, expected := range map[string]int{"": http.StatusForbidden, "reader": http.StatusForbidden, "admin": http.StatusBadRequest} {
+		r := httptest.NewRequest(http.MethodPost, "/_query", strings.NewReader(`{"query": "DELETE FROM orders"}`))
+		r.Header.Set("X-Roles", roles)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != expected {
+			t.Errorf("/_query as %q: expected %d, got %d", roles, expected, w.Code)
+		}
+	}
+}
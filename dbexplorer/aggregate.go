@@ -0,0 +1,360 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var aggregateFunctions = map[string]string{
+	"count": "COUNT",
+	"sum":   "SUM",
+	"avg":   "AVG",
+	"min":   "MIN",
+	"max":   "MAX",
+}
+
+// windowFunctions maps ranking functions to SQL, they take no column.
+var windowFunctions = map[string]string{
+	"row_number": "ROW_NUMBER",
+	"rank":       "RANK",
+	"dense_rank": "DENSE_RANK",
+}
+
+// runningFunctions maps cumulative window functions to SQL, they take a column.
+var runningFunctions = map[string]string{
+	"running_sum":   "SUM",
+	"running_avg":   "AVG",
+	"running_count": "COUNT",
+}
+
+type AggregateExpr struct {
+	Func   string
+	Column string
+}
+
+func (a AggregateExpr) Alias() string {
+	if a.Column == "" {
+		return a.Func
+	}
+
+	return a.Func + "_" + a.Column
+}
+
+// AggregateQuery is a parsed ?group_by=a&agg=count,sum:b&window=rank&partition_by=a&order_by=-sum_b request.
+// Windows are computed over the grouped rows when group_by or agg is given and over the table rows otherwise.
+type AggregateQuery struct {
+	GroupBy     []string
+	Aggregates  []AggregateExpr
+	Windows     []AggregateExpr
+	PartitionBy []string
+	OrderBy     []SortField
+	Pagination  Pagination
+}
+
+func (q AggregateQuery) grouped() bool {
+	return len(q.GroupBy) > 0 || len(q.Aggregates) > 0
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// parseFunctionList parses "count,sum:amount" into expressions.
+func parseFunctionList(value string) []AggregateExpr {
+	exprs := make([]AggregateExpr, 0)
+	for _, item := range splitList(value) {
+		fn, column, _ := strings.Cut(item, ":")
+		exprs = append(exprs, AggregateExpr{Func: strings.ToLower(fn), Column: column})
+	}
+
+	return exprs
+}
+
+func (exp Explorer) parseAggregateQuery(table string, query url.Values) (AggregateQuery, error) {
+	pagination, err := exp.tablePagination(table, query)
+	if err != nil {
+		return AggregateQuery{}, err
+	}
+
+	aggQuery := AggregateQuery{
+		GroupBy:     splitList(query.Get("group_by")),
+		Aggregates:  parseFunctionList(query.Get("agg")),
+		Windows:     parseFunctionList(query.Get("window")),
+		PartitionBy: splitList(query.Get("partition_by")),
+		Pagination:  pagination,
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return aggQuery, err
+	}
+
+	checkColumn := func(column string, numeric bool) error {
+		info, ok := schema.Column(column)
+		if !ok {
+			return fmt.Errorf("unknown column %s", column)
+		}
+		if numeric && !isNumericDataType(info.DataType) {
+			return fmt.Errorf("column %s is not numeric", column)
+		}
+
+		return nil
+	}
+
+	for _, column := range aggQuery.GroupBy {
+		if err := checkColumn(column, false); err != nil {
+			return aggQuery, err
+		}
+	}
+
+	for _, a := range aggQuery.Aggregates {
+		if _, ok := aggregateFunctions[a.Func]; !ok {
+			return aggQuery, fmt.Errorf("unknown agg %s", a.Func)
+		}
+		if a.Column == "" && a.Func != "count" {
+			return aggQuery, fmt.Errorf("agg %s requires a column", a.Func)
+		}
+		if a.Column != "" {
+			if err := checkColumn(a.Column, a.Func == "sum" || a.Func == "avg"); err != nil {
+				return aggQuery, err
+			}
+		}
+	}
+
+	// names the windows may refer to
+	available := make(map[string]bool)
+	if aggQuery.grouped() {
+		for _, column := range aggQuery.GroupBy {
+			available[column] = true
+		}
+		for _, a := range aggQuery.Aggregates {
+			available[a.Alias()] = true
+		}
+	} else {
+		for _, c := range schema.Columns {
+			available[c.Name] = true
+		}
+	}
+
+	for _, w := range aggQuery.Windows {
+		_, ranking := windowFunctions[w.Func]
+		_, running := runningFunctions[w.Func]
+		switch {
+		case ranking && w.Column != "":
+			return aggQuery, fmt.Errorf("window %s takes no column", w.Func)
+		case running && !available[w.Column]:
+			return aggQuery, fmt.Errorf("unknown window column %s", w.Column)
+		case !ranking && !running:
+			return aggQuery, fmt.Errorf("unknown window %s", w.Func)
+		}
+	}
+
+	for _, column := range aggQuery.PartitionBy {
+		if !available[column] {
+			return aggQuery, fmt.Errorf("unknown partition column %s", column)
+		}
+	}
+
+	for _, name := range splitList(query.Get("order_by")) {
+		field := SortField{Column: strings.TrimPrefix(name, "-"), Desc: strings.HasPrefix(name, "-")}
+		if !available[field.Column] {
+			return aggQuery, fmt.Errorf("unknown order column %s", field.Column)
+		}
+		aggQuery.OrderBy = append(aggQuery.OrderBy, field)
+	}
+
+	if len(aggQuery.Windows) > 0 && len(aggQuery.OrderBy) == 0 {
+		return aggQuery, fmt.Errorf("window functions require order_by")
+	}
+
+	return aggQuery, nil
+}
+
+// buildAggregateQuery generates the SELECT statement. Every identifier is checked against the table schema
+// in parseAggregateQuery and quoted, functions come from the allowlists above. Window functions need MySQL 8+.
+func (exp Explorer) buildAggregateQuery(table string, aggQuery AggregateQuery) (string, []any) {
+	// SQL expressions of the names available to windows and ORDER BY
+	expressions := make(map[string]string)
+	selectList := make([]string, 0)
+
+	if aggQuery.grouped() {
+		for _, column := range aggQuery.GroupBy {
+			expressions[column] = exp.quoteColumn(table, column)
+			selectList = append(selectList, exp.quoteColumn(table, column))
+		}
+
+		for _, a := range aggQuery.Aggregates {
+			argument := "*"
+			if a.Column != "" {
+				argument = exp.quoteColumn(table, a.Column)
+			}
+
+			expression := fmt.Sprintf("%s(%s)", aggregateFunctions[a.Func], argument)
+			expressions[a.Alias()] = expression
+			selectList = append(selectList, expression+" AS "+exp.dialect.QuoteIdent(a.Alias()))
+		}
+	} else {
+		selectList = append(selectList, exp.quote(table)+".*")
+		if schema, err := exp.getTableSchema(table); err == nil {
+			for _, c := range schema.Columns {
+				expressions[c.Name] = exp.quoteColumn(table, c.Name)
+			}
+		}
+	}
+
+	orderTerms := make([]string, len(aggQuery.OrderBy))
+	for i, field := range aggQuery.OrderBy {
+		orderTerms[i] = expressions[field.Column]
+		if field.Desc {
+			orderTerms[i] += " DESC"
+		}
+	}
+	orderBy := strings.Join(orderTerms, ", ")
+
+	over := "ORDER BY " + orderBy
+	if len(aggQuery.PartitionBy) > 0 {
+		partitions := make([]string, len(aggQuery.PartitionBy))
+		for i, column := range aggQuery.PartitionBy {
+			partitions[i] = expressions[column]
+		}
+		over = "PARTITION BY " + strings.Join(partitions, ", ") + " " + over
+	}
+
+	for _, w := range aggQuery.Windows {
+		var expression string
+		if fn, ok := windowFunctions[w.Func]; ok {
+			expression = fmt.Sprintf("%s() OVER (%s)", fn, over)
+		} else {
+			expression = fmt.Sprintf("%s(%s) OVER (%s ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)",
+				runningFunctions[w.Func], expressions[w.Column], over)
+		}
+		selectList = append(selectList, expression+" AS "+exp.dialect.QuoteIdent(w.Alias()))
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectList, ", "), exp.quote(table))
+	if len(aggQuery.GroupBy) > 0 {
+		query += " GROUP BY " + exp.quoteColumns(table, aggQuery.GroupBy)
+	}
+	if orderBy != "" {
+		query += " ORDER BY " + orderBy
+	}
+	query += " LIMIT ? OFFSET ?"
+
+	return query, []any{aggQuery.Pagination.Limit, aggQuery.Pagination.Offset}
+}
+
+// AggregateRequest is the body of POST /{table}/aggregate, the JSON form of the _aggregate parameters:
+// {"group_by": ["status"], "aggregates": [{"func": "sum", "column": "amount"}], "order_by": ["-sum_amount"]}.
+type AggregateRequest struct {
+	GroupBy    []string        `json:"group_by"`
+	Aggregates []AggregateFunc `json:"aggregates"`
+	OrderBy    []string        `json:"order_by"`
+	Limit      *int            `json:"limit"`
+	Offset     *int            `json:"offset"`
+}
+
+type AggregateFunc struct {
+	Func   string `json:"func"`
+	Column string `json:"column"`
+}
+
+// values returns the request as the query parameters of GET /{table}/_aggregate.
+func (req AggregateRequest) values() url.Values {
+	query := url.Values{}
+	if len(req.GroupBy) > 0 {
+		query.Set("group_by", strings.Join(req.GroupBy, ","))
+	}
+
+	aggregates := make([]string, len(req.Aggregates))
+	for i, a := range req.Aggregates {
+		aggregates[i] = a.Func
+		if a.Column != "" {
+			aggregates[i] += ":" + a.Column
+		}
+	}
+	if len(aggregates) > 0 {
+		query.Set("agg", strings.Join(aggregates, ","))
+	}
+
+	if len(req.OrderBy) > 0 {
+		query.Set("order_by", strings.Join(req.OrderBy, ","))
+	}
+	if req.Limit != nil {
+		query.Set("limit", strconv.Itoa(*req.Limit))
+	}
+	if req.Offset != nil {
+		query.Set("offset", strconv.Itoa(*req.Offset))
+	}
+
+	return query
+}
+
+func (exp Explorer) handlerGetAggregate(w http.ResponseWriter, r *http.Request) {
+	exp.writeAggregate(w, r, r.URL.Query())
+}
+
+// handlerPostAggregate serves POST /{table}/aggregate for clients building reports from JSON. It only
+// reads, see isReadRequest; like /{table}/bulk it shadows a record with the key "aggregate".
+func (exp Explorer) handlerPostAggregate(w http.ResponseWriter, r *http.Request) {
+	req := AggregateRequest{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid aggregate request"))
+		return
+	}
+
+	if len(req.GroupBy) == 0 && len(req.Aggregates) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("group_by or aggregates required"))
+		return
+	}
+
+	exp.writeAggregate(w, r, req.values())
+}
+
+func (exp Explorer) writeAggregate(w http.ResponseWriter, r *http.Request, values url.Values) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if !exp.requireAggregate(w, r) {
+		return
+	}
+
+	aggQuery, err := exp.parseAggregateQuery(tableName, values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	query, args := exp.buildAggregateQuery(tableName, aggQuery)
+	rows, release, err := exp.queryContext(r.Context(), query, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer release()
+	defer rows.Close()
+
+	result, err := exp.scanRows(rows)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, GetTableItemsResponse{Records: result})
+}
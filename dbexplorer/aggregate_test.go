@@ -0,0 +1,91 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestBuildAggregateQuery(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"orders": {
+			PrimaryKey: "id",
+			Columns: []ColumnInfo{
+				{Name: "id", DataType: "int"},
+				{Name: "customer", DataType: "varchar"},
+				{Name: "amount", DataType: "decimal"},
+			},
+		},
+	})
+
+	cases := []struct {
+		query    string
+		expected string
+		err      string
+	}{
+		{
+			query:    "group_by=customer&agg=count,sum:amount&window=rank&order_by=-sum_amount",
+			expected: "SELECT `customer`, COUNT(*) AS `count`, SUM(`amount`) AS `sum_amount`, RANK() OVER (ORDER BY SUM(`amount`) DESC) AS `rank` FROM `orders` GROUP BY `customer` ORDER BY SUM(`amount`) DESC LIMIT ? OFFSET ?",
+		},
+		{
+			query:    "window=running_sum:amount,row_number&partition_by=customer&order_by=id",
+			expected: "SELECT `orders`.*, SUM(`amount`) OVER (PARTITION BY `customer` ORDER BY `id` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS `running_sum_amount`, ROW_NUMBER() OVER (PARTITION BY `customer` ORDER BY `id`) AS `row_number` FROM `orders` ORDER BY `id` LIMIT ? OFFSET ?",
+		},
+		{query: "window=rank", err: "window functions require order_by"},
+		{query: "agg=sum:customer", err: "column customer is not numeric"},
+		{query: "group_by=customer&agg=count&order_by=amount", err: "unknown order column amount"},
+		{query: "window=lag&order_by=id", err: "unknown window lag"},
+	}
+
+	for _, c := range cases {
+		query, _ := url.ParseQuery(c.query)
+		aggQuery, err := exp.parseAggregateQuery("orders", query)
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("%s: expected error %q, got %v", c.query, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.query, err)
+			continue
+		}
+
+		if got, _ := exp.buildAggregateQuery("orders", aggQuery); got != c.expected {
+			t.Errorf("%s:\nexpected %s\ngot      %s", c.query, c.expected, got)
+		}
+	}
+}
+
+func TestAggregateRequest(t *testing.T) {
+	limit := 10
+	req := AggregateRequest{
+		GroupBy:    []string{"customer"},
+		Aggregates: []AggregateFunc{{Func: "count"}, {Func: "sum", Column: "amount"}},
+		OrderBy:    []string{"-sum_amount"},
+		Limit:      &limit,
+	}
+
+	expected := "agg=count%2Csum%3Aamount&group_by=customer&limit=10&order_by=-sum_amount"
+	if query := req.values().Encode(); query != expected {
+		t.Errorf("expected %s, got %s", expected, query)
+	}
+
+	cases := []struct {
+		method string
+		path   string
+		read   bool
+	}{
+		{http.MethodGet, "/orders", true},
+		{http.MethodPost, "/orders/aggregate", true},
+		{http.MethodPost, "/orders/1", false},
+		{http.MethodPut, "/orders/aggregate", false},
+	}
+
+	for _, c := range cases {
+		if read := isReadRequest(httptest.NewRequest(c.method, c.path, nil)); read != c.read {
+			t.Errorf("%s %s: expected read %v", c.method, c.path, c.read)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package dbexplorer
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// APIKey is a static key for services calling the API, sent in the X-API-Key header.
+type APIKey struct {
+	Name  string
+	Roles []string
+}
+
+// WithAPIKeys authenticates requests by the keys of the map, e.g. configured from the environment.
+func WithAPIKeys(keys map[string]APIKey) Option {
+	hashed := make(map[[sha256.Size]byte]APIKey, len(keys))
+	for key, apiKey := range keys {
+		hashed[sha256.Sum256([]byte(key))] = apiKey
+	}
+
+	return WithAuthenticator(AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		key := r.Header.Get(apiKeyHeader)
+		if key == "" {
+			return nil, nil
+		}
+
+		// keys are looked up by hash so the lookup time doesn't depend on the matching prefix
+		apiKey, ok := hashed[sha256.Sum256([]byte(key))]
+		if !ok {
+			return nil, fmt.Errorf("invalid api key")
+		}
+
+		return &Principal{Name: apiKey.Name, Roles: apiKey.Roles}, nil
+	}))
+}
+
+// basicAuthenticator checks HTTP basic auth credentials against local users.
+type basicAuthenticator struct {
+	users map[string]LocalUser
+	realm string
+}
+
+// WithBasicAuth authenticates requests with HTTP basic auth of the local users.
+func WithBasicAuth(realm string, users map[string]LocalUser) Option {
+	return func(exp *Explorer) error {
+		if err := checkUsers(users); err != nil {
+			return err
+		}
+
+		return WithAuthenticator(&basicAuthenticator{users: users, realm: realm})(exp)
+	}
+}
+
+func (a *basicAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, nil
+	}
+
+	user, exists := a.users[username]
+	if !exists || !checkPassword(user.Password, password) {
+		return nil, fmt.Errorf("invalid credentials")
+	}
+
+	return &Principal{Name: username, Roles: user.Roles}, nil
+}
+
+func (a *basicAuthenticator) Challenge() string {
+	return fmt.Sprintf("Basic realm=%q", a.realm)
+}
+
+// WithAuthExemptPaths lets requests to the paths through without credentials, e.g. "/_health" for probes.
+func WithAuthExemptPaths(paths ...string) Option {
+	return func(exp *Explorer) error {
+		for _, path := range paths {
+			exp.authExemptPaths[path] = true
+		}
+		return nil
+	}
+}
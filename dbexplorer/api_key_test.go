@@ -0,0 +1,64 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyAndBasicAuth(t *testing.T) {
+	password, err := hashPassword("love", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Explorer{authExemptPaths: make(map[string]bool)}
+	if err := WithBasicAuth("db_explorer", map[string]LocalUser{"alice": {Password: "love"}})(&exp); err == nil {
+		t.Errorf("expected a plain text password to be rejected")
+	}
+
+	for _, opt := range []Option{
+		WithAPIKeys(map[string]APIKey{"secret-key": {Name: "billing", Roles: []string{"reader"}}}),
+		WithBasicAuth("db_explorer", map[string]LocalUser{"alice": {Password: password}}),
+		WithAuthExemptPaths("/_health"),
+	} {
+		if err := opt(&exp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := exp.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal := PrincipalFromContext(r.Context()); principal != nil {
+			w.Write([]byte(principal.Name))
+		}
+	}))
+
+	serve := func(path string, setup func(r *http.Request)) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		setup(r)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve("/items", func(r *http.Request) { r.Header.Set(apiKeyHeader, "secret-key") }); w.Code != http.StatusOK || w.Body.String() != "billing" {
+		t.Errorf("api key: unexpected %d %s", w.Code, w.Body.String())
+	}
+
+	if w := serve("/items", func(r *http.Request) { r.Header.Set(apiKeyHeader, "wrong") }); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong api key: expected 401, got %d", w.Code)
+	}
+
+	if w := serve("/items", func(r *http.Request) { r.SetBasicAuth("alice", "love") }); w.Code != http.StatusOK || w.Body.String() != "alice" {
+		t.Errorf("basic auth: unexpected %d %s", w.Code, w.Body.String())
+	}
+
+	w := serve("/items", func(r *http.Request) {})
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != `Basic realm="db_explorer"` {
+		t.Errorf("anonymous: unexpected %d %v", w.Code, w.Header())
+	}
+
+	if w := serve("/_health", func(r *http.Request) {}); w.Code != http.StatusOK {
+		t.Errorf("exempt path: expected 200, got %d", w.Code)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package dbexplorer
 
 import (
 	"database/sql"
@@ -29,6 +29,10 @@ type Case struct {
 }
 
 var (
+	// DSN это соединение с базой
+	// docker run -p 3306:3306 -v $(PWD):/docker-entrypoint-initdb.d -e MYSQL_ROOT_PASSWORD=1234 -e MYSQL_DATABASE=golang -d mysql
+	DSN = "root:1234@tcp(localhost:3306)/golang?charset=utf8"
+
 	client = &http.Client{Timeout: time.Second}
 )
 
@@ -97,7 +101,7 @@ func TestApis(t *testing.T) {
 	// возможно вам будет удобно закомментировать это чтобы смотреть результат после теста
 	// defer CleanupTestApis(db)
 
-	handler, err := NewDbExplorer(db)
+	handler, err := New(db)
 	if err != nil {
 		panic(err)
 	}
@@ -173,6 +177,54 @@ func TestApis(t *testing.T) {
 				},
 			},
 		},
+		Case{
+			Path:  "/items",
+			Query: "title=memcache",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":          2,
+							"title":       "memcache",
+							"description": "Рассказать про мемкеш с примером использования",
+							"updated":     nil,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/items",
+			Query: "sort=-id&limit=1",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":          2,
+							"title":       "memcache",
+							"description": "Рассказать про мемкеш с примером использования",
+							"updated":     nil,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/items",
+			Query:  "sort=unknown_column",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "unknown sort column unknown_column",
+			},
+		},
+		Case{
+			Path:   "/items",
+			Query:  "unknown_column=1",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "unknown column unknown_column",
+			},
+		},
 		Case{
 			Path: "/items/1",
 			Result: CR{
@@ -225,6 +277,39 @@ func TestApis(t *testing.T) {
 				},
 			},
 		},
+		Case{
+			Path: "/items/3/_exists",
+			Result: CR{
+				"response": CR{
+					"exists": true,
+				},
+			},
+		},
+		Case{
+			Path: "/items/100500/_exists",
+			Result: CR{
+				"response": CR{
+					"exists": false,
+				},
+			},
+		},
+		Case{
+			Path: "/items/count",
+			Result: CR{
+				"response": CR{
+					"count": 3,
+				},
+			},
+		},
+		Case{
+			Path:  "/items/count",
+			Query: "id=1",
+			Result: CR{
+				"response": CR{
+					"count": 1,
+				},
+			},
+		},
 		Case{
 			Path:   "/items/3",
 			Method: http.MethodPost,
@@ -234,6 +319,8 @@ func TestApis(t *testing.T) {
 			Result: CR{
 				"response": CR{
 					"updated": 1,
+					"matched": 1,
+					"changed": 1,
 				},
 			},
 		},
@@ -261,6 +348,8 @@ func TestApis(t *testing.T) {
 			Result: CR{
 				"response": CR{
 					"updated": 1,
+					"matched": 1,
+					"changed": 1,
 				},
 			},
 		},
@@ -288,6 +377,8 @@ func TestApis(t *testing.T) {
 			Result: CR{
 				"response": CR{
 					"updated": 1,
+					"matched": 1,
+					"changed": 1,
 				},
 			},
 		},
@@ -314,7 +405,13 @@ func TestApis(t *testing.T) {
 				"id": 4, // primary key нельзя обновлять у существующей записи
 			},
 			Result: CR{
-				"error": "field id have invalid type",
+				"error": "validation failed",
+				"errors": []CR{
+					CR{"field": "id", "reason": "invalid type"},
+				},
+				"fields": CR{
+					"id": "invalid type",
+				},
 			},
 		},
 		Case{
@@ -325,7 +422,13 @@ func TestApis(t *testing.T) {
 				"title": 42,
 			},
 			Result: CR{
-				"error": "field title have invalid type",
+				"error": "validation failed",
+				"errors": []CR{
+					CR{"field": "title", "reason": "invalid type"},
+				},
+				"fields": CR{
+					"title": "invalid type",
+				},
 			},
 		},
 		Case{
@@ -336,7 +439,13 @@ func TestApis(t *testing.T) {
 				"title": nil,
 			},
 			Result: CR{
-				"error": "field title have invalid type",
+				"error": "validation failed",
+				"errors": []CR{
+					CR{"field": "title", "reason": "invalid type"},
+				},
+				"fields": CR{
+					"title": "invalid type",
+				},
 			},
 		},
 
@@ -348,7 +457,34 @@ func TestApis(t *testing.T) {
 				"updated": 42,
 			},
 			Result: CR{
-				"error": "field updated have invalid type",
+				"error": "validation failed",
+				"errors": []CR{
+					CR{"field": "updated", "reason": "invalid type"},
+				},
+				"fields": CR{
+					"updated": "invalid type",
+				},
+			},
+		},
+		// все ошибки валидации возвращаются разом
+		Case{
+			Path:   "/items/3",
+			Method: http.MethodPost,
+			Status: http.StatusBadRequest,
+			Body: CR{
+				"title":   42,
+				"updated": 42,
+			},
+			Result: CR{
+				"error": "validation failed",
+				"errors": []CR{
+					CR{"field": "title", "reason": "invalid type"},
+					CR{"field": "updated", "reason": "invalid type"},
+				},
+				"fields": CR{
+					"title":   "invalid type",
+					"updated": "invalid type",
+				},
 			},
 		},
 
@@ -406,6 +542,8 @@ func TestApis(t *testing.T) {
 			Result: CR{
 				"response": CR{
 					"updated": 1,
+					"matched": 1,
+					"changed": 1,
 				},
 			},
 		},
@@ -433,7 +571,13 @@ func TestApis(t *testing.T) {
 				"user_id": 1, // primary key нельзя обновлять у существующей записи
 			},
 			Result: CR{
-				"error": "field user_id have invalid type",
+				"error": "validation failed",
+				"errors": []CR{
+					CR{"field": "user_id", "reason": "invalid type"},
+				},
+				"fields": CR{
+					"user_id": "invalid type",
+				},
 			},
 		},
 		// не забываем про sql-инъекции
@@ -512,7 +656,7 @@ func runCases(t *testing.T, ts *httptest.Server, db *sql.DB, cases []Case) {
 		caseName := fmt.Sprintf("case %d: [%s] %s %s", idx, item.Method, item.Path, item.Query)
 
 		// если у вас случилась это ошибка - значит вы не делаете где-то rows.Close и у вас текут соединения с базой
-		// если такое случилось на первом тесте - значит вы не закрываете коннект где-то при иницаилизации в NewDbExplorer
+		// если такое случилось на первом тесте - значит вы не закрываете коннект где-то при иницаилизации в New
 		if db.Stats().OpenConnections != 1 {
 			t.Fatalf("[%s] you have %d open connections, must be 1", caseName, db.Stats().OpenConnections)
 		}
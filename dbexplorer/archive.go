@@ -0,0 +1,201 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	archiveSuffix           = "_archive"
+	defaultArchiveBatchSize = 1000
+	maxArchiveBatchSize     = 10000
+)
+
+// ArchiveRequest selects the rows to archive with the filters of the list endpoint,
+// e.g. {"filter": {"status": "closed"}, "batch_size": 500}.
+type ArchiveRequest struct {
+	Filter    map[string]any `json:"filter"`
+	BatchSize int            `json:"batch_size"`
+}
+
+type ArchiveResponse struct {
+	Job Job `json:"job"`
+}
+
+// archiveBatch moves up to batchSize matching rows to the archive table in one transaction
+// and returns the number of moved rows. With the audit log the rows are deleted as the principal.
+func (exp Explorer) archiveBatch(table string, archive string, where string, args []any, batchSize int, principal *Principal) (int64, error) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	primaryKey := exp.quoteColumn(table, schema.PrimaryKey)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %d FOR UPDATE",
+		primaryKey, exp.quote(table), where, primaryKey, batchSize)
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]any, 0, batchSize)
+	for rows.Next() {
+		var id any
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	columnNames := make([]string, len(schema.Columns))
+	for i, c := range schema.Columns {
+		columnNames[i] = c.Name
+	}
+	columns := exp.quoteColumns(table, columnNames)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE %s IN (%s)",
+		exp.quote(archive), columns, columns, exp.quote(table), primaryKey, placeholders)
+	if _, err := tx.Exec(insert, ids...); err != nil {
+		return 0, err
+	}
+
+	var moved int64
+	if exp.auditLog {
+		// row by row, so every archived row gets its audit entry
+		for _, id := range ids {
+			op := writeOp{
+				Op:         writeDelete,
+				Table:      table,
+				PrimaryKey: schema.PrimaryKey,
+				ID:         normalizeValue(id),
+			}
+
+			written, err := exp.executeWrite(context.Background(), tx, op, auditMeta{Principal: principal})
+			if err != nil {
+				return 0, err
+			}
+			moved += written.Affected
+		}
+	} else {
+		result, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", exp.quote(table), primaryKey, placeholders), ids...)
+		if err != nil {
+			return 0, err
+		}
+
+		moved, err = result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		exp.countAffected(table, writeDelete, moved)
+	}
+
+	return moved, tx.Commit()
+}
+
+// handlerArchive starts a job moving the rows matching the filter to <table>_archive,
+// which is created like the table if absent.
+func (exp Explorer) handlerArchive(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("archive is not allowed for writes requiring approval"))
+		return
+	}
+
+	var req ArchiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.Filter) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("filter is required"))
+		return
+	}
+
+	if req.BatchSize == 0 {
+		req.BatchSize = defaultArchiveBatchSize
+	}
+	if req.BatchSize < 0 || req.BatchSize > maxArchiveBatchSize {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("batch_size must be between 1 and %d", maxArchiveBatchSize))
+		return
+	}
+
+	where, args, err := exp.parseWhere(tableName, req.Filter)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	archive := tableName + archiveSuffix
+	exists, err := exp.tableExists(archive)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	if !exists {
+		if _, err := exp.db().Exec(exp.dialect.CreateTableLike(archive, exp.dbName(tableName))); err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		if err := exp.recordDDL(principal, archive); err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+
+	job, err := exp.jobs.start("archive", tableName, principal, func(progress jobProgress) error {
+		var total int64
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", exp.quote(tableName), where)
+		if err := exp.db().QueryRow(countQuery, args...).Scan(&total); err != nil {
+			return err
+		}
+		progress.SetTotal(total)
+
+		for {
+			moved, err := exp.archiveBatch(tableName, archive, where, args, req.BatchSize, principal)
+			if err != nil {
+				return err
+			}
+
+			progress.Add(moved)
+			if moved < int64(req.BatchSize) {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	writeResponse(w, ArchiveResponse{Job: job})
+}
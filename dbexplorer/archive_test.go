@@ -0,0 +1,36 @@
+package dbexplorer
+
+import "testing"
+
+func TestArchiveBatchAudit(t *testing.T) {
+	db := openTestDB(t, "items"+archiveSuffix, auditTable)
+	exp, err := New(db, WithAuditLog())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(exp.dialect.CreateTableLike("items"+archiveSuffix, "items")); err != nil {
+		t.Fatal(err)
+	}
+
+	where, args, err := exp.parseWhere("items", map[string]any{"id": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := exp.archiveBatch("items", "items"+archiveSuffix, where, args, 10, &Principal{Name: "alice"})
+	if err != nil || moved != 1 {
+		t.Fatalf("expected one archived row, got %d %v", moved, err)
+	}
+
+	var archived int
+	if err := db.QueryRow("SELECT COUNT(*) FROM items" + archiveSuffix + " WHERE id = 1").Scan(&archived); err != nil || archived != 1 {
+		t.Errorf("expected the row in the archive, got %d %v", archived, err)
+	}
+
+	var principal, operation, recordID string
+	err = db.QueryRow("SELECT principal, operation, record_id FROM "+auditTable+" WHERE table_name = 'items'").Scan(&principal, &operation, &recordID)
+	if err != nil || principal != "alice" || operation != writeDelete || recordID != "1" {
+		t.Errorf("unexpected audit entry %s %s %s %v", principal, operation, recordID, err)
+	}
+}
@@ -0,0 +1,43 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var errAsOfWithoutAudit = errors.New("as_of requires the audit log")
+
+// itemAsOf reconstructs the record at the time from the audit log: the state before the first write after it,
+// or the current row when there was none. It returns nil when the record didn't exist at that time.
+func (exp Explorer) itemAsOf(table string, pkName string, pkValue any, at time.Time) (map[string]any, error) {
+	if !exp.auditLog {
+		return nil, errAsOfWithoutAudit
+	}
+
+	var before sql.NullString
+	err := exp.db().QueryRow(`SELECT before_data FROM `+auditTable+` WHERE table_name = ? AND record_id = ? AND created_at > ? ORDER BY id LIMIT 1`,
+		table, fmt.Sprint(pkValue), at.UTC()).Scan(&before)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		item, err := exp.getItem(exp.db(), table, pkName, pkValue)
+		if err != nil {
+			return nil, nil
+		}
+		return item, nil
+	case err != nil:
+		return nil, err
+	case !before.Valid:
+		return nil, nil
+	}
+
+	item := make(map[string]any)
+	if err := json.Unmarshal([]byte(before.String), &item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
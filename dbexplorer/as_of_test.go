@@ -0,0 +1,14 @@
+package dbexplorer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestItemAsOfRequiresAudit(t *testing.T) {
+	exp := Explorer{}
+	if _, err := exp.itemAsOf("items", "id", 1, time.Now()); !errors.Is(err, errAsOfWithoutAudit) {
+		t.Errorf("expected errAsOfWithoutAudit, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const auditTable = metaTablePrefix + "audit"
+
+type AuditEntry struct {
+	Table     string
+	RecordID  any
+	Operation string
+	Before    map[string]any
+	After     map[string]any
+}
+
+// auditMeta describes who made the change.
+type auditMeta struct {
+	Principal *Principal
+	ChangeID  any
+}
+
+// WithAuditLog records every executed write with the record state before and after it
+// into the _explorer_audit table.
+func WithAuditLog() Option {
+	return func(exp *Explorer) error {
+		exp.metaTables = append(exp.metaTables, metaTable{
+			Name: auditTable,
+			Columns: []metaColumn{
+				{Name: "id", Kind: "serial"},
+				{Name: "table_name", Kind: "string"},
+				{Name: "record_id", Kind: "string"},
+				{Name: "operation", Kind: "string"},
+				{Name: "principal", Kind: "string", Nullable: true},
+				{Name: "change_id", Kind: "bigint", Nullable: true},
+				{Name: "before_data", Kind: "text", Nullable: true},
+				{Name: "after_data", Kind: "text", Nullable: true},
+				{Name: "created_at", Kind: "time"},
+			},
+			PrimaryKey: "id",
+			Indexes:    [][]string{{"table_name", "record_id", "id"}},
+		})
+		exp.auditLog = true
+		return nil
+	}
+}
+
+func principalName(principal *Principal) any {
+	if principal == nil {
+		return nil
+	}
+
+	return principal.Name
+}
+
+func marshalNullable(data map[string]any) (any, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(encoded), nil
+}
+
+func (exp Explorer) writeAudit(q queryer, entry AuditEntry, meta auditMeta) error {
+	before, err := marshalNullable(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := marshalNullable(entry.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(`INSERT INTO `+auditTable+` (table_name, record_id, operation, principal, change_id, before_data, after_data, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Table, fmt.Sprint(entry.RecordID), entry.Operation, principalName(meta.Principal), meta.ChangeID, before, after, time.Now().UTC())
+
+	return err
+}
@@ -0,0 +1,114 @@
+package dbexplorer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// statusError carries the http status an error has to be reported with.
+type statusError struct {
+	Status int
+	Err    error
+}
+
+func (e statusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e statusError) Unwrap() error {
+	return e.Err
+}
+
+// Authenticator resolves the principal of a request.
+// It returns nil principal and nil error when the request has no credentials it understands,
+// so the next authenticator can try.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) {
+	return f(r)
+}
+
+// WithAuthenticator enables authentication: requests not recognized by any authenticator are rejected with 401.
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(exp *Explorer) error {
+		exp.authenticators = append(exp.authenticators, authenticator)
+		return nil
+	}
+}
+
+func (exp Explorer) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exp.authExemptPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// checked before the credentials, so guessing them is as slow as the failures are limited
+		if !exp.checkAuthFailures(w, r) {
+			return
+		}
+
+		for _, authenticator := range exp.authenticators {
+			principal, err := authenticator.Authenticate(r)
+			if err != nil {
+				status := http.StatusUnauthorized
+				var statusErr statusError
+				if errors.As(err, &statusErr) {
+					status = statusErr.Status
+				}
+				if status == http.StatusUnauthorized {
+					exp.recordAuthFailure(r)
+				}
+
+				writeError(w, status, err)
+				return
+			}
+
+			if principal != nil {
+				next.ServeHTTP(w, r.WithContext(ContextWithPrincipal(r.Context(), principal)))
+				return
+			}
+		}
+
+		for _, authenticator := range exp.authenticators {
+			if challenger, ok := authenticator.(interface{ Challenge() string }); ok {
+				w.Header().Add("WWW-Authenticate", challenger.Challenge())
+			}
+		}
+
+		exp.recordAuthFailure(r)
+		writeError(w, http.StatusUnauthorized, LocalizedError{Code: MsgUnauthorized})
+	})
+}
+
+// writeForbidden rejects a request lacking a permission. Anonymous requests get 401 when authentication
+// is enabled, so the client knows credentials may help, authenticated principals get 403.
+func (exp Explorer) writeForbidden(w http.ResponseWriter, r *http.Request, err error) {
+	if PrincipalFromContext(r.Context()) == nil && len(exp.authenticators) > 0 {
+		writeError(w, http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+		return
+	}
+
+	writeError(w, http.StatusForbidden, err)
+}
+
+// writeFormError reports a form error of processForm, columns the principal can't write are reported like writeForbidden.
+func (exp Explorer) writeFormError(w http.ResponseWriter, r *http.Request, err error) {
+	if formErrorStatus(err) == http.StatusForbidden {
+		exp.writeForbidden(w, r, err)
+		return
+	}
+
+	var errs ValidationErrors
+	if exp.strictStatus && errors.As(err, &errs) {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writeError(w, formErrorStatus(err), err)
+}
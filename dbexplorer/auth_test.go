@@ -0,0 +1,39 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteForbidden(t *testing.T) {
+	withAuth := Explorer{
+		authenticators: []Authenticator{AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+			return nil, nil
+		})},
+	}
+
+	cases := []struct {
+		exp       Explorer
+		principal *Principal
+		expected  int
+	}{
+		{withAuth, nil, http.StatusUnauthorized},
+		{withAuth, &Principal{Name: "bob"}, http.StatusForbidden},
+		{Explorer{}, nil, http.StatusForbidden},
+	}
+
+	for idx, c := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/_tokens", nil)
+		if c.principal != nil {
+			r = r.WithContext(ContextWithPrincipal(r.Context(), c.principal))
+		}
+
+		w := httptest.NewRecorder()
+		c.exp.writeForbidden(w, r, fmt.Errorf("forbidden"))
+		if w.Code != c.expected {
+			t.Errorf("case %d: expected %d, got %d", idx, c.expected, w.Code)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+)
+
+// Backend is the storage the API reads and writes records in. The explorer's own implementation runs
+// the SQL of its dialect; see Explorer.Backend. Another one is plugged in with WithBackend.
+type Backend interface {
+	ListTables(ctx context.Context) ([]string, error)
+	Schema(ctx context.Context, table string) (*TableSchema, error)
+	// Query returns the records of a list request; a filter on the primary key selects a single record.
+	Query(ctx context.Context, table string, query ListQuery) ([]map[string]any, error)
+	// Insert creates the record and returns its primary key.
+	Insert(ctx context.Context, table string, record map[string]any) (any, error)
+	Update(ctx context.Context, table string, id any, record map[string]any) (int64, error)
+	Delete(ctx context.Context, table string, id any) (int64, error)
+}
+
+// sqlBackend is the Backend of the explorer's database. It serves the tables of the schema cache and
+// writes without the audit log, approvals and permissions, which are applied by the HTTP handlers.
+type sqlBackend struct {
+	exp Explorer
+}
+
+// WithBackend serves the tables from backend: the table list and schemas, lists and single records,
+// creates, updates and deletes. The meta tables and the endpoints built on SQL, like /_tx, /_query,
+// bulk writes and imports, still use the database passed to New. The audit log needs the records in
+// that database and can't be combined with another backend.
+func WithBackend(backend Backend) Option {
+	return func(exp *Explorer) error {
+		if backend == nil {
+			return fmt.Errorf("backend is nil")
+		}
+
+		exp.backend = backend
+		return nil
+	}
+}
+
+// Backend returns the backend of WithBackend or else the SQL core of the explorer, e.g. to reuse it
+// from code next to the HTTP API.
+func (exp Explorer) Backend() Backend {
+	if exp.backend != nil {
+		return exp.backend
+	}
+
+	return sqlBackend{exp: exp}
+}
+
+// backendItem reads a single record through the backend, nil when there is none.
+func (exp Explorer) backendItem(ctx context.Context, table string, primaryKey string, id any, columns []string) (map[string]any, error) {
+	items, err := exp.Backend().Query(ctx, table, ListQuery{
+		Pagination: Pagination{Limit: 1},
+		Filters:    []Filter{{Column: primaryKey, Operator: opEq, Value: formatParam(id)}},
+		Fields:     columns,
+	})
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	return items[0], nil
+}
+
+// executeBackendWrite is executeWrite for the backend of WithBackend.
+func (exp Explorer) executeBackendWrite(ctx context.Context, op writeOp) (writeResult, error) {
+	result := writeResult{ID: op.ID}
+	backend := exp.Backend()
+
+	var err error
+	switch op.Op {
+	case writeCreate:
+		result.ID, err = backend.Insert(ctx, op.Table, op.Form)
+		result.Affected = 1
+	case writeUpdate:
+		result.Affected, err = backend.Update(ctx, op.Table, op.ID, op.Form)
+	case writeDelete:
+		result.Affected, err = backend.Delete(ctx, op.Table, op.ID)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	exp.countAffected(op.Table, op.Op, result.Affected)
+	return result, nil
+}
+
+func (b sqlBackend) ListTables(ctx context.Context) ([]string, error) {
+	return b.exp.tableNames(), nil
+}
+
+func (b sqlBackend) Schema(ctx context.Context, table string) (*TableSchema, error) {
+	return b.exp.getTableSchema(table)
+}
+
+func (b sqlBackend) Query(ctx context.Context, table string, query ListQuery) ([]map[string]any, error) {
+	return b.exp.getTableItems(ctx, table, query)
+}
+
+func (b sqlBackend) primaryKey(table string) (string, error) {
+	schema, err := b.exp.getTableSchema(table)
+	if err != nil {
+		return "", err
+	}
+
+	if schema.PrimaryKey == "" {
+		return "", fmt.Errorf("table %s has no primary key", table)
+	}
+
+	return schema.PrimaryKey, nil
+}
+
+func (b sqlBackend) Insert(ctx context.Context, table string, record map[string]any) (any, error) {
+	primaryKey, err := b.primaryKey(table)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.exp.createItem(b.exp.db(), table, record, nil, primaryKey)
+}
+
+func (b sqlBackend) Update(ctx context.Context, table string, id any, record map[string]any) (int64, error) {
+	primaryKey, err := b.primaryKey(table)
+	if err != nil {
+		return 0, err
+	}
+
+	return b.exp.updateItem(b.exp.db(), table, record, nil, primaryKey, id)
+}
+
+func (b sqlBackend) Delete(ctx context.Context, table string, id any) (int64, error) {
+	primaryKey, err := b.primaryKey(table)
+	if err != nil {
+		return 0, err
+	}
+
+	return b.exp.deleteItem(b.exp.db(), table, primaryKey, id)
+}
@@ -0,0 +1,179 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSQLBackendSchema(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id"},
+		"logs":  {},
+	})
+	backend := exp.Backend()
+
+	tables, err := backend.ListTables(context.Background())
+	if err != nil || len(tables) != 2 {
+		t.Errorf("unexpected tables %v, %v", tables, err)
+	}
+
+	if schema, err := backend.Schema(context.Background(), "items"); err != nil || schema.PrimaryKey != "id" {
+		t.Errorf("unexpected schema %+v, %v", schema, err)
+	}
+
+	if _, err := backend.Delete(context.Background(), "logs", 1); err == nil {
+		t.Errorf("expected error for a table without primary key")
+	}
+}
+
+// memoryBackend keeps the records of a single table with an id primary key.
+type memoryBackend struct {
+	records map[string]map[string]any
+	nextID  int64
+}
+
+func (b *memoryBackend) ListTables(ctx context.Context) ([]string, error) {
+	return []string{"items"}, nil
+}
+
+func (b *memoryBackend) Schema(ctx context.Context, table string) (*TableSchema, error) {
+	if table != "items" {
+		return nil, fmt.Errorf("unknown table %s", table)
+	}
+
+	return &TableSchema{PrimaryKey: "id", Columns: []ColumnInfo{
+		{Name: "id", DataType: "int", Extra: "auto_increment"},
+		{Name: "title", DataType: "varchar"},
+	}}, nil
+}
+
+func (b *memoryBackend) Query(ctx context.Context, table string, query ListQuery) ([]map[string]any, error) {
+	items := make([]map[string]any, 0)
+	for id, record := range b.records {
+		if len(query.Filters) == 0 || query.Filters[0].Value == id {
+			items = append(items, record)
+		}
+	}
+
+	return items, nil
+}
+
+func (b *memoryBackend) Insert(ctx context.Context, table string, record map[string]any) (any, error) {
+	b.nextID++
+	record["id"] = b.nextID
+	b.records[fmt.Sprint(b.nextID)] = record
+
+	return b.nextID, nil
+}
+
+func (b *memoryBackend) Update(ctx context.Context, table string, id any, record map[string]any) (int64, error) {
+	existing, ok := b.records[fmt.Sprint(id)]
+	if !ok {
+		return 0, nil
+	}
+
+	for column, value := range record {
+		existing[column] = value
+	}
+
+	return 1, nil
+}
+
+func (b *memoryBackend) Delete(ctx context.Context, table string, id any) (int64, error) {
+	if _, ok := b.records[fmt.Sprint(id)]; !ok {
+		return 0, nil
+	}
+
+	delete(b.records, fmt.Sprint(id))
+	return 1, nil
+}
+
+func TestWithBackend(t *testing.T) {
+	backend := &memoryBackend{records: map[string]map[string]any{
+		"1": {"id": int64(1), "title": "a"},
+	}}
+
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "int"}, {Name: "title", DataType: "varchar"}}},
+	})
+	if err := WithBackend(backend)(&exp); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	exp.handlerGetTableItem(w, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"title":"a"`) {
+		t.Errorf("expected the record of the backend, got %d %s", w.Code, w.Body.String())
+	}
+
+	op := writeOp{Op: writeUpdate, Table: "items", PrimaryKey: "id", ID: int64(1), Form: map[string]any{"title": "b"}}
+	if written, err := exp.runWrite(context.Background(), nil, op); err != nil || written.Affected != 1 || backend.records["1"]["title"] != "b" {
+		t.Errorf("expected the update to reach the backend, got %+v, %v", written, err)
+	}
+
+	w = httptest.NewRecorder()
+	exp.handlerDeleteItem(w, httptest.NewRequest(http.MethodDelete, "/items/1", nil))
+	if w.Code != http.StatusOK || len(backend.records) != 0 {
+		t.Errorf("expected the delete to reach the backend, got %d %s", w.Code, w.Body.String())
+	}
+
+	if exists, err := exp.itemExists(context.Background(), nil, "items", "1"); err != nil || exists {
+		t.Errorf("expected the deleted record to be gone, got %v, %v", exists, err)
+	}
+
+	if err := WithBackend(nil)(&exp); err == nil {
+		t.Errorf("expected an error for a nil backend")
+	}
+}
+
+func TestWithBackendServesHTTP(t *testing.T) {
+	// nothing listens on the port, New fails if it reads the schema from the database
+	db, err := sql.Open("mysql", "root@tcp(127.0.0.1:1)/none?timeout=1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	backend := &memoryBackend{records: map[string]map[string]any{"1": {"id": int64(1), "title": "a"}}, nextID: 1}
+	handler, err := New(db, WithBackend(backend))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		method   string
+		path     string
+		body     string
+		expected string
+	}{
+		{http.MethodGet, "/", "", `{"response":{"tables":["items"]}}`},
+		{http.MethodGet, "/items/1", "", `{"response":{"record":{"id":1,"title":"a"}}}`},
+		{http.MethodPut, "/items/", `{"title": "b"}`, `{"response":{"id":2}}`},
+		{http.MethodPost, "/items/2", `{"title": "c"}`, `{"response":{"updated":1,"matched":1,"changed":1}}`},
+		{http.MethodPost, "/items/7", `{"title": "c"}`, `{"response":{"updated":0,"matched":0,"changed":0}}`},
+		{http.MethodGet, "/items/2/_exists", "", `{"response":{"exists":true}}`},
+		{http.MethodDelete, "/items/1", "", `{"response":{"deleted":1}}`},
+		{http.MethodGet, "/items/1/_exists", "", `{"response":{"exists":false}}`},
+	}
+
+	for _, c := range cases {
+		w := serveRequest(handler, c.method, c.path, c.body)
+		if w.Code != http.StatusOK || w.Body.String() != c.expected {
+			t.Errorf("%s %s: expected %s, got %d %s", c.method, c.path, c.expected, w.Code, w.Body.String())
+		}
+	}
+
+	if backend.records["2"]["title"] != "c" || len(backend.records) != 1 {
+		t.Errorf("unexpected records %v", backend.records)
+	}
+
+	w := serveRequest(handler, http.MethodGet, "/items/_schema", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"primary_key":"id"`) {
+		t.Errorf("schema: unexpected %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,113 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+const (
+	defaultMaxBodySize = 10 << 20
+	// maxJSONDepth is far above any form and keeps hostile bodies of nested arrays from being decoded.
+	maxJSONDepth = 32
+)
+
+// importPath limits its uploads on its own, files are larger than JSON bodies.
+var importPath = regexp.MustCompile(`^/\w+/_import$`)
+
+var errBodyTooLarge = fmt.Errorf("request body too large")
+
+// WithMaxBodySize limits request bodies to size bytes instead of 10 MiB, larger bodies are rejected with 413.
+func WithMaxBodySize(size int64) Option {
+	return func(exp *Explorer) error {
+		if size <= 0 {
+			return fmt.Errorf("max body size must be positive")
+		}
+
+		exp.maxBodySize = size
+		return nil
+	}
+}
+
+func (exp Explorer) bodyLimitMiddleware(next http.Handler) http.Handler {
+	limit := exp.maxBodySize
+	if limit == 0 {
+		limit = defaultMaxBodySize
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if importPath.MatchString(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.ContentLength > limit {
+			writeError(w, http.StatusRequestEntityTooLarge, errBodyTooLarge)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkJSONDepth rejects JSON nested deeper than depth arrays and objects.
+func checkJSONDepth(data []byte, depth int) error {
+	level, inString, escaped := 0, false, false
+	for _, ch := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if ch == '\\' {
+				escaped = true
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == '[' || ch == '{':
+			level++
+			if level > depth {
+				return fmt.Errorf("json nested deeper than %d levels", depth)
+			}
+		case ch == ']' || ch == '}':
+			level--
+		}
+	}
+
+	return nil
+}
+
+// decodeJSONBody decodes the request body into v, bodies over the size limit fail with errBodyTooLarge.
+func decodeJSONBody(r *http.Request, v any) error {
+	data, err := io.ReadAll(r.Body)
+
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return errBodyTooLarge
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := checkJSONDepth(data, maxJSONDepth); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// writeBodyError reports a body that could not be read or decoded.
+func writeBodyError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	if err == errBodyTooLarge || errors.As(err, &maxErr) {
+		writeError(w, http.StatusRequestEntityTooLarge, errBodyTooLarge)
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, err)
+}
@@ -0,0 +1,76 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckJSONDepth(t *testing.T) {
+	cases := []struct {
+		name  string
+		body  string
+		valid bool
+	}{
+		{"flat", `{"title": "a", "tags": [1, 2]}`, true},
+		{"at limit", strings.Repeat("[", 3) + strings.Repeat("]", 3), true},
+		{"too deep", strings.Repeat("[", 4) + strings.Repeat("]", 4), false},
+		{"brackets in strings", `{"title": "[[[[{{{{\"[["}`, true},
+	}
+
+	for _, c := range cases {
+		err := checkJSONDepth([]byte(c.body), 3)
+		if (err == nil) != c.valid {
+			t.Errorf("%s: expected valid %v, got %v", c.name, c.valid, err)
+		}
+	}
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	var exp Explorer
+	if err := WithMaxBodySize(16)(&exp); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := exp.bodyLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		form := make(map[string]any)
+		if err := decodeJSONBody(r, &form); err != nil {
+			writeBodyError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	cases := []struct {
+		name     string
+		path     string
+		body     string
+		chunked  bool
+		expected int
+	}{
+		{"small", "/items/1", `{"title": "a"}`, false, http.StatusNoContent},
+		{"content length", "/items/1", `{"title": "too long"}`, false, http.StatusRequestEntityTooLarge},
+		{"chunked", "/items/1", `{"title": "too long"}`, true, http.StatusRequestEntityTooLarge},
+		{"malformed", "/items/1", `{"title"`, false, http.StatusBadRequest},
+		{"import", "/items/_import", `{"title": "too long"}`, false, http.StatusNoContent},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodPost, c.path, strings.NewReader(c.body))
+		if c.chunked {
+			r.ContentLength = -1
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != c.expected {
+			t.Errorf("%s: expected %d, got %d", c.name, c.expected, w.Code)
+		}
+	}
+
+	if err := WithMaxBodySize(0)(&exp); err == nil {
+		t.Error("expected an error for a zero limit")
+	}
+}
@@ -0,0 +1,69 @@
+package dbexplorer
+
+// isBooleanColumn reports whether the dialect stores booleans in the column, e.g. TINYINT(1) in MySQL.
+func (exp Explorer) isBooleanColumn(table string, column string) bool {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return false
+	}
+
+	info, ok := schema.Column(column)
+	if !ok {
+		return false
+	}
+
+	return exp.dialect.IsBoolean(info)
+}
+
+// isValidBoolean accepts true/false and the 0/1 numbers clients may send for boolean columns.
+func isValidBoolean(nullable bool, value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return true
+	case float64:
+		return v == 0 || v == 1
+	case nil:
+		return nullable
+	}
+
+	return false
+}
+
+// toBoolean converts the stored 0/1 value of a boolean column, other values are returned as is.
+func toBoolean(value any) any {
+	switch v := value.(type) {
+	case int64:
+		return v != 0
+	case uint64:
+		return v != 0
+	case string:
+		switch v {
+		case "0", "\x00":
+			return false
+		case "1", "\x01":
+			return true
+		}
+	}
+
+	return value
+}
+
+// renderBooleans replaces the 0/1 values of boolean columns of the table with true/false.
+func (exp Explorer) renderBooleans(table string, items ...map[string]any) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return
+	}
+
+	for _, c := range schema.Columns {
+		if !exp.dialect.IsBoolean(c) {
+			continue
+		}
+
+		for _, item := range items {
+			if value, ok := item[c.Name]; ok {
+				item[c.Name] = toBoolean(value)
+			}
+		}
+	}
+}
@@ -0,0 +1,680 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const maxBulkRecords = 1000
+
+// refField names a record of a bulk insert, later records may use {"$ref": name} as a value
+// to get the primary key of that record, e.g. for the parent of a tree.
+const refField = "_ref"
+
+type BulkInsertResponse struct {
+	IDs  []any          `json:"ids"`
+	Refs map[string]any `json:"refs,omitempty"`
+}
+
+// BulkRecordError lists the validation errors of the record at Index of the request.
+type BulkRecordError struct {
+	Index  int               `json:"index"`
+	Error  string            `json:"error"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+type BulkErrorResponse struct {
+	Error   string            `json:"error"`
+	Records []BulkRecordError `json:"records"`
+}
+
+// bulkInsert inserts the forms, which have the same columns, with one multi-row INSERT and returns their keys.
+// MySQL assigns consecutive auto-increment values to a multi-row INSERT, so they are derived from LastInsertId.
+func (exp Explorer) bulkInsert(q queryer, table string, primaryKey string, forms []map[string]any) ([]any, error) {
+	for i := range forms {
+		form, err := exp.generateID(table, forms[i])
+		if err != nil {
+			return nil, err
+		}
+		forms[i] = form
+	}
+
+	columns := make([]string, 0, len(forms[0]))
+	for column := range forms[0] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	rowPlaceholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+	rows := make([]string, len(forms))
+	args := make([]any, 0, len(forms)*len(columns))
+	for i, form := range forms {
+		rows[i] = rowPlaceholder
+		for _, column := range columns {
+			args = append(args, form[column])
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", exp.quote(table), exp.quoteColumns(table, columns), strings.Join(rows, ", "))
+
+	ids := make([]any, 0, len(forms))
+	if _, ok := forms[0][primaryKey]; ok {
+		if _, err := q.Exec(query, args...); err != nil {
+			return nil, err
+		}
+
+		for _, form := range forms {
+			ids = append(ids, form[primaryKey])
+		}
+		return ids, nil
+	}
+
+	if exp.dialect.InsertReturning() {
+		result, err := q.Query(query+" RETURNING "+exp.quoteColumn(table, primaryKey), args...)
+		if err != nil {
+			return nil, err
+		}
+
+		defer result.Close()
+
+		for result.Next() {
+			var id any
+			if err := result.Scan(&id); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, result.Err()
+	}
+
+	result, err := q.Exec(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range forms {
+		ids = append(ids, first+int64(i))
+	}
+
+	return ids, nil
+}
+
+// bulkRefName returns the name of a {"$ref": name} value.
+func bulkRefName(value any) (string, bool) {
+	ref, ok := value.(map[string]any)
+	if !ok || len(ref) != 1 {
+		return "", false
+	}
+
+	name, ok := ref["$ref"].(string)
+	return name, ok
+}
+
+// bulkRefs removes the _ref of every record and returns the record index by its name and the columns
+// of every record referring to an earlier one, or the errors of the records with invalid references.
+func bulkRefs(records []map[string]any) (map[string]int, map[int][]string, []BulkRecordError) {
+	refs := make(map[string]int)
+	refColumns := make(map[int][]string)
+	recordErrors := make([]BulkRecordError, 0)
+
+	for i, record := range records {
+		for column, value := range record {
+			name, ok := bulkRefName(value)
+			if !ok {
+				continue
+			}
+
+			if _, ok := refs[name]; !ok {
+				recordErrors = append(recordErrors, BulkRecordError{Index: i, Error: fmt.Sprintf("%s: $ref %s names no earlier record", column, name)})
+				continue
+			}
+			refColumns[i] = append(refColumns[i], column)
+		}
+		sort.Strings(refColumns[i])
+
+		value, ok := record[refField]
+		if !ok {
+			continue
+		}
+		delete(record, refField)
+
+		name, ok := value.(string)
+		if !ok || name == "" {
+			recordErrors = append(recordErrors, BulkRecordError{Index: i, Error: "_ref must be a non-empty string"})
+			continue
+		}
+		if _, ok := refs[name]; ok {
+			recordErrors = append(recordErrors, BulkRecordError{Index: i, Error: fmt.Sprintf("duplicate _ref %s", name)})
+			continue
+		}
+		refs[name] = i
+	}
+
+	return refs, refColumns, recordErrors
+}
+
+// bulkRecordFailure is a record found invalid once its references were resolved.
+type bulkRecordFailure struct {
+	index int
+	err   error
+}
+
+func (e bulkRecordFailure) Error() string {
+	return fmt.Sprintf("record %d: %v", e.index, e.err)
+}
+
+// bulkInsertLinked inserts records referring to earlier ones, the pending rows are inserted first
+// whenever a record refers to one of them, so the rest still goes in multi-row INSERTs.
+func (exp Explorer) bulkInsertLinked(q queryer, table string, primaryKey string, records []map[string]any, forms []map[string]any,
+	refs map[string]int, refColumns map[int][]string, validationOptions ValidationOptions) ([]any, error) {
+	ids := make([]any, 0, len(forms))
+	start := 0
+	flush := func(end int) error {
+		if end == start {
+			return nil
+		}
+
+		batch, err := exp.bulkInsert(q, table, primaryKey, forms[start:end])
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, batch...)
+		start = end
+		return nil
+	}
+
+	for i, record := range records {
+		columns, ok := refColumns[i]
+		if !ok {
+			continue
+		}
+
+		for _, column := range columns {
+			name, _ := bulkRefName(record[column])
+			if refs[name] >= start {
+				if err := flush(i); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+
+		resolved := make(map[string]any, len(record))
+		for column, value := range record {
+			resolved[column] = value
+		}
+		for _, column := range columns {
+			name, _ := bulkRefName(record[column])
+			resolved[column] = ids[refs[name]]
+		}
+
+		form, err := exp.processForm(table, resolved, primaryKey, validationOptions)
+		if err != nil {
+			return nil, bulkRecordFailure{index: i, err: err}
+		}
+		forms[i] = form
+	}
+
+	if err := flush(len(forms)); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// validateLinked validates a record without its references, which are checked once resolved.
+func (exp Explorer) validateLinked(table string, record map[string]any, columns []string, primaryKey string, validationOptions ValidationOptions) error {
+	unresolved := make(map[string]any, len(record))
+	for column, value := range record {
+		if !containsString(columns, column) {
+			unresolved[column] = value
+		}
+	}
+
+	_, err := exp.processForm(table, unresolved, primaryKey, validationOptions)
+
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	rest := make(ValidationErrors, 0, len(errs))
+	for _, e := range errs {
+		if !containsString(columns, e.Field) {
+			rest = append(rest, e)
+		}
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+
+	return rest
+}
+
+func writeBulkErrors(w http.ResponseWriter, recordErrors []BulkRecordError) {
+	data, _ := json.Marshal(BulkErrorResponse{Error: "invalid records", Records: recordErrors})
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(data)
+}
+
+func bulkRecordError(index int, err error) BulkRecordError {
+	recordError := BulkRecordError{Index: index, Error: err.Error()}
+	if errs, ok := err.(ValidationErrors); ok {
+		recordError.Errors = errs
+	}
+
+	return recordError
+}
+
+// handlerBulkInsert validates every record and inserts all of them or none.
+func (exp Explorer) handlerBulkInsert(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("bulk insert is not allowed for writes requiring approval"))
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	records := make([]map[string]any, 0)
+	if err := decodeJSONBody(r, &records); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(records) == 0 || len(records) > maxBulkRecords {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("expected 1 to %d records", maxBulkRecords))
+		return
+	}
+
+	refs, refColumns, recordErrors := bulkRefs(records)
+
+	validationOptions := ValidationOptions{
+		IgnorePk:          true,
+		IncludePk:         !exp.primaryKeyGenerated(tableName),
+		WithDefaultValues: true,
+		ColumnWritable:    exp.columnWritable(r.Context(), tableName, principal),
+	}
+
+	forms := make([]map[string]any, len(records))
+	for i, record := range records {
+		var err error
+		if columns, ok := refColumns[i]; ok {
+			err = exp.validateLinked(tableName, record, columns, primaryKey, validationOptions)
+		} else {
+			forms[i], err = exp.processForm(tableName, record, primaryKey, validationOptions)
+		}
+		if err == nil {
+			continue
+		}
+
+		if formErrorStatus(err) == http.StatusForbidden {
+			exp.writeForbidden(w, r, err)
+			return
+		}
+
+		recordErrors = append(recordErrors, bulkRecordError(i, err))
+	}
+
+	if len(recordErrors) > 0 {
+		sort.SliceStable(recordErrors, func(i, j int) bool {
+			return recordErrors[i].Index < recordErrors[j].Index
+		})
+		writeBulkErrors(w, recordErrors)
+		return
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	var ids []any
+	if len(refColumns) == 0 {
+		ids, err = exp.bulkInsert(tx, tableName, primaryKey, forms)
+	} else {
+		ids, err = exp.bulkInsertLinked(tx, tableName, primaryKey, records, forms, refs, refColumns, validationOptions)
+	}
+
+	var failure bulkRecordFailure
+	if errors.As(err, &failure) {
+		if formErrorStatus(failure.err) == http.StatusForbidden {
+			exp.writeForbidden(w, r, failure.err)
+			return
+		}
+
+		writeBulkErrors(w, []BulkRecordError{bulkRecordError(failure.index, failure.err)})
+		return
+	}
+	if err != nil {
+		if !writeSQLError(w, r, err) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("insert failed"))
+		}
+		return
+	}
+
+	if exp.auditLog {
+		for _, id := range ids {
+			after, err := exp.getItem(tx, tableName, primaryKey, id)
+			if err != nil {
+				writeInternalError(w, r, err)
+				return
+			}
+
+			entry := AuditEntry{Table: tableName, RecordID: id, Operation: writeCreate, After: after}
+			if err := exp.writeAudit(tx, entry, auditMeta{Principal: principal}); err != nil {
+				writeInternalError(w, r, err)
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	res := BulkInsertResponse{IDs: ids}
+	if len(refs) > 0 {
+		res.Refs = make(map[string]any, len(refs))
+		for name, index := range refs {
+			res.Refs[name] = ids[index]
+		}
+	}
+
+	writeResponse(w, res)
+}
+
+// BulkUpdateRequest updates the columns of set in every row matching where.
+type BulkUpdateRequest struct {
+	Set   map[string]any `json:"set"`
+	Where map[string]any `json:"where"`
+}
+
+// BulkUpdateResponse counts the rows matching where and the ones the update changed, like updated does.
+type BulkUpdateResponse struct {
+	Updated int64 `json:"updated"`
+	Matched int64 `json:"matched"`
+	Changed int64 `json:"changed"`
+}
+
+func (exp Explorer) handlerBulkUpdate(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("bulk update is not allowed for writes requiring approval"))
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	var req BulkUpdateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(req.Set) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("set is required"))
+		return
+	}
+
+	// an update of the whole table is more likely a mistake than intended
+	if len(req.Where) == 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("where is required"))
+		return
+	}
+
+	form, err := exp.processForm(tableName, req.Set, primaryKey, ValidationOptions{
+		IgnoreNotProvidedField: true,
+		ColumnWritable:         exp.columnWritable(r.Context(), tableName, principal),
+	})
+	if err != nil {
+		exp.writeFormError(w, r, err)
+		return
+	}
+
+	where, args, err := exp.parseWhere(tableName, req.Where)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	var matched int64
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", exp.quote(tableName), where)
+	if err := tx.QueryRow(query, args...).Scan(&matched); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	var updated int64
+	if exp.auditLog || exp.lockTTL > 0 {
+		op := writeOp{Op: writeUpdate, Table: tableName, PrimaryKey: primaryKey, Form: form, LockToken: r.Header.Get(lockTokenHeader)}
+		updated, err = exp.writeEach(r.Context(), tx, op, where, args, principal)
+	} else {
+		updated, err = exp.updateWhere(tx, tableName, form, where, args)
+	}
+	if err != nil {
+		if !writeSQLError(w, r, err) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("update failed"))
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	addRowsAffected(r.Context(), updated)
+	writeResponse(w, BulkUpdateResponse{Updated: updated, Matched: matched, Changed: updated})
+}
+
+func (exp Explorer) updateWhere(q queryer, table string, form map[string]any, where string, whereArgs []any) (int64, error) {
+	columns := make([]string, 0, len(form))
+	for column := range form {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	assignments := make([]string, len(columns))
+	args := make([]any, 0, len(columns)+len(whereArgs))
+	for i, column := range columns {
+		assignments[i] = exp.quoteColumn(table, column) + " = ?"
+		args = append(args, form[column])
+	}
+	args = append(args, whereArgs...)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", exp.quote(table), strings.Join(assignments, ", "), where)
+	result, err := q.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	updated, err := result.RowsAffected()
+	exp.countAffected(table, writeUpdate, updated)
+
+	return updated, err
+}
+
+// writeEach applies the update or delete to the matching rows one by one, so each of them gets its audit entry
+// and locked rows are refused.
+func (exp Explorer) writeEach(ctx context.Context, q queryer, op writeOp, where string, args []any, principal *Principal) (int64, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s FOR UPDATE", exp.quoteColumn(op.Table, op.PrimaryKey), exp.quote(op.Table), where)
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]any, 0)
+	for rows.Next() {
+		var id any
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, normalizeValue(id))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var affected int64
+	for _, id := range ids {
+		op.ID = id
+		written, err := exp.executeWrite(ctx, q, op, auditMeta{Principal: principal})
+		if err != nil {
+			return 0, err
+		}
+		affected += written.Affected
+	}
+
+	return affected, nil
+}
+
+type BulkDeleteResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// bulkDeleteWhere builds the condition of a bulk delete from ?ids=1,2,3 or the list filters.
+func (exp Explorer) bulkDeleteWhere(table string, primaryKey string, query url.Values) (string, []any, error) {
+	if query.Has("ids") {
+		if len(query) > 1 {
+			return "", nil, fmt.Errorf("ids can't be combined with filters")
+		}
+
+		ids := make([]any, 0)
+		for _, id := range splitList(query.Get("ids")) {
+			value, err := exp.primaryKeyValue(table, id)
+			if err != nil {
+				return "", nil, err
+			}
+			ids = append(ids, value)
+		}
+
+		if len(ids) == 0 || len(ids) > maxBulkRecords {
+			return "", nil, fmt.Errorf("expected 1 to %d ids", maxBulkRecords)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+		return fmt.Sprintf("%s IN (%s)", exp.quoteColumn(table, primaryKey), placeholders), ids, nil
+	}
+
+	filters, err := exp.parseFilters(table, query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// deleting the whole table takes an explicit filter
+	if len(filters) == 0 {
+		return "", nil, fmt.Errorf("ids or filters are required")
+	}
+
+	return exp.filterWhere(table, filters)
+}
+
+// handlerBulkDelete deletes the rows of DELETE /{table}?ids=1,2,3 or DELETE /{table}?status=closed in one transaction.
+func (exp Explorer) handlerBulkDelete(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("bulk delete is not allowed for writes requiring approval"))
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	where, args, err := exp.bulkDeleteWhere(tableName, primaryKey, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	var deleted int64
+	if exp.auditLog || exp.lockTTL > 0 {
+		op := writeOp{Op: writeDelete, Table: tableName, PrimaryKey: primaryKey, LockToken: r.Header.Get(lockTokenHeader)}
+		deleted, err = exp.writeEach(r.Context(), tx, op, where, args, principal)
+	} else {
+		var result sql.Result
+		result, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", exp.quote(tableName), where), args...)
+		if err == nil {
+			deleted, err = result.RowsAffected()
+			exp.countAffected(tableName, writeDelete, deleted)
+		}
+	}
+	if err != nil {
+		if !writeSQLError(w, r, err) {
+			writeError(w, http.StatusConflict, fmt.Errorf("delete failed"))
+		}
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	addRowsAffected(r.Context(), deleted)
+	writeResponse(w, BulkDeleteResponse{Deleted: deleted})
+}
@@ -0,0 +1,42 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBulkRefs(t *testing.T) {
+	records := []map[string]any{
+		{"_ref": "root", "title": "root"},
+		{"_ref": "child", "title": "child", "parent_id": map[string]any{"$ref": "root"}},
+		{"title": "grandchild", "parent_id": map[string]any{"$ref": "child"}},
+		{"title": "orphan", "parent_id": map[string]any{"$ref": "later"}},
+		{"_ref": "later", "title": "later"},
+		{"_ref": "root", "title": "duplicate"},
+		{"_ref": 1, "title": "number"},
+	}
+
+	refs, refColumns, recordErrors := bulkRefs(records)
+
+	if !reflect.DeepEqual(refs, map[string]int{"root": 0, "child": 1, "later": 4}) {
+		t.Errorf("unexpected refs %v", refs)
+	}
+
+	if !reflect.DeepEqual(refColumns, map[int][]string{1: {"parent_id"}, 2: {"parent_id"}}) {
+		t.Errorf("unexpected ref columns %v", refColumns)
+	}
+
+	indexes := make([]int, 0)
+	for _, e := range recordErrors {
+		indexes = append(indexes, e.Index)
+	}
+	if !reflect.DeepEqual(indexes, []int{3, 5, 6}) {
+		t.Errorf("expected errors of records 3, 5 and 6, got %+v", recordErrors)
+	}
+
+	for i, record := range records {
+		if _, ok := record[refField]; ok {
+			t.Errorf("record %d: expected _ref to be removed", i)
+		}
+	}
+}
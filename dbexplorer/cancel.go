@@ -0,0 +1,84 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// queryCanceler is implemented by dialects whose driver only drops the connection of a canceled query,
+// leaving the query itself running on the server.
+type queryCanceler interface {
+	// ConnectionIDQuery returns the server id of the current connection.
+	ConnectionIDQuery() string
+	// CancelQuery stops the running query of the connection with the id given as the only argument.
+	CancelQuery() string
+}
+
+func (MySQLDialect) ConnectionIDQuery() string {
+	return "SELECT CONNECTION_ID()"
+}
+
+func (MySQLDialect) CancelQuery() string {
+	return "KILL QUERY ?"
+}
+
+// queryContext runs a long SELECT that is stopped on the server when ctx is canceled, e.g. because
+// the client disconnected. release must be called after the rows are closed.
+func (exp Explorer) queryContext(ctx context.Context, query string, args ...any) (rows *sql.Rows, release func(), err error) {
+	query = rebind(exp.dialect, query)
+
+	canceler, ok := exp.dialect.(queryCanceler)
+	if !ok {
+		rows, err = exp.DB.QueryContext(ctx, query, args...)
+		return rows, func() {}, wrapQueryError(query, err)
+	}
+
+	conn, err := exp.DB.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var connectionID int64
+	if err := conn.QueryRowContext(ctx, canceler.ConnectionIDQuery()).Scan(&connectionID); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	var (
+		mu       sync.Mutex
+		released bool
+		done     = make(chan struct{})
+	)
+
+	go func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			mu.Lock()
+			defer mu.Unlock()
+
+			// once released the connection may run someone else's query
+			if !released {
+				exp.DB.Exec(canceler.CancelQuery(), connectionID)
+			}
+		}
+	}()
+
+	release = func() {
+		mu.Lock()
+		released = true
+		mu.Unlock()
+
+		close(done)
+		conn.Close()
+	}
+
+	rows, err = conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		release()
+		return nil, nil, wrapQueryError(query, err)
+	}
+
+	return rows, release, nil
+}
@@ -0,0 +1,332 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	changesTable = metaTablePrefix + "changes"
+
+	changePending  = "pending"
+	changeApproved = "approved"
+	changeRejected = "rejected"
+	changeFailed   = "failed"
+)
+
+type writeApproval struct {
+	approverRoles   []string
+	privilegedRoles []string
+}
+
+type Change struct {
+	ID          int64           `json:"id"`
+	Table       string          `json:"table"`
+	Operation   string          `json:"operation"`
+	RecordID    *string         `json:"record_id"`
+	Payload     json.RawMessage `json:"payload"`
+	RequestedBy *string         `json:"requested_by"`
+	Status      string          `json:"status"`
+	Error       *string         `json:"error"`
+	CreatedAt   string          `json:"created_at"`
+	DecidedBy   *string         `json:"decided_by"`
+	DecidedAt   *string         `json:"decided_at"`
+}
+
+type PendingChangeResponse struct {
+	ChangeID any    `json:"change_id"`
+	Status   string `json:"status"`
+}
+
+type ChangeDecisionResponse struct {
+	ChangeID int64  `json:"change_id"`
+	Status   string `json:"status"`
+	RecordID any    `json:"record_id,omitempty"`
+}
+
+type GetChangesResponse struct {
+	Changes []Change `json:"changes"`
+}
+
+type GetChangeResponse struct {
+	Change Change `json:"change"`
+}
+
+// WithWriteApproval stores writes of principals without approver or privileged roles as pending changes.
+// Approvers review them at /_changes and only approved changes are executed. Implies WithAuditLog.
+func WithWriteApproval(approverRoles []string, privilegedRoles []string) Option {
+	return func(exp *Explorer) error {
+		if err := WithAuditLog()(exp); err != nil {
+			return err
+		}
+
+		exp.metaTables = append(exp.metaTables, metaTable{
+			Name: changesTable,
+			Columns: []metaColumn{
+				{Name: "id", Kind: "serial"},
+				{Name: "table_name", Kind: "string"},
+				{Name: "operation", Kind: "string"},
+				{Name: "record_id", Kind: "string", Nullable: true},
+				{Name: "payload", Kind: "text", Nullable: true},
+				{Name: "requested_by", Kind: "string", Nullable: true},
+				{Name: "status", Kind: "string"},
+				{Name: "error", Kind: "text", Nullable: true},
+				{Name: "created_at", Kind: "time"},
+				{Name: "decided_by", Kind: "string", Nullable: true},
+				{Name: "decided_at", Kind: "time", Nullable: true},
+			},
+			PrimaryKey: "id",
+			Indexes:    [][]string{{"status", "id"}},
+		})
+
+		exp.approval = &writeApproval{
+			approverRoles:   approverRoles,
+			privilegedRoles: privilegedRoles,
+		}
+		return nil
+	}
+}
+
+func hasAnyRole(principal *Principal, roles []string) bool {
+	for _, role := range roles {
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (exp Explorer) requiresApproval(principal *Principal) bool {
+	if exp.approval == nil {
+		return false
+	}
+
+	return !hasAnyRole(principal, exp.approval.approverRoles) && !hasAnyRole(principal, exp.approval.privilegedRoles)
+}
+
+func (exp Explorer) submitChange(w http.ResponseWriter, r *http.Request, principal *Principal, op writeOp) {
+	var recordID any
+	if op.ID != nil {
+		recordID = fmt.Sprint(op.ID)
+	}
+
+	payload, err := marshalNullable(op.Form)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	id, err := exp.insertReturningID(exp.db(), `INSERT INTO `+changesTable+` (table_name, operation, record_id, payload, requested_by, status, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`, "id",
+		op.Table, op.Op, recordID, payload, principalName(principal), changePending, time.Now().UTC())
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	writeResponse(w, PendingChangeResponse{ChangeID: id, Status: changePending})
+}
+
+func (exp Explorer) requireApprover(w http.ResponseWriter, r *http.Request) bool {
+	principal := PrincipalFromContext(r.Context())
+	if !hasAnyRole(principal, exp.approval.approverRoles) {
+		exp.writeForbidden(w, r, fmt.Errorf("forbidden"))
+		return false
+	}
+
+	if !hasAdminScope(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("missing scope %s:*", adminScope))
+		return false
+	}
+
+	return true
+}
+
+const changeColumns = `id, table_name, operation, record_id, payload, requested_by, status, error, created_at, decided_by, decided_at`
+
+func scanChange(scanner interface{ Scan(...any) error }) (Change, error) {
+	var (
+		change  Change
+		payload sql.NullString
+	)
+
+	err := scanner.Scan(&change.ID, &change.Table, &change.Operation, &change.RecordID, &payload, &change.RequestedBy,
+		&change.Status, &change.Error, &change.CreatedAt, &change.DecidedBy, &change.DecidedAt)
+	if err != nil {
+		return change, err
+	}
+
+	change.Payload = json.RawMessage("null")
+	if payload.Valid {
+		change.Payload = json.RawMessage(payload.String)
+	}
+
+	return change, nil
+}
+
+func (exp Explorer) handlerGetChanges(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireApprover(w, r) {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = changePending
+	}
+
+	rows, err := exp.db().Query(`SELECT `+changeColumns+` FROM `+changesTable+` WHERE status = ? ORDER BY id`, status)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer rows.Close()
+
+	changes := make([]Change, 0)
+	for rows.Next() {
+		change, err := scanChange(rows)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		changes = append(changes, change)
+	}
+
+	writeResponse(w, GetChangesResponse{Changes: changes})
+}
+
+func (exp Explorer) handlerGetChange(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireApprover(w, r) {
+		return
+	}
+
+	change, err := scanChange(exp.db().QueryRow(`SELECT `+changeColumns+` FROM `+changesTable+` WHERE id = ?`, exp.getId(r.URL.Path)))
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("change not found"))
+		return
+	}
+
+	writeResponse(w, GetChangeResponse{Change: change})
+}
+
+func (exp Explorer) handlerApproveChange(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireApprover(w, r) {
+		return
+	}
+
+	changeID := exp.getId(r.URL.Path)
+	principal := PrincipalFromContext(r.Context())
+
+	change, err := scanChange(exp.db().QueryRow(`SELECT `+changeColumns+` FROM `+changesTable+` WHERE id = ? AND status = ?`, changeID, changePending))
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("change not found"))
+		return
+	}
+
+	if !exp.isValidTableName(change.Table) {
+		writeError(w, http.StatusNotFound, errUnknownTable)
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(change.Table)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	op := writeOp{
+		Op:         change.Operation,
+		Table:      change.Table,
+		PrimaryKey: primaryKey,
+	}
+	if change.RecordID != nil {
+		op.ID = *change.RecordID
+	}
+	if err := json.Unmarshal(change.Payload, &op.Form); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	// the row lock makes concurrent approvals of the same change execute it once
+	var status string
+	err = tx.QueryRow(`SELECT status FROM `+changesTable+` WHERE id = ? FOR UPDATE`, changeID).Scan(&status)
+	if err != nil || status != changePending {
+		tx.Rollback()
+		writeError(w, http.StatusNotFound, fmt.Errorf("change not found"))
+		return
+	}
+
+	requester := &Principal{}
+	if change.RequestedBy != nil {
+		requester.Name = *change.RequestedBy
+	}
+
+	result, err := exp.executeWrite(r.Context(), tx, op, auditMeta{Principal: requester, ChangeID: change.ID})
+	if err != nil {
+		tx.Rollback()
+
+		exp.db().Exec(`UPDATE `+changesTable+` SET status = ?, error = ?, decided_by = ?, decided_at = ? WHERE id = ?`,
+			changeFailed, err.Error(), principalName(principal), time.Now().UTC(), changeID)
+
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+
+	_, err = tx.Exec(`UPDATE `+changesTable+` SET status = ?, decided_by = ?, decided_at = ? WHERE id = ?`,
+		changeApproved, principalName(principal), time.Now().UTC(), changeID)
+	if err != nil {
+		tx.Rollback()
+		writeInternalError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	writeResponse(w, ChangeDecisionResponse{ChangeID: change.ID, Status: changeApproved, RecordID: result.ID})
+}
+
+func (exp Explorer) handlerRejectChange(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireApprover(w, r) {
+		return
+	}
+
+	changeID := exp.getId(r.URL.Path)
+
+	result, err := exp.db().Exec(`UPDATE `+changesTable+` SET status = ?, decided_by = ?, decided_at = ? WHERE id = ? AND status = ?`,
+		changeRejected, principalName(PrincipalFromContext(r.Context())), time.Now().UTC(), changeID, changePending)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	if affected == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("change not found"))
+		return
+	}
+
+	var id int64
+	fmt.Sscan(changeID, &id)
+
+	writeResponse(w, ChangeDecisionResponse{ChangeID: id, Status: changeRejected})
+}
@@ -0,0 +1,126 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChangeHandlersRequireApprover(t *testing.T) {
+	exp := newItemsExplorer()
+	if err := WithWriteApproval([]string{"approver"}, nil)(&exp); err != nil {
+		t.Fatal(err)
+	}
+	exp.authenticators = []Authenticator{AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		return &Principal{Name: "bob", Roles: []string{"writer"}}, nil
+	})}
+	exp.initRoutes()
+	handler := exp.authMiddleware(exp.router)
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/_changes"},
+		{http.MethodGet, "/_changes/1"},
+		{http.MethodPost, "/_changes/1/approve"},
+		{http.MethodPost, "/_changes/1/reject"},
+	}
+
+	for _, req := range requests {
+		if w := serveRequest(handler, req.method, req.path, ""); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s: expected 403, got %d", req.method, req.path, w.Code)
+		}
+	}
+}
+
+func TestChangeHandlers(t *testing.T) {
+	db := openTestDB(t, changesTable, auditTable)
+	handler, err := New(db,
+		WithWriteApproval([]string{"approver"}, nil),
+		WithAPIKeys(map[string]APIKey{
+			"writer-key":   {Name: "writer", Roles: []string{"writer"}},
+			"approver-key": {Name: "approver", Roles: []string{"approver"}},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method string, path string, body string, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set(apiKeyHeader, key)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	submit := func(method string, path string, body string) string {
+		w := do(method, path, body, "writer-key")
+		var pending struct {
+			Response PendingChangeResponse `json:"response"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &pending); err != nil || w.Code != http.StatusAccepted || pending.Response.Status != changePending {
+			t.Fatalf("submit %s %s: unexpected %d %s", method, path, w.Code, w.Body.String())
+		}
+		return fmt.Sprint(pending.Response.ChangeID)
+	}
+
+	created := submit(http.MethodPut, "/items/", `{"title": "approved", "description": ""}`)
+	updated := submit(http.MethodPost, "/items/1", `{"title": "rejected"}`)
+
+	w := do(http.MethodGet, "/_changes", "", "approver-key")
+	var listed struct {
+		Response GetChangesResponse `json:"response"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil || len(listed.Response.Changes) != 2 ||
+		listed.Response.Changes[0].Operation != writeCreate || *listed.Response.Changes[0].RequestedBy != "writer" {
+		t.Errorf("list: unexpected %d %s", w.Code, w.Body.String())
+	}
+
+	if w := do(http.MethodPost, "/_changes/"+created+"/approve", "", "writer-key"); w.Code != http.StatusForbidden {
+		t.Errorf("approve as writer: expected 403, got %d", w.Code)
+	}
+
+	w = do(http.MethodPost, "/_changes/"+created+"/approve", "", "approver-key")
+	var approved struct {
+		Response ChangeDecisionResponse `json:"response"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &approved); err != nil || approved.Response.Status != changeApproved || approved.Response.RecordID == nil {
+		t.Fatalf("approve: unexpected %d %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, "/_changes/"+created+"/approve", "", "approver-key"); w.Code != http.StatusNotFound {
+		t.Errorf("approve twice: expected 404, got %d", w.Code)
+	}
+
+	var title string
+	if err := db.QueryRow("SELECT title FROM items WHERE id = ?", approved.Response.RecordID).Scan(&title); err != nil || title != "approved" {
+		t.Errorf("expected the approved record, got %q %v", title, err)
+	}
+
+	var principal, changeID, operation string
+	err = db.QueryRow("SELECT principal, change_id, operation FROM "+auditTable+" WHERE table_name = 'items'").Scan(&principal, &changeID, &operation)
+	if err != nil || principal != "writer" || changeID != created || operation != writeCreate {
+		t.Errorf("unexpected audit entry %s %s %s %v", principal, changeID, operation, err)
+	}
+
+	if w := do(http.MethodPost, "/_changes/"+updated+"/reject", "", "approver-key"); w.Code != http.StatusOK ||
+		w.Body.String() != fmt.Sprintf(`{"response":{"change_id":%s,"status":"rejected"}}`, updated) {
+		t.Errorf("reject: unexpected %d %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, "/_changes/"+updated+"/approve", "", "approver-key"); w.Code != http.StatusNotFound {
+		t.Errorf("approve rejected: expected 404, got %d", w.Code)
+	}
+	if err := db.QueryRow("SELECT title FROM items WHERE id = 1").Scan(&title); err != nil || title == "rejected" {
+		t.Errorf("expected the rejected change not to be written, got %q %v", title, err)
+	}
+
+	w = do(http.MethodGet, "/_changes/"+updated, "", "approver-key")
+	if !strings.Contains(w.Body.String(), `"status":"rejected"`) || !strings.Contains(w.Body.String(), `"decided_by":"approver"`) {
+		t.Errorf("get: unexpected %d %s", w.Code, w.Body.String())
+	}
+}
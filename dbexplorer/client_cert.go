@@ -0,0 +1,28 @@
+package dbexplorer
+
+import (
+	"net/http"
+)
+
+// WithClientCertAuth authenticates requests by the verified TLS client certificate.
+// Roles are looked up by the certificate subject common name or by the full subject DN.
+// The TLS server itself has to be configured to request and verify client certificates.
+func WithClientCertAuth(subjectRoles map[string][]string) Option {
+	return WithAuthenticator(AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.PeerCertificates) == 0 {
+			return nil, nil
+		}
+
+		subject := r.TLS.PeerCertificates[0].Subject
+
+		roles, ok := subjectRoles[subject.CommonName]
+		if !ok {
+			roles = subjectRoles[subject.String()]
+		}
+
+		return &Principal{
+			Name:  subject.CommonName,
+			Roles: roles,
+		}, nil
+	}))
+}
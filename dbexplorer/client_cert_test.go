@@ -0,0 +1,58 @@
+package dbexplorer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClientCertAuth(t *testing.T) {
+	exp := newItemsExplorer()
+	err := WithClientCertAuth(map[string][]string{
+		"alice":                 {"admin"},
+		"CN=bob,O=Example Corp": {"reader"},
+	})(&exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withCert := func(subject pkix.Name, verified bool) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/items", nil)
+		cert := &x509.Certificate{Subject: subject}
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		if verified {
+			r.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+		}
+		return r
+	}
+
+	cases := []struct {
+		name     string
+		request  *http.Request
+		expected *Principal
+	}{
+		{"common name", withCert(pkix.Name{CommonName: "alice"}, true), &Principal{Name: "alice", Roles: []string{"admin"}}},
+		{"subject", withCert(pkix.Name{CommonName: "bob", Organization: []string{"Example Corp"}}, true), &Principal{Name: "bob", Roles: []string{"reader"}}},
+		{"unknown subject", withCert(pkix.Name{CommonName: "carol"}, true), &Principal{Name: "carol"}},
+		{"unverified", withCert(pkix.Name{CommonName: "alice"}, false), nil},
+		{"plain http", httptest.NewRequest(http.MethodGet, "/items", nil), nil},
+	}
+
+	for _, c := range cases {
+		principal, err := exp.authenticators[0].Authenticate(c.request)
+		if err != nil || !reflect.DeepEqual(principal, c.expected) {
+			t.Errorf("%s: expected %v, got %v %v", c.name, c.expected, principal, err)
+		}
+	}
+
+	handler := exp.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, withCert(pkix.Name{CommonName: "alice"}, false))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a verified certificate, got %d", w.Code)
+	}
+}
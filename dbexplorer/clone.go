@@ -0,0 +1,244 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultCloneDepth = 3
+	maxCloneDepth     = 10
+	defaultCloneRows  = 1000
+)
+
+type CloneResponse struct {
+	ID     any            `json:"id"`
+	Cloned map[string]int `json:"cloned"`
+}
+
+// cloneError is a clone failure caused by the request rather than the database.
+type cloneError struct {
+	error
+}
+
+// cloner copies a record and, with depth > 0, the rows referencing it through foreign keys,
+// pointing the copied children at the new parents.
+type cloner struct {
+	ctx       context.Context
+	exp       Explorer
+	tx        dialectTx
+	principal *Principal
+	maxDepth  int
+	maxRows   int
+	cloned    map[string]int
+	visited   map[string]bool
+}
+
+func (c *cloner) cloneRow(table string, row map[string]any, overrides map[string]any, depth int) (any, error) {
+	schema, err := c.exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	oldID := row[schema.PrimaryKey]
+	key := fmt.Sprintf("%s/%v", table, oldID)
+	if c.visited[key] {
+		return nil, nil
+	}
+	c.visited[key] = true
+
+	rows := 0
+	for _, n := range c.cloned {
+		rows += n
+	}
+	if rows >= c.maxRows {
+		return nil, cloneError{fmt.Errorf("clone exceeds %d rows", c.maxRows)}
+	}
+
+	form := make(map[string]any, len(row))
+	for column, value := range row {
+		if column != schema.PrimaryKey {
+			form[column] = value
+		}
+	}
+	for column, value := range overrides {
+		form[column] = value
+	}
+
+	if _, ok := form[schema.PrimaryKey]; !ok && !c.exp.primaryKeyGenerated(table) {
+		return nil, cloneError{fmt.Errorf("table %s needs %s to clone a record", table, schema.PrimaryKey)}
+	}
+
+	op := writeOp{
+		Op:         writeCreate,
+		Table:      table,
+		PrimaryKey: schema.PrimaryKey,
+		Form:       form,
+	}
+
+	written, err := c.exp.executeWrite(c.ctx, c.tx, op, auditMeta{Principal: c.principal})
+	if err != nil {
+		return nil, err
+	}
+	c.cloned[table]++
+
+	if depth >= c.maxDepth {
+		return written.ID, nil
+	}
+
+	for _, ref := range c.exp.referencingKeys(table, schema.PrimaryKey) {
+		query := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", c.exp.quote(ref.Table), c.exp.quoteColumn(ref.Table, ref.ForeignKey.Column))
+		children, err := c.selectRows(query, oldID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, child := range children {
+			overrides := map[string]any{ref.ForeignKey.Column: written.ID}
+			if _, err := c.cloneRow(ref.Table, child, overrides, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return written.ID, nil
+}
+
+func (c *cloner) selectRows(query string, args ...any) ([]map[string]any, error) {
+	rows, err := c.tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return c.exp.scanRows(rows)
+}
+
+// handlerClone copies the record, ?deep=true copies dependent rows as well, limited by
+// ?depth (levels of children, 3 by default) and ?max_rows. The body may override columns of the copy.
+func (exp Explorer) handlerClone(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("clone is not allowed for writes requiring approval"))
+		return
+	}
+
+	id, err := exp.primaryKeyValue(tableName, exp.getId(r.URL.Path))
+	if err != nil {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	overrides := make(map[string]any)
+	if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writable := exp.columnWritable(r.Context(), tableName, principal)
+	for column := range overrides {
+		if !exp.isValidColumnName(tableName, column) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown column %s", column))
+			return
+		}
+		if writable != nil && !writable(column) {
+			exp.writeForbidden(w, r, NewColumnPermissionError(column))
+			return
+		}
+	}
+
+	query := r.URL.Query()
+	c := &cloner{
+		ctx:       r.Context(),
+		exp:       exp,
+		principal: principal,
+		maxRows:   getQueryIntValue(query, "max_rows", defaultCloneRows),
+		cloned:    make(map[string]int),
+		visited:   make(map[string]bool),
+	}
+
+	if deep, _ := strconv.ParseBool(query.Get("deep")); deep {
+		c.maxDepth = getQueryIntValue(query, "depth", defaultCloneDepth)
+		if c.maxDepth < 0 || c.maxDepth > maxCloneDepth {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("depth must be between 0 and %d", maxCloneDepth))
+			return
+		}
+
+		// every table that may be copied is checked before anything is written
+		tables := []string{tableName}
+		for i := 0; i < len(tables); i++ {
+			schema, err := exp.getTableSchema(tables[i])
+			if err != nil {
+				writeInternalError(w, r, err)
+				return
+			}
+			for _, ref := range exp.referencingKeys(tables[i], schema.PrimaryKey) {
+				if !containsString(tables, ref.Table) {
+					tables = append(tables, ref.Table)
+				}
+			}
+		}
+
+		for _, table := range tables {
+			if err := exp.authorize(r.Context(), principal, Action{Table: table, Op: OpWrite}); err != nil {
+				exp.writeForbidden(w, r, err)
+				return
+			}
+		}
+	}
+
+	schema, err := exp.getTableSchema(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	c.tx, err = exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer c.tx.Rollback()
+
+	rows, err := c.selectRows(fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", exp.quote(tableName), exp.quoteColumn(tableName, schema.PrimaryKey)), id)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	if len(rows) == 0 {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	newID, err := c.cloneRow(tableName, rows[0], overrides, 0)
+	if err != nil {
+		var cloneErr cloneError
+		if errors.As(err, &cloneErr) {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		writeError(w, http.StatusConflict, fmt.Errorf("clone failed"))
+		return
+	}
+
+	if err := c.tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	writeResponse(w, CloneResponse{ID: newID, Cloned: c.cloned})
+}
@@ -0,0 +1,211 @@
+package dbexplorer
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// InvalidateWrite is sent after a successful write to the table, or to all tables when the table is empty.
+	InvalidateWrite = "write"
+	// InvalidateSchema is sent when the schema of the database has changed.
+	InvalidateSchema = "schema"
+)
+
+const (
+	clusterPublishTimeout  = 2 * time.Second
+	clusterResubscribeWait = time.Second
+)
+
+// InvalidationEvent tells the explorer instances behind a load balancer that cached data is stale.
+type InvalidationEvent struct {
+	Type   string `json:"type"`
+	Table  string `json:"table,omitempty"`
+	Origin string `json:"origin"`
+}
+
+// ClusterBus delivers invalidation events between explorer instances, e.g. over Redis pub/sub with NewRedisBus.
+type ClusterBus interface {
+	Publish(ctx context.Context, event InvalidationEvent) error
+	// Subscribe calls handle for every published event until ctx is done or the connection fails.
+	Subscribe(ctx context.Context, handle func(InvalidationEvent)) error
+}
+
+// invalidation notifies the local listeners of stale data and shares the events with the other instances.
+type invalidation struct {
+	bus        ClusterBus
+	instanceID string
+
+	mu        sync.RWMutex
+	listeners []func(InvalidationEvent)
+}
+
+func newInvalidation() *invalidation {
+	return &invalidation{}
+}
+
+// WithClusterBus propagates write and schema invalidation events between the explorer instances.
+func WithClusterBus(bus ClusterBus) Option {
+	return func(exp *Explorer) error {
+		id, err := randomToken()
+		if err != nil {
+			return err
+		}
+
+		exp.invalidation.bus = bus
+		exp.invalidation.instanceID = id[:16]
+		return nil
+	}
+}
+
+// OnInvalidate registers a listener called for local and remote invalidation events, e.g. to drop a cache.
+func (exp Explorer) OnInvalidate(listener func(event InvalidationEvent)) {
+	exp.invalidation.mu.Lock()
+	defer exp.invalidation.mu.Unlock()
+
+	exp.invalidation.listeners = append(exp.invalidation.listeners, listener)
+}
+
+// Invalidate notifies the local listeners and publishes the event to the other instances.
+func (exp Explorer) Invalidate(event InvalidationEvent) {
+	exp.invalidation.notify(event)
+
+	if exp.invalidation.bus == nil {
+		return
+	}
+
+	event.Origin = exp.invalidation.instanceID
+
+	// the request context may be canceled right after the response, the event must still be sent
+	ctx, cancel := context.WithTimeout(context.Background(), clusterPublishTimeout)
+	defer cancel()
+
+	if err := exp.invalidation.bus.Publish(ctx, event); err != nil {
+		exp.metrics.add("db_explorer_cluster_errors_total", 1, "op", "publish")
+	}
+}
+
+func (inv *invalidation) notify(event InvalidationEvent) {
+	inv.mu.RLock()
+	listeners := inv.listeners
+	inv.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// initCluster subscribes to the events of the other instances for the lifetime of the explorer.
+func (exp Explorer) initCluster() {
+	exp.metrics.describe("db_explorer_cluster_errors_total", "Failures of the cluster bus by operation.")
+
+	inv := exp.invalidation
+	if inv.bus == nil {
+		return
+	}
+
+	exp.scheduler.background("cluster", func(stop <-chan struct{}) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			<-stop
+			cancel()
+		}()
+
+		for {
+			err := inv.bus.Subscribe(ctx, func(event InvalidationEvent) {
+				if event.Origin != inv.instanceID {
+					inv.notify(event)
+				}
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				exp.metrics.add("db_explorer_cluster_errors_total", 1, "op", "subscribe")
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-time.After(clusterResubscribeWait):
+			}
+		}
+	})
+}
+
+// invalidatedTable returns the table changed by a successful write request, ok is false for reads.
+// Writes to several tables at once, like transactions and approved changes, invalidate all tables.
+func (exp Explorer) invalidatedTable(r *http.Request) (table string, ok bool) {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+		return "", false
+	}
+
+	if strings.HasSuffix(r.URL.Path, "/_validate") || isReadRequest(r) {
+		return "", false
+	}
+
+	segment := strings.Split(r.URL.Path, "/")[1]
+	switch {
+	case segment == "_tx", segment == "_changes":
+		return "", true
+	case exp.isValidTableName(segment):
+		return segment, true
+	}
+
+	return "", false
+}
+
+func (exp Explorer) invalidationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		table, ok := exp.invalidatedTable(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status < http.StatusMultipleChoices {
+			exp.Invalidate(InvalidationEvent{Type: InvalidateWrite, Table: table})
+		}
+	})
+}
+
+// statusRecorder remembers the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	// bytes of the response body written so far
+	bytes int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	n, err := r.ResponseWriter.Write(data)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
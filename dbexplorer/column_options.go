@@ -0,0 +1,138 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const defaultOptionsLimit = 100
+
+type ColumnOption struct {
+	ID    any    `json:"id"`
+	Label string `json:"label"`
+}
+
+type GetColumnOptionsResponse struct {
+	Options []ColumnOption `json:"options"`
+}
+
+// WithDisplayColumn sets the column used as the label of the table rows in reference dropdowns.
+// By default the first text column is used.
+func WithDisplayColumn(table string, column string) Option {
+	return func(exp *Explorer) error {
+		if exp.displayColumns == nil {
+			exp.displayColumns = make(map[string]string)
+		}
+
+		exp.displayColumns[table] = column
+		return nil
+	}
+}
+
+func isTextDataType(dataType string) bool {
+	switch dataType {
+	case "char", "varchar", "text", "tinytext", "mediumtext", "longtext", "character varying", "character":
+		return true
+	}
+
+	return false
+}
+
+func (exp Explorer) getDisplayColumn(table string) (string, error) {
+	if column, ok := exp.displayColumns[table]; ok {
+		return column, nil
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return "", err
+	}
+
+	for _, c := range schema.Columns {
+		if c.Name != schema.PrimaryKey && isTextDataType(c.DataType) {
+			return c.Name, nil
+		}
+	}
+
+	return schema.PrimaryKey, nil
+}
+
+func normalizeValue(value any) any {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+
+	return value
+}
+
+func (exp Explorer) handlerGetColumnOptions(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	schema, err := exp.getTableSchema(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	column, ok := schema.Column(strings.Split(r.URL.Path, "/")[2])
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown column"))
+		return
+	}
+
+	options := make([]ColumnOption, 0)
+
+	if len(column.EnumValues) > 0 {
+		for _, v := range column.EnumValues {
+			options = append(options, ColumnOption{ID: v, Label: v})
+		}
+
+		writeResponse(w, GetColumnOptionsResponse{Options: options})
+		return
+	}
+
+	fk, ok := schema.ForeignKey(column.Name)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("column has no options"))
+		return
+	}
+
+	displayColumn, err := exp.getDisplayColumn(fk.RefTable)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	limit := getQueryIntValue(r.URL.Query(), "limit", defaultOptionsLimit)
+
+	query := fmt.Sprintf("SELECT %s, %s FROM %s ORDER BY %s LIMIT ?",
+		exp.quoteColumn(fk.RefTable, fk.RefColumn), exp.quoteColumn(fk.RefTable, displayColumn), exp.quote(fk.RefTable), exp.quoteColumn(fk.RefTable, displayColumn))
+	rows, err := exp.db().Query(query, limit)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    any
+			label sql.NullString
+		)
+		if err := rows.Scan(&id, &label); err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		options = append(options, ColumnOption{ID: normalizeValue(id), Label: label.String})
+	}
+
+	writeResponse(w, GetColumnOptionsResponse{Options: options})
+}
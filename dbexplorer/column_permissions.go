@@ -0,0 +1,76 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type ColumnPermissionError struct {
+	Field string
+}
+
+func NewColumnPermissionError(field string) ColumnPermissionError {
+	return ColumnPermissionError{
+		Field: field,
+	}
+}
+
+func (e ColumnPermissionError) Error() string {
+	return fmt.Sprintf("field %s is not writable", e.Field)
+}
+
+// WithColumnWriteRoles allows writing the column only to principals having one of the roles.
+// Other columns of the table stay writable for everyone.
+func WithColumnWriteRoles(table string, column string, roles ...string) Option {
+	return func(exp *Explorer) error {
+		if exp.columnWriteRoles == nil {
+			exp.columnWriteRoles = make(map[string]map[string][]string)
+		}
+
+		if exp.columnWriteRoles[table] == nil {
+			exp.columnWriteRoles[table] = make(map[string][]string)
+		}
+
+		exp.columnWriteRoles[table][column] = append(exp.columnWriteRoles[table][column], roles...)
+		return nil
+	}
+}
+
+func (exp Explorer) columnWritable(ctx context.Context, table string, principal *Principal) func(column string) bool {
+	columnRoles, ok := exp.columnWriteRoles[table]
+	if !ok && exp.authorizer == nil {
+		return nil
+	}
+
+	return func(column string) bool {
+		if exp.authorizer != nil {
+			action := Action{Table: table, Op: OpWrite, Columns: []string{column}}
+			if exp.authorizer(ctx, principal, action) != nil {
+				return false
+			}
+		}
+
+		roles, ok := columnRoles[column]
+		if !ok {
+			return true
+		}
+
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+func formErrorStatus(err error) int {
+	if errors.As(err, &ColumnPermissionError{}) {
+		return http.StatusForbidden
+	}
+
+	return http.StatusBadRequest
+}
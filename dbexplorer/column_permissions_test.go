@@ -0,0 +1,59 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestColumnWriteRoles(t *testing.T) {
+	db, err := sql.Open("mysql", "root@tcp(127.0.0.1:1)/none?timeout=1s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	backend := &memoryBackend{records: map[string]map[string]any{"1": {"id": int64(1), "title": "a"}}, nextID: 1}
+	handler, err := New(db,
+		WithBackend(backend),
+		WithAuthenticator(AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+			return &Principal{Name: r.Header.Get("X-Role"), Roles: []string{r.Header.Get("X-Role")}}, nil
+		})),
+		WithColumnWriteRoles("items", "title", "editor"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		role     string
+		method   string
+		path     string
+		expected int
+	}{
+		{"reader", http.MethodPut, "/items/", http.StatusForbidden},
+		{"reader", http.MethodPost, "/items/1", http.StatusForbidden},
+		{"editor", http.MethodPut, "/items/", http.StatusOK},
+		{"editor", http.MethodPost, "/items/1", http.StatusOK},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, c.path, strings.NewReader(`{"title": "b"}`))
+		r.Header.Set("X-Role", c.role)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != c.expected {
+			t.Errorf("%s %s as %s: expected %d, got %d %s", c.method, c.path, c.role, c.expected, w.Code, w.Body.String())
+		}
+		if c.expected == http.StatusForbidden && !strings.Contains(w.Body.String(), "field title is not writable") {
+			t.Errorf("%s %s: expected the column in the error, got %s", c.method, c.path, w.Body.String())
+		}
+	}
+
+	if backend.records["1"]["title"] != "b" || len(backend.records) != 2 {
+		t.Errorf("expected only the editor's writes, got %v", backend.records)
+	}
+}
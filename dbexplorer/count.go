@@ -0,0 +1,69 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type GetCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+// buildCountQuery counts the rows matching the filters of the list endpoint.
+func (exp Explorer) buildCountQuery(table string, query url.Values) (string, []any, error) {
+	where, args, err := exp.listWhere(table, query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sqlQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", exp.quote(table))
+	if where != "" {
+		sqlQuery += " WHERE " + where
+	}
+
+	return sqlQuery, args, nil
+}
+
+// handlerGetCount serves GET /{table}/count, so totals don't need paging through the rows.
+// Like /{table}/bulk it shadows a record with the key "count".
+func (exp Explorer) handlerGetCount(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if !exp.requireAggregate(w, r) {
+		return
+	}
+
+	query, args, err := exp.buildCountQuery(tableName, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, release, err := exp.queryContext(r.Context(), query, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer release()
+	defer rows.Close()
+
+	var res GetCountResponse
+	if rows.Next() {
+		if err := rows.Scan(&res.Count); err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, res)
+}
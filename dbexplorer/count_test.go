@@ -0,0 +1,66 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBuildCountQuery(t *testing.T) {
+	exp := newItemsExplorer()
+
+	query, args, err := exp.buildCountQuery("items", url.Values{"limit": {"5"}})
+	if err != nil || query != "SELECT COUNT(*) FROM `items`" || len(args) != 0 {
+		t.Errorf("unexpected query %q %v %v", query, args, err)
+	}
+
+	query, args, err = exp.buildCountQuery("items", url.Values{"status": {"new"}, "filter": {"id=1 OR id=2"}})
+	if err != nil || query != "SELECT COUNT(*) FROM `items` WHERE `status` = ? AND (`id` = ? OR `id` = ?)" ||
+		!reflect.DeepEqual(args, []any{"new", "1", "2"}) {
+		t.Errorf("unexpected filtered query %q %v %v", query, args, err)
+	}
+
+	if _, _, err := exp.buildCountQuery("items", url.Values{"missing": {"1"}}); err == nil {
+		t.Errorf("expected error for an unknown filter column")
+	}
+}
+
+func TestGetCountHandler(t *testing.T) {
+	exp := newItemsExplorer()
+	exp.initRoutes()
+
+	cases := []struct {
+		path     string
+		expected int
+	}{
+		{"/missing/count", http.StatusNotFound},
+		{"/items/count?missing=1", http.StatusBadRequest},
+		{"/items/count?filter=(id%3D1", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		if w := serveRequest(exp.router, http.MethodGet, c.path, ""); w.Code != c.expected {
+			t.Errorf("%s: expected %d, got %d %s", c.path, c.expected, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestGetCountHandlerDB(t *testing.T) {
+	handler, err := New(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]string{
+		"/items/count":                `{"response":{"count":2}}`,
+		"/items/count?title=memcache": `{"response":{"count":1}}`,
+		"/items/count?limit=1":        `{"response":{"count":2}}`,
+	}
+
+	for path, expected := range cases {
+		if w := serveRequest(handler, http.MethodGet, path, ""); w.Code != http.StatusOK || w.Body.String() != expected {
+			t.Errorf("%s: expected %s, got %d %s", path, expected, w.Code, w.Body.String())
+		}
+	}
+}
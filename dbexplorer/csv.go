@@ -0,0 +1,104 @@
+package dbexplorer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const csvContentType = "text/csv"
+
+// wantsCSV reports whether the records are requested as CSV by ?format=csv or Accept.
+func wantsCSV(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "csv" {
+		return true
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == csvContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// csvColumns returns the header of the CSV, the requested fields or else every column of the table.
+func (exp Explorer) csvColumns(table string, listQuery ListQuery) ([]string, error) {
+	if len(listQuery.Fields) > 0 {
+		return listQuery.Fields, nil
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, len(schema.Columns))
+	for i, column := range schema.Columns {
+		columns[i] = column.Name
+	}
+
+	return columns, nil
+}
+
+// csvValue formats a value for a spreadsheet, NULL is an empty cell and JSON columns stay JSON.
+func csvValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// writeTableItemsCSV writes the page of the list query as CSV with a header row, streaming it like ndjson.
+func (exp Explorer) writeTableItemsCSV(w http.ResponseWriter, r *http.Request, table string, listQuery ListQuery) {
+	columns, err := exp.csvColumns(table, listQuery)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writer := csv.NewWriter(w)
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", csvContentType+"; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table+".csv"))
+		writer.Write(columns)
+		started = true
+	}
+
+	record := make([]string, len(columns))
+	err = exp.streamTableItems(r.Context(), table, listQuery, func(item map[string]any) error {
+		if !started {
+			start()
+		}
+
+		for i, column := range columns {
+			record[i] = csvValue(item[column])
+		}
+
+		return writer.Write(record)
+	})
+	if err != nil && !started {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	if !started {
+		start()
+	}
+	writer.Flush()
+}
@@ -0,0 +1,53 @@
+package dbexplorer
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWantsCSV(t *testing.T) {
+	cases := []struct {
+		url    string
+		accept string
+		csv    bool
+	}{
+		{"/items", "", false},
+		{"/items", "application/json", false},
+		{"/items?format=csv", "", true},
+		{"/items", "text/csv", true},
+		{"/items", "application/json;q=0.5, text/csv; charset=utf-8", true},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", c.url, nil)
+		if c.accept != "" {
+			r.Header.Set("Accept", c.accept)
+		}
+
+		if got := wantsCSV(r); got != c.csv {
+			t.Errorf("%s with Accept %q: expected %v, got %v", c.url, c.accept, c.csv, got)
+		}
+	}
+}
+
+func TestCSVColumns(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id"}, {Name: "title"}, {Name: "tags"}}},
+	})
+
+	columns, err := exp.csvColumns("items", ListQuery{})
+	if err != nil || !reflect.DeepEqual(columns, []string{"id", "title", "tags"}) {
+		t.Errorf("expected every column, got %v, %v", columns, err)
+	}
+
+	columns, err = exp.csvColumns("items", ListQuery{Fields: []string{"title"}})
+	if err != nil || !reflect.DeepEqual(columns, []string{"title"}) {
+		t.Errorf("expected the fields, got %v, %v", columns, err)
+	}
+
+	values := []string{csvValue(nil), csvValue("a,b"), csvValue(int64(42)), csvValue([]any{"x", 1.5})}
+	if !reflect.DeepEqual(values, []string{"", "a,b", "42", `["x",1.5]`}) {
+		t.Errorf("unexpected values %q", values)
+	}
+}
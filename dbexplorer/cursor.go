@@ -0,0 +1,214 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	errInvalidCursor    = errors.New("invalid cursor")
+	errCursorMismatch   = errors.New("cursor does not match the query")
+	errCursorWithOffset = errors.New("cursor and offset can't be combined")
+)
+
+// WithCursorSecret sets the key cursors are signed with. Without it a random key is generated on start,
+// so cursors don't survive a restart and aren't accepted by other instances.
+func WithCursorSecret(secret []byte) Option {
+	return func(exp *Explorer) error {
+		if len(secret) == 0 {
+			return fmt.Errorf("empty cursor secret")
+		}
+
+		exp.cursorSecret = secret
+		return nil
+	}
+}
+
+func randomSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// Cursor is the payload of a keyset pagination token: the sort key of the last returned row and
+// a fingerprint of the sort and filters it was produced for.
+type Cursor struct {
+	Query string `json:"q"`
+	After []any  `json:"a"`
+}
+
+// keysetFields returns the sort fields of a cursor page with the primary key as the final tie-breaker,
+// in the same order as orderBy.
+func (exp Explorer) keysetFields(table string, sort []SortField) ([]SortField, error) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.PrimaryKey == "" {
+		return nil, fmt.Errorf("cursor pagination requires a primary key")
+	}
+
+	fields := make([]SortField, 0, len(sort)+1)
+	for _, field := range sort {
+		if field.Column == scoreColumn {
+			return nil, fmt.Errorf("cursor pagination does not support sort by %s", scoreColumn)
+		}
+
+		if field.Column == schema.PrimaryKey {
+			return append(fields, field), nil
+		}
+		fields = append(fields, field)
+	}
+
+	return append(fields, SortField{Column: schema.PrimaryKey}), nil
+}
+
+func cursorFingerprint(table string, listQuery ListQuery) string {
+	state, _ := json.Marshal(struct {
+		Table     string
+		Search    string
+		Sort      []SortField
+		Filters   []Filter
+		Expr      *FilterExpr
+		Partition string
+	}{table, listQuery.Search, listQuery.Sort, listQuery.Filters, listQuery.Expr, listQuery.Partition})
+
+	sum := sha256.Sum256(state)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func (exp Explorer) signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, exp.cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (exp Explorer) encodeCursor(cursor Cursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(exp.signCursor(payload)), nil
+}
+
+func (exp Explorer) decodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return cursor, errInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return cursor, errInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, exp.signCursor(payload)) {
+		return cursor, errInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil {
+		return cursor, errInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// parseCursor switches the list query to keyset pagination for ?cursor=. An empty cursor starts
+// from the first page, any other one must have been issued for the same table, sort and filters.
+func (exp Explorer) parseCursor(table string, query url.Values, listQuery *ListQuery) error {
+	if !query.Has("cursor") {
+		return nil
+	}
+
+	if query.Has("offset") {
+		return errCursorWithOffset
+	}
+
+	fields, err := exp.keysetFields(table, listQuery.Sort)
+	if err != nil {
+		return err
+	}
+
+	listQuery.Keyset = fields
+
+	token := query.Get("cursor")
+	if token == "" {
+		return nil
+	}
+
+	cursor, err := exp.decodeCursor(token)
+	if err != nil {
+		return err
+	}
+
+	if cursor.Query != cursorFingerprint(table, *listQuery) || len(cursor.After) != len(fields) {
+		return errCursorMismatch
+	}
+
+	for i, v := range cursor.After {
+		if number, ok := v.(json.Number); ok {
+			cursor.After[i] = number.String()
+		}
+	}
+	listQuery.After = cursor.After
+
+	return nil
+}
+
+// keysetCondition selects the rows following listQuery.After in the keyset order. NULL sort keys
+// compare as unknown, so a page ending on one is the last page.
+func (exp Explorer) keysetCondition(table string, listQuery ListQuery) (string, []any) {
+	alternatives := make([]string, len(listQuery.Keyset))
+	args := make([]any, 0)
+	for i, field := range listQuery.Keyset {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, exp.quoteColumn(table, listQuery.Keyset[j].Column)+" = ?")
+			args = append(args, listQuery.After[j])
+		}
+
+		operator := " > ?"
+		if field.Desc {
+			operator = " < ?"
+		}
+		terms = append(terms, exp.quoteColumn(table, field.Column)+operator)
+		args = append(args, listQuery.After[i])
+
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// nextCursor returns the cursor of the page after items, or "" when items is the last page.
+func (exp Explorer) nextCursor(table string, listQuery ListQuery, items []map[string]any) (string, error) {
+	if len(listQuery.Keyset) == 0 || len(items) == 0 || len(items) < listQuery.Pagination.Limit {
+		return "", nil
+	}
+
+	last := items[len(items)-1]
+	after := make([]any, len(listQuery.Keyset))
+	for i, field := range listQuery.Keyset {
+		after[i] = last[field.Column]
+	}
+
+	return exp.encodeCursor(Cursor{Query: cursorFingerprint(table, listQuery), After: after})
+}
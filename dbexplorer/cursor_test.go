@@ -0,0 +1,52 @@
+package dbexplorer
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCursorPagination(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id"}, {Name: "title"}}},
+	})
+	exp.cursorSecret = []byte("secret")
+
+	first, err := exp.parseListQuery("items", url.Values{"cursor": {""}, "sort": {"-title"}, "limit": {"2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first.Keyset, []SortField{{Column: "title", Desc: true}, {Column: "id"}}) {
+		t.Fatalf("unexpected keyset %+v", first.Keyset)
+	}
+
+	token, err := exp.nextCursor("items", first, []map[string]any{{"id": 3, "title": "b"}, {"id": 1, "title": "a"}})
+	if err != nil || token == "" {
+		t.Fatalf("expected a cursor, got %q, %v", token, err)
+	}
+
+	next, err := exp.parseListQuery("items", url.Values{"cursor": {token}, "sort": {"-title"}, "limit": {"2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(next.After, []any{"a", "1"}) {
+		t.Errorf("unexpected cursor position %#v", next.After)
+	}
+
+	condition, args := exp.keysetCondition("items", next)
+	if condition != "((`title` < ?) OR (`title` = ? AND `id` > ?))" || len(args) != 3 {
+		t.Errorf("unexpected condition %s %v", condition, args)
+	}
+
+	if _, err := exp.parseListQuery("items", url.Values{"cursor": {token}, "sort": {"title"}}); err != errCursorMismatch {
+		t.Errorf("expected mismatch for another sort, got %v", err)
+	}
+
+	if _, err := exp.parseListQuery("items", url.Values{"cursor": {"x" + token}, "sort": {"-title"}}); err != errInvalidCursor {
+		t.Errorf("expected tampered cursor to be rejected, got %v", err)
+	}
+
+	if _, err := exp.parseListQuery("items", url.Values{"cursor": {""}, "offset": {"5"}}); err != errCursorWithOffset {
+		t.Errorf("expected cursor with offset to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,1622 @@
+// Package dbexplorer serves the tables of a MySQL or PostgreSQL database as a JSON API over HTTP.
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+type Pagination struct {
+	Offset int
+	Limit  int
+}
+
+type Route struct {
+	Method  string
+	Pattern *regexp.Regexp
+	Handler http.HandlerFunc
+}
+
+type Router struct {
+	routes      []Route
+	middlewares []func(http.Handler) http.Handler
+	handler     http.Handler
+}
+
+type ValidationError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func NewValidationError(field string) ValidationError {
+	return ValidationError{
+		Field:  field,
+		Reason: reasonInvalidType,
+	}
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("field %s have %s", e.Field, e.Reason)
+}
+
+// ValidationErrors holds every invalid field of a form.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, v := range e {
+		messages[i] = v.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+type Response struct {
+	Response any           `json:"response"`
+	Meta     *ResponseMeta `json:"_meta,omitempty"`
+}
+
+type GetTableNamesResponse struct {
+	Tables []string `json:"tables"`
+}
+
+type GetTableItemsResponse struct {
+	Records    []map[string]any `json:"records"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type DeleteTableItemResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// UpdateTableItemResponse tells a record found but left as it was, matched 1 and changed 0, from a missing one.
+type UpdateTableItemResponse struct {
+	Updated int `json:"updated"`
+	Matched int `json:"matched"`
+	Changed int `json:"changed"`
+	// Record is the row after the update, with ?return=representation.
+	Record map[string]any `json:"record,omitempty"`
+}
+
+type GetTableItemResponse struct {
+	Record     map[string]any  `json:"record"`
+	References []ItemReference `json:"references,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error  string            `json:"error"`
+	Errors []ValidationError `json:"errors,omitempty"`
+	// Fields maps every invalid field to its reason, so forms can mark all of them at once.
+	Fields map[string]string `json:"fields,omitempty"`
+	Meta   *ResponseMeta     `json:"_meta,omitempty"`
+}
+
+func NewErrorResponse(err error) []byte {
+	return newErrorResponse(err, englishMessages, nil)
+}
+
+func newErrorResponse(err error, bundle MessageBundle, meta *ResponseMeta) []byte {
+	resp := ErrorResponse{
+		Error: bundle.translate(err),
+		Meta:  meta,
+	}
+
+	// the invalid fields are listed in errors and fields, the message stays the same for every form
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		resp.Error = bundle.format(MsgValidationFailed, nil)
+		resp.Errors = validationErrors
+		resp.Fields = make(map[string]string, len(validationErrors))
+		for _, e := range validationErrors {
+			if _, ok := resp.Fields[e.Field]; !ok {
+				resp.Fields[e.Field] = bundle.reason(e.Reason)
+			}
+		}
+	}
+
+	data, _ := json.Marshal(resp)
+	return data
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	bundle := englishMessages
+	if localized := responseBundle(w); localized != nil {
+		w.Header().Set("Content-Language", localized.lang)
+		bundle = localized.bundle
+	}
+
+	w.WriteHeader(status)
+	w.Write(newErrorResponse(err, bundle, responseMeta(w)))
+}
+
+func writeResponse(w http.ResponseWriter, result any) {
+	data, err := json.Marshal(Response{Response: result, Meta: responseMeta(w)})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(data)
+}
+
+func NewRouter() *Router {
+	return &Router{
+		routes: make([]Route, 0),
+	}
+}
+
+func (r *Router) Handle(method string, pattern string, handler http.HandlerFunc) {
+	re := regexp.MustCompile("^" + pattern + "$")
+	r.routes = append(r.routes, Route{Method: method, Pattern: re, Handler: handler})
+}
+
+// Use wraps every route in the middlewares, the first one is the outermost. Call it before serving requests.
+func (r *Router) Use(middlewares ...func(http.Handler) http.Handler) {
+	r.middlewares = append(r.middlewares, middlewares...)
+
+	var handler http.Handler = http.HandlerFunc(r.dispatch)
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	r.handler = handler
+}
+
+type Explorer struct {
+	DB               *sql.DB
+	schema           *schemaCache
+	router           *Router
+	handler          http.Handler
+	ipAllowlist      *ipAllowlist
+	authenticators   []Authenticator
+	authExemptPaths  map[string]bool
+	sessions         *sessionStore
+	tokenAuth        bool
+	adminRole        string
+	columnWriteRoles map[string]map[string][]string
+	auditLog         bool
+	approval         *writeApproval
+	dialect          Dialect
+	metaTables       []metaTable
+	displayColumns   map[string]string
+	levenshtein      *levenshteinFunc
+	sqliteDriver     string
+	numbersAsStrings bool
+	jobs             *jobManager
+	scheduler        *scheduler
+	metrics          *metrics
+	retentionRules   []RetentionRule
+	invalidation     *invalidation
+	leader           *leaderElection
+	messageBundles   map[string]MessageBundle
+	queryOptions     *QueryOptions
+	errorReporter    ErrorReporter
+	schemaChangelog  time.Duration
+	cursorSecret     []byte
+	schemaRefresh    time.Duration
+	returning        bool
+	prefix           string
+	defaultLimit     int
+	backend          Backend
+	primaryKeys      map[string]string
+	requestLogger    RequestLogger
+	access           *accessControl
+	readOnly         bool
+	rateLimiter      *rateLimiter
+	authFailures     *rateLimiter
+	tableLimits      map[string]*tableLimiter
+	gzip             bool
+	queryProfiles    []QueryProfile
+	swaggerUI        bool
+	idGenerators     map[string]IDGenerator
+	ui               bool
+	journal          Journal
+	savedViews       bool
+	statsRefresh     time.Duration
+	slowQueries      *slowQueryLog
+	schemaRules      *schemaRules
+	strictStatus     bool
+	authorizer       Authorizer
+	strictFields     bool
+	lockTTL          time.Duration
+	maxBodySize      int64
+	maxLimit         int
+}
+
+type ValidationOptions struct {
+	IgnorePk               bool
+	IgnoreNotProvidedField bool
+	WithDefaultValues      bool
+	ColumnWritable         func(column string) bool
+	// IncludePk requires the primary key like a regular column, for tables with keys not generated by the database.
+	IncludePk bool
+}
+
+var (
+	integerTypes = []string{"TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "YEAR", "INT2", "INT4", "INT8"}
+	floatTypes   = []string{"FLOAT", "DOUBLE", "REAL", "FLOAT4", "FLOAT8"}
+	decimalTypes = []string{"DECIMAL", "NUMERIC", "NUMBER"}
+)
+
+func hasType(types []string, columnType string) bool {
+	columnType = strings.TrimPrefix(columnType, "UNSIGNED ")
+	for _, v := range types {
+		if v == columnType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNumberType(columnType string) bool {
+	return isIntegerType(columnType) || hasType(floatTypes, columnType) || hasType(decimalTypes, columnType)
+}
+
+func isIntegerType(columnType string) bool {
+	return hasType(integerTypes, columnType)
+}
+
+// isPreciseType reports whether values of the type may not fit into a float64 without losing precision.
+func isPreciseType(columnType string) bool {
+	return hasType(decimalTypes, columnType) || hasType([]string{"BIGINT", "INT8"}, columnType)
+}
+
+// convertValue turns a scanned value into its JSON representation. Drivers return numbers as text
+// in some cases, those are parsed back unless precise numbers are kept as strings.
+func (exp Explorer) convertValue(columnType string, value any) any {
+	value = normalizeValue(value)
+	if value == nil || !isNumberType(columnType) {
+		return value
+	}
+
+	if exp.numbersAsStrings && isPreciseType(columnType) {
+		return fmt.Sprint(value)
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	if isIntegerType(columnType) {
+		if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return n
+		}
+	}
+
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f
+	}
+
+	return value
+}
+
+// WithNumbersAsStrings returns DECIMAL and BIGINT values as strings, so JSON clients parsing numbers
+// as float64 don't lose precision.
+func WithNumbersAsStrings() Option {
+	return func(exp *Explorer) error {
+		exp.numbersAsStrings = true
+		return nil
+	}
+}
+
+func getDefaultValue(dbTypeName string) any {
+	if isNumberType(dbTypeName) {
+		return 0
+	}
+
+	if isStringType(dbTypeName) {
+		return ""
+	}
+
+	return false
+}
+
+func isStringType(columnType string) bool {
+	types := []string{
+		"VARCHAR",
+		"TEXT",
+		"NVARCHAR",
+	}
+
+	for _, v := range types {
+		if v == columnType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (exp Explorer) getTableItems(ctx context.Context, table string, listQuery ListQuery) ([]map[string]any, error) {
+	res := make([]map[string]any, 0)
+
+	query, args, err := exp.planListQuery(ctx, table, listQuery)
+	if err != nil {
+		return res, err
+	}
+
+	defer exp.recordSlowQuery(table, listQuery, time.Now())
+
+	rows, release, err := exp.queryContext(ctx, query, args...)
+	if err != nil {
+		return res, err
+	}
+
+	defer release()
+	defer rows.Close()
+
+	res, err = exp.scanRows(rows)
+	if err != nil {
+		return res, err
+	}
+
+	exp.renderColumns(table, res...)
+	exp.countReturned(table, len(res))
+	addRowsScanned(ctx, len(res))
+
+	return res, nil
+}
+
+// scanRows reads all rows into maps keyed by the column names.
+func (exp Explorer) scanRows(rows *sql.Rows) ([]map[string]any, error) {
+	res := make([]map[string]any, 0)
+
+	err := exp.eachRow(rows, func(item map[string]any) error {
+		res = append(res, item)
+		return nil
+	})
+
+	return res, err
+}
+
+// eachRow calls fn with every row as a map keyed by the column names, as the rows are read.
+func (exp Explorer) eachRow(rows *sql.Rows, fn func(item map[string]any) error) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		for i := range values {
+			if isStringType(columnTypes[i].DatabaseTypeName()) {
+				values[i] = new(sql.NullString)
+			} else {
+				values[i] = new(any)
+			}
+		}
+
+		if err := rows.Scan(values...); err != nil {
+			return err
+		}
+
+		item := make(map[string]any)
+		for i, v := range values {
+			if exp.hiddenColumn(columns[i]) {
+				continue
+			}
+
+			name := exp.apiColumn(columns[i])
+			strOrNil, ok := v.(*sql.NullString)
+			if ok {
+				if strOrNil.Valid {
+					item[name] = strOrNil.String
+				} else {
+					item[name] = nil
+				}
+			} else {
+				item[name] = exp.convertValue(columnTypes[i].DatabaseTypeName(), *v.(*any))
+			}
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (exp Explorer) getTableNames() ([]string, error) {
+	tableNames := make([]string, 0)
+
+	rows, err := exp.db().Query(exp.dialect.ListTablesQuery())
+	if err != nil {
+		return tableNames, nil
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return tableNames, err
+		}
+
+		if strings.HasPrefix(name, metaTablePrefix) || exp.schemaRules.tableExcluded(name) {
+			continue
+		}
+
+		tableNames = append(tableNames, name)
+	}
+
+	return tableNames, nil
+}
+
+// New reads the tables of db and returns the explorer serving them over HTTP.
+func New(db *sql.DB, opts ...Option) (*Explorer, error) {
+	explorer := Explorer{
+		DB:              db,
+		dialect:         MySQLDialect{},
+		router:          NewRouter(),
+		schema:          newSchemaCache(nil, nil),
+		authExemptPaths: make(map[string]bool),
+		adminRole:       "admin",
+		jobs:            newJobManager(),
+		scheduler:       newScheduler(),
+		metrics:         newMetrics(),
+		invalidation:    newInvalidation(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(&explorer); err != nil {
+			return nil, err
+		}
+	}
+
+	if explorer.backend != nil && explorer.auditLog {
+		return nil, fmt.Errorf("the audit log can't be combined with WithBackend")
+	}
+
+	if len(explorer.authenticators) > 0 && explorer.authFailures == nil {
+		explorer.authFailures = newRateLimiter(defaultAuthFailureLimit)
+	}
+
+	if explorer.cursorSecret == nil {
+		secret, err := randomSecret()
+		if err != nil {
+			return nil, err
+		}
+		explorer.cursorSecret = secret
+	}
+
+	if err := explorer.initMetaTables(); err != nil {
+		return nil, err
+	}
+
+	if err := explorer.RefreshSchema(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING only saves the reads of the audit log, which a backend doesn't have
+	if explorer.backend == nil {
+		returning, err := explorer.detectReturning()
+		if err != nil {
+			return nil, err
+		}
+		explorer.returning = returning
+	}
+
+	if err := explorer.initRetention(); err != nil {
+		return nil, err
+	}
+
+	if err := explorer.initSchemaChangelog(); err != nil {
+		return nil, err
+	}
+
+	explorer.initStatsCache()
+	explorer.initMetrics()
+	explorer.initSchemaRefresh()
+	explorer.initCluster()
+	explorer.initLeader()
+	explorer.initRoutes()
+	explorer.handler = explorer.buildHandler()
+	explorer.scheduler.start()
+
+	return &explorer, nil
+}
+
+// buildHandler wraps the router, and with it the middlewares of WithMiddleware, in the built-in chain.
+// From the outside in: gzip, size metrics, body limit, logging, response meta, error reporting,
+// locale, IP allowlist, authentication, read-only, rate limits, table limits, permissions, journal
+// and invalidation.
+func (exp Explorer) buildHandler() http.Handler {
+	var handler http.Handler = exp.router
+
+	handler = exp.invalidationMiddleware(handler)
+	if exp.journal != nil {
+		handler = exp.journalMiddleware(handler)
+	}
+	handler = exp.permissionMiddleware(handler)
+
+	if len(exp.tableLimits) > 0 {
+		handler = exp.tableLimitsMiddleware(handler)
+	}
+
+	if exp.rateLimiter != nil {
+		handler = exp.rateLimitMiddleware(handler)
+	}
+
+	if exp.readOnly {
+		handler = exp.readOnlyMiddleware(handler)
+	}
+
+	if len(exp.authenticators) > 0 {
+		handler = exp.authMiddleware(handler)
+	}
+
+	if exp.ipAllowlist != nil {
+		handler = exp.ipAllowlist.middleware(handler)
+	}
+
+	if len(exp.messageBundles) > 0 {
+		handler = exp.localeMiddleware(handler)
+	}
+
+	if exp.errorReporter != nil {
+		handler = exp.errorReportingMiddleware(handler)
+	}
+
+	handler = metaMiddleware(handler)
+
+	if exp.requestLogger != nil {
+		handler = exp.loggingMiddleware(handler)
+	}
+
+	handler = exp.bodyLimitMiddleware(handler)
+	handler = exp.sizeMiddleware(handler)
+
+	if exp.gzip {
+		handler = gzipMiddleware(handler)
+	}
+
+	return handler
+}
+
+func (exp Explorer) initRoutes() {
+	if exp.sessions != nil {
+		exp.router.Handle(http.MethodPost, "/_login", exp.handlerLogin)
+		exp.router.Handle(http.MethodPost, "/_logout", exp.handlerLogout)
+	}
+
+	if exp.approval != nil {
+		exp.router.Handle(http.MethodGet, "/_changes", exp.handlerGetChanges)
+		exp.router.Handle(http.MethodGet, `/_changes/[0-9]+`, exp.handlerGetChange)
+		exp.router.Handle(http.MethodPost, `/_changes/[0-9]+/approve`, exp.handlerApproveChange)
+		exp.router.Handle(http.MethodPost, `/_changes/[0-9]+/reject`, exp.handlerRejectChange)
+	}
+
+	if exp.tokenAuth {
+		exp.router.Handle(http.MethodGet, "/_tokens", exp.handlerGetTokens)
+		exp.router.Handle(http.MethodGet, `/_tokens/[0-9]+`, exp.handlerGetToken)
+		exp.router.Handle(http.MethodPut, "/_tokens/", exp.handlerCreateToken)
+		exp.router.Handle(http.MethodPost, `/_tokens/[0-9]+`, exp.handlerUpdateToken)
+		exp.router.Handle(http.MethodDelete, `/_tokens/[0-9]+`, exp.handlerRevokeToken)
+	}
+
+	if exp.savedViews {
+		exp.router.Handle(http.MethodGet, "/_views", exp.handlerGetViews)
+		exp.router.Handle(http.MethodGet, `/_views/[0-9]+`, exp.handlerGetView)
+		exp.router.Handle(http.MethodPut, "/_views/", exp.handlerCreateView)
+		exp.router.Handle(http.MethodPost, `/_views/[0-9]+`, exp.handlerUpdateView)
+		exp.router.Handle(http.MethodDelete, `/_views/[0-9]+`, exp.handlerDeleteView)
+	}
+
+	if exp.slowQueries != nil {
+		exp.router.Handle(http.MethodGet, "/_suggestions/indexes", exp.handlerGetIndexSuggestions)
+	}
+
+	exp.router.Handle(http.MethodGet, "/_health", exp.handlerHealth)
+	exp.router.Handle(http.MethodGet, "/_export/sqlite", exp.handlerExportSQLite)
+	exp.router.Handle(http.MethodGet, "/_metrics", exp.handlerGetMetrics)
+	exp.router.Handle(http.MethodGet, "/_schema", exp.handlerGetSchema)
+	exp.router.Handle(http.MethodGet, `/_openapi\.json`, exp.handlerGetOpenAPI)
+	if exp.swaggerUI {
+		exp.router.Handle(http.MethodGet, "/_docs", exp.handlerSwaggerUI)
+	}
+	if exp.ui {
+		exp.router.Handle(http.MethodGet, "/_ui/?", exp.handlerUI)
+	}
+	exp.router.Handle(http.MethodGet, "/_schema/changes", exp.handlerGetSchemaChanges)
+	exp.router.Handle(http.MethodPost, "/_admin/schema/refresh", exp.handlerRefreshSchema)
+	exp.router.Handle(http.MethodPost, "/_tx", exp.handlerTx)
+	exp.router.Handle(http.MethodPost, "/_query", exp.handlerQuery)
+	exp.router.Handle(http.MethodGet, "/graphql", exp.handlerGraphQL)
+	exp.router.Handle(http.MethodPost, "/graphql", exp.handlerGraphQL)
+	exp.router.Handle(http.MethodGet, "/_jobs", exp.handlerGetJobs)
+	exp.router.Handle(http.MethodGet, `/_jobs/\w+`, exp.handlerGetJob)
+	exp.router.Handle(http.MethodPost, `/\w+/_validate`, exp.handlerValidateItem)
+	exp.router.Handle(http.MethodPost, `/\w+/_import`, exp.handlerImport)
+	exp.router.Handle(http.MethodPost, `/\w+/_merge`, exp.handlerMerge)
+	exp.router.Handle(http.MethodPost, `/\w+/_archive`, exp.handlerArchive)
+	exp.router.Handle(http.MethodPost, `/\w+/[^/]+/_clone`, exp.handlerClone)
+	exp.router.Handle(http.MethodPost, `/\w+/[^/]+/_undo`, exp.handlerUndo)
+	if exp.lockTTL > 0 {
+		exp.router.Handle(http.MethodPost, `/\w+/[^/]+/_lock`, exp.handlerLockItem)
+		exp.router.Handle(http.MethodGet, `/\w+/[^/]+/_lock`, exp.handlerGetLock)
+		exp.router.Handle(http.MethodDelete, `/\w+/[^/]+/_lock`, exp.handlerUnlockItem)
+	}
+	exp.router.Handle(http.MethodGet, `/\w+/\w+/_options`, exp.handlerGetColumnOptions)
+	exp.router.Handle(http.MethodGet, `/\w+/\w+/_minmax`, exp.handlerGetMinMax)
+	exp.router.Handle(http.MethodGet, `/\w+/_timeseries`, exp.handlerGetTimeseries)
+	exp.router.Handle(http.MethodGet, `/\w+/_aggregate`, exp.handlerGetAggregate)
+	exp.router.Handle(http.MethodGet, `/\w+/_stats`, exp.handlerGetStats)
+	exp.router.Handle(http.MethodGet, `/\w+/_explain`, exp.handlerGetExplain)
+	exp.router.Handle(http.MethodGet, `/\w+/_schema`, exp.handlerGetTableSchema)
+	exp.router.Handle(http.MethodGet, `/\w+/count`, exp.handlerGetCount)
+
+	exp.router.Handle(http.MethodGet, "/", exp.handlerGetTableNames)
+	exp.router.Handle(http.MethodGet, `/\w*`, exp.handlerGetTableItems)
+	exp.router.Handle(http.MethodGet, `/\w*/[^/]*`, exp.handlerGetTableItem)
+	exp.router.Handle(http.MethodHead, `/\w+/[^/]+`, exp.handlerHeadItem)
+	exp.router.Handle(http.MethodGet, `/\w+/[^/]+/_exists`, exp.handlerItemExists)
+	exp.router.Handle(http.MethodGet, `/\w+/[^/]+/\w+`, exp.handlerGetRelated)
+	exp.router.Handle(http.MethodPut, `/\w+/bulk`, exp.handlerBulkInsert)
+	exp.router.Handle(http.MethodPost, `/\w+/aggregate`, exp.handlerPostAggregate)
+	exp.router.Handle(http.MethodPut, `/\w*/`, exp.handlerCreateItem)
+	exp.router.Handle(http.MethodDelete, `/\w+`, exp.handlerBulkDelete)
+	exp.router.Handle(http.MethodDelete, `/\w*/[^/]*`, exp.handlerDeleteItem)
+	exp.router.Handle(http.MethodPost, `/\w+`, exp.handlerBulkUpdate)
+	exp.router.Handle(http.MethodPost, `/\w*/[^/]*`, exp.handlerUpdateItem)
+}
+
+func (exp Explorer) updateItem(q queryer, table string, form map[string]any, columns []*sql.ColumnType, primaryKey string, pkValue any) (pk int64, err error) {
+	builder, err := exp.queryBuilder(table)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args, err := builder.update(form, primaryKey, pkValue)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := q.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	pk, err = result.RowsAffected()
+
+	return pk, err
+}
+
+func isValidValue(dbTypeName string, nullable bool, value any) bool {
+	switch v := value.(type) {
+	case float64:
+		if isIntegerType(dbTypeName) {
+			return v == math.Trunc(v) && (v >= 0 || !strings.HasPrefix(dbTypeName, "UNSIGNED "))
+		}
+		return isNumberType(dbTypeName)
+	case string:
+		// precise numbers are accepted as strings the way they are returned with WithNumbersAsStrings
+		if isPreciseType(dbTypeName) {
+			_, err := strconv.ParseFloat(v, 64)
+			return err == nil
+		}
+		return isStringType(dbTypeName)
+	case nil:
+		return nullable
+	}
+
+	return true
+}
+
+// formColumn is a column a form may set, under its API name.
+type formColumn struct {
+	Name     string
+	Type     string
+	Nullable bool
+}
+
+// formColumns returns the columns of the table from the result columns of the database, or from the
+// schema of the backend of WithBackend.
+func (exp Explorer) formColumns(table string) ([]formColumn, error) {
+	if exp.backend != nil {
+		schema, err := exp.getTableSchema(table)
+		if err != nil {
+			return nil, err
+		}
+
+		columns := make([]formColumn, len(schema.Columns))
+		for i, c := range schema.Columns {
+			columns[i] = formColumn{Name: c.Name, Type: strings.ToUpper(c.DataType), Nullable: c.Nullable}
+		}
+		return columns, nil
+	}
+
+	columnTypes, err := exp.getColumnTypesFromCache(table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]formColumn, len(columnTypes))
+	for i, c := range columnTypes {
+		nullable, err := exp.isNullable(table, c)
+		if err != nil {
+			return nil, err
+		}
+		columns[i] = formColumn{Name: exp.apiColumn(c.Name()), Type: c.DatabaseTypeName(), Nullable: nullable}
+	}
+	return columns, nil
+}
+
+func (exp Explorer) processForm(table string, form map[string]any, primaryKey string, validationOptions ValidationOptions) (map[string]any, error) {
+	newForm := make(map[string]any)
+	errs := make(ValidationErrors, 0)
+
+	columns, err := exp.formColumns(table)
+	if err != nil {
+		return newForm, err
+	}
+
+	for _, c := range columns {
+		name := c.Name
+		value, has := form[name]
+		nullable := c.Nullable
+
+		if name == primaryKey && validationOptions.IncludePk && !has {
+			errs = append(errs, ValidationError{Field: name, Reason: reasonRequired})
+			continue
+		}
+
+		if name == primaryKey && !validationOptions.IncludePk {
+			if has && !validationOptions.IgnorePk {
+				errs = append(errs, NewValidationError(name))
+			}
+			continue
+		}
+
+		if has {
+			if validationOptions.ColumnWritable != nil && !validationOptions.ColumnWritable(name) {
+				return newForm, NewColumnPermissionError(name)
+			}
+
+			if exp.isJSONColumn(table, name) {
+				if value == nil && !nullable {
+					errs = append(errs, NewValidationError(name))
+					continue
+				}
+
+				newForm[name], err = jsonColumnValue(value)
+				if err != nil {
+					errs = append(errs, NewValidationError(name))
+				}
+				continue
+			}
+
+			if exp.isBooleanColumn(table, name) {
+				if !isValidBoolean(nullable, value) {
+					errs = append(errs, NewValidationError(name))
+					continue
+				}
+			} else if _, ok := value.(bool); ok || !isValidValue(c.Type, nullable, value) {
+				errs = append(errs, NewValidationError(name))
+				continue
+			}
+
+			newForm[name] = value
+			continue
+		}
+
+		if validationOptions.IgnoreNotProvidedField {
+			continue
+		}
+
+		if !nullable {
+			if !validationOptions.WithDefaultValues {
+				errs = append(errs, ValidationError{Field: name, Reason: reasonRequired})
+				continue
+			}
+
+			if exp.isJSONColumn(table, name) {
+				newForm[name] = "null"
+				continue
+			}
+
+			newForm[name] = getDefaultValue(c.Type)
+			continue
+		}
+
+		newForm[name] = nil
+	}
+
+	if exp.strictFields {
+		names := make([]string, len(columns))
+		for i, c := range columns {
+			names[i] = c.Name
+		}
+		errs = append(errs, unknownFields(names, form)...)
+	}
+
+	if len(errs) > 0 {
+		return newForm, errs
+	}
+
+	return newForm, nil
+}
+
+func (exp Explorer) handlerUpdateItem(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	form := make(map[string]any)
+	err = decodeJSONBody(r, &form)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	id, err := exp.primaryKeyValue(tableName, exp.getId(r.URL.Path))
+	if err != nil {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	newForm, err := exp.processForm(tableName, form, primaryKey, ValidationOptions{
+		IgnorePk:               false,
+		IgnoreNotProvidedField: true,
+		ColumnWritable:         exp.columnWritable(r.Context(), tableName, PrincipalFromContext(r.Context())),
+	})
+
+	if err != nil {
+		exp.writeFormError(w, r, err)
+		return
+	}
+
+	op := writeOp{
+		Op:         writeUpdate,
+		Table:      tableName,
+		PrimaryKey: primaryKey,
+		ID:         id,
+		Form:       newForm,
+		LockToken:  r.Header.Get(lockTokenHeader),
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.submitChange(w, r, principal, op)
+		return
+	}
+
+	written, err := exp.runWrite(r.Context(), principal, op)
+	if err != nil {
+		writeWriteError(w, r, err)
+		return
+	}
+	addRowsAffected(r.Context(), written.Affected)
+
+	// MySQL doesn't count the rows an update leaves as they were, so they are looked up
+	matched := written.Affected > 0
+	if !matched {
+		matched, err = exp.itemExists(r.Context(), exp.db(), tableName, exp.getId(r.URL.Path))
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+
+	if exp.strictStatus && !matched {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	result := UpdateTableItemResponse{}
+	if written.Affected > 0 {
+		result.Updated = 1
+		result.Changed = 1
+	}
+	if matched {
+		result.Matched = 1
+	}
+	if wantsRepresentation(r) {
+		result.Record, err = exp.writtenRecord(r.Context(), w, op, written)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+	response := Response{
+		Response: result,
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (exp Explorer) handlerDeleteItem(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	pkName, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	id, err := exp.primaryKeyValue(tableName, exp.getId(r.URL.Path))
+	if err != nil {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	op := writeOp{
+		Op:         writeDelete,
+		Table:      tableName,
+		PrimaryKey: pkName,
+		ID:         id,
+		LockToken:  r.Header.Get(lockTokenHeader),
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.submitChange(w, r, principal, op)
+		return
+	}
+
+	written, err := exp.runWrite(r.Context(), principal, op)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+	addRowsAffected(r.Context(), written.Affected)
+
+	if exp.strictStatus && written.Affected == 0 {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	deleted := 0
+	if written.Affected > 0 {
+		deleted = 1
+	}
+
+	result := DeleteTableItemResponse{
+		Deleted: deleted,
+	}
+	response := Response{
+		Response: result,
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (exp Explorer) deleteItem(q queryer, table string, pkName string, pkValue any) (pk int64, err error) {
+	builder, err := exp.queryBuilder(table)
+	if err != nil {
+		return pk, err
+	}
+
+	query, err := builder.deleteByKey(pkName)
+	if err != nil {
+		return pk, err
+	}
+
+	result, err := q.Exec(query, pkValue)
+	if err != nil {
+		return pk, err
+	}
+
+	id, err := result.RowsAffected()
+	if err != nil {
+		return pk, err
+	}
+
+	return id, nil
+}
+
+func (exp Explorer) createItem(q queryer, table string, form map[string]any, columns []*sql.ColumnType, primaryKey string) (pk any, err error) {
+	builder, err := exp.queryBuilder(table)
+	if err != nil {
+		return 0, err
+	}
+
+	form, err = exp.generateID(table, form)
+	if err != nil {
+		return 0, err
+	}
+
+	query, values, err := builder.insert(form)
+	if err != nil {
+		return 0, err
+	}
+
+	if pkValue, ok := form[primaryKey]; ok {
+		_, err := q.Exec(query, values...)
+		return pkValue, err
+	}
+
+	lastInsertId, err := exp.insertReturningID(q, query, exp.dbColumn(table, primaryKey), values...)
+	if err != nil {
+		return 0, err
+	}
+
+	return lastInsertId, err
+}
+
+func (exp Explorer) getPrimaryKey(table string) (string, error) {
+	if column, ok := exp.primaryKeys[table]; ok {
+		return column, nil
+	}
+
+	if exp.backend != nil {
+		schema, err := exp.getTableSchema(table)
+		if err != nil {
+			return "", err
+		}
+
+		return schema.PrimaryKey, nil
+	}
+
+	rows, err := exp.db().Query(exp.dialect.PrimaryKeyQuery(), exp.dbName(table))
+	if err != nil {
+		return "", err
+	}
+
+	defer rows.Close()
+
+	var name string
+	for rows.Next() {
+		if err := rows.Scan(&name); err != nil {
+			return "", err
+		}
+
+	}
+
+	return exp.apiColumn(name), nil
+}
+
+func (exp Explorer) handlerCreateItem(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	form := make(map[string]any)
+	err = decodeJSONBody(r, &form)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	newForm, err := exp.processForm(tableName, form, primaryKey, ValidationOptions{
+		IgnorePk:               true,
+		IncludePk:              !exp.primaryKeyGenerated(tableName),
+		IgnoreNotProvidedField: false,
+		WithDefaultValues:      true,
+		ColumnWritable:         exp.columnWritable(r.Context(), tableName, PrincipalFromContext(r.Context())),
+	})
+	if err != nil {
+		exp.writeFormError(w, r, err)
+		return
+	}
+
+	op := writeOp{
+		Op:         writeCreate,
+		Table:      tableName,
+		PrimaryKey: primaryKey,
+		Form:       newForm,
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.submitChange(w, r, principal, op)
+		return
+	}
+
+	written, err := exp.runWrite(r.Context(), principal, op)
+	if err != nil {
+		writeWriteError(w, r, err)
+		return
+	}
+	addRowsAffected(r.Context(), written.Affected)
+
+	result := make(map[string]any)
+	result[primaryKey] = written.ID
+	if wantsRepresentation(r) {
+		record, err := exp.writtenRecord(r.Context(), w, op, written)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+		for column, value := range record {
+			result[column] = value
+		}
+	}
+
+	response := Response{
+		Response: result,
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (exp Explorer) handlerGetTableNames(w http.ResponseWriter, r *http.Request) {
+	tables, err := exp.Backend().ListTables(r.Context())
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	tableResponse := GetTableNamesResponse{
+		Tables: tables,
+	}
+
+	response := Response{
+		Response: tableResponse,
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (exp Explorer) isValidTableName(tableName string) bool {
+	for _, name := range exp.tableNames() {
+		if name == tableName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getQueryIntValue(query url.Values, key string, defaultValue int) int {
+	if !query.Has(key) {
+		return defaultValue
+	}
+
+	value := query.Get(key)
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+
+	return intValue
+}
+
+func (exp Explorer) getPagination(query url.Values) (Pagination, error) {
+	defaultLimit := exp.defaultLimit
+	if defaultLimit == 0 {
+		defaultLimit = defaultPageLimit
+	}
+	if exp.maxLimit > 0 && defaultLimit > exp.maxLimit {
+		defaultLimit = exp.maxLimit
+	}
+
+	pagination := Pagination{
+		Limit:  getQueryIntValue(query, "limit", defaultLimit),
+		Offset: getQueryIntValue(query, "offset", 0),
+	}
+	if pagination.Limit < 0 || pagination.Offset < 0 {
+		return pagination, fmt.Errorf("limit and offset must not be negative")
+	}
+	if exp.maxLimit > 0 && pagination.Limit > exp.maxLimit {
+		return pagination, fmt.Errorf("limit must not exceed %d", exp.maxLimit)
+	}
+
+	return pagination, nil
+}
+
+func (exp Explorer) getTableName(url string) (string, error) {
+	tableName := strings.Split(url, "/")[1]
+	if !exp.isValidTableName(tableName) {
+		return "", errUnknownTable
+	}
+
+	return tableName, nil
+}
+
+func (exp Explorer) getId(url string) string {
+	id := strings.Split(url, "/")[2]
+
+	return id
+}
+
+func (exp Explorer) handlerGetTableItems(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	listQuery, err := exp.parseListQuery(tableName, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	expand, ok := exp.requireExpand(w, r, tableName)
+	if !ok {
+		return
+	}
+	for _, fk := range expand {
+		listQuery.Fields = withColumns(listQuery.Fields, fk.Column)
+	}
+
+	if wantsNDJSON(r) {
+		if len(expand) > 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("expand is not supported for ndjson"))
+			return
+		}
+
+		// the stream holds a row at a time, so without ?limit= it is exempt from WithMaxLimit,
+		// but not from the max page size of the table
+		if !r.URL.Query().Has("limit") {
+			listQuery.Pagination.Limit = math.MaxInt
+			if maxPageSize := exp.maxPageSize(tableName); maxPageSize > 0 {
+				listQuery.Pagination.Limit = maxPageSize
+			}
+		}
+
+		exp.writeTableItemsNDJSON(w, r, tableName, listQuery)
+		return
+	}
+
+	if wantsCSV(r) {
+		if len(expand) > 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("expand is not supported for csv"))
+			return
+		}
+
+		exp.writeTableItemsCSV(w, r, tableName, listQuery)
+		return
+	}
+
+	items, err := exp.Backend().Query(r.Context(), tableName, listQuery)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	if err := exp.expandReferences(items, expand); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	nextCursor, err := exp.nextCursor(tableName, listQuery, items)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	exp.pickFields(tableName, listQuery.Fields, items...)
+
+	itemsResp := GetTableItemsResponse{
+		Records:    items,
+		NextCursor: nextCursor,
+	}
+
+	resp := Response{
+		Response: itemsResp,
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (exp Explorer) getColumnTypes(table string) ([]*sql.ColumnType, error) {
+	res := make([]*sql.ColumnType, 0)
+
+	rows, err := exp.db().Query(fmt.Sprintf("SELECT * FROM %s LIMIT 0", exp.quote(table)))
+	if err != nil {
+		return res, err
+	}
+
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return res, err
+	}
+
+	return columnTypes, nil
+}
+
+func (exp Explorer) getColumnTypesFromCache(table string) ([]*sql.ColumnType, error) {
+	exp.schema.mu.RLock()
+	columnTypes, ok := exp.schema.columns[table]
+	exp.schema.mu.RUnlock()
+	if !ok {
+		return columnTypes, fmt.Errorf("table=%s doesnt have cache", table)
+	}
+
+	return columnTypes, nil
+}
+
+func (exp Explorer) getItem(q queryer, table string, pkName string, pkValue any) (map[string]any, error) {
+	return exp.getItemColumns(q, table, pkName, pkValue, nil)
+}
+
+// getItemColumns reads only the columns of the record, all of them for none.
+func (exp Explorer) getItemColumns(q queryer, table string, pkName string, pkValue any, columns []string) (map[string]any, error) {
+	res := make(map[string]any)
+
+	builder, err := exp.queryBuilder(table)
+	if err != nil {
+		return res, err
+	}
+
+	columnTypes, err := exp.getColumnTypesFromCache(table)
+	if err != nil {
+		return res, err
+	}
+
+	// SELECT * would return the columns hidden by the schema rules too
+	if columns == nil && exp.schemaRules != nil {
+		columns = make([]string, len(columnTypes))
+		for i, columnType := range columnTypes {
+			columns[i] = exp.apiColumn(columnType.Name())
+		}
+	}
+
+	query, err := builder.selectByKey(pkName, columns...)
+	if err != nil {
+		return res, err
+	}
+
+	row := q.QueryRow(query, pkValue)
+	if row.Err() != nil {
+		return res, row.Err()
+	}
+
+	if columns != nil {
+		selected := make([]*sql.ColumnType, 0, len(columns))
+		for _, name := range columns {
+			for _, columnType := range columnTypes {
+				if exp.apiColumn(columnType.Name()) == name {
+					selected = append(selected, columnType)
+				}
+			}
+		}
+		columnTypes = selected
+	}
+
+	values := make([]any, len(columnTypes))
+	for i := range values {
+		if isStringType(columnTypes[i].DatabaseTypeName()) {
+			values[i] = new(sql.NullString)
+		} else {
+			values[i] = new(any)
+		}
+	}
+
+	err = row.Scan(values...)
+	if err != nil {
+		return res, err
+	}
+
+	for i, v := range values {
+		strOrNil, ok := v.(*sql.NullString)
+		if ok {
+			if strOrNil.Valid {
+				res[exp.apiColumn(columnTypes[i].Name())] = strOrNil.String
+			} else {
+				res[exp.apiColumn(columnTypes[i].Name())] = nil
+			}
+		} else {
+			res[exp.apiColumn(columnTypes[i].Name())] = exp.convertValue(columnTypes[i].DatabaseTypeName(), *v.(*any))
+		}
+	}
+
+	exp.renderColumns(table, res)
+
+	return res, nil
+}
+
+func (exp Explorer) handlerGetTableItem(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	pkName, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	pkValue, err := exp.primaryKeyValue(tableName, exp.getId(r.URL.Path))
+	if err != nil {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	expand, ok := exp.requireExpand(w, r, tableName)
+	if !ok {
+		return
+	}
+
+	fields, err := exp.parseFields(tableName, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	for _, fk := range expand {
+		fields = withColumns(fields, fk.Column)
+	}
+
+	// the references are counted by the values of all referenced columns
+	columns := fields
+	if r.URL.Query().Get("include_refs") == "true" {
+		columns = nil
+	}
+
+	var item map[string]any
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		at, err := time.Parse(time.RFC3339, asOf)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("as_of must be an RFC 3339 time"))
+			return
+		}
+
+		item, err = exp.itemAsOf(tableName, pkName, pkValue, at)
+		if errors.Is(err, errAsOfWithoutAudit) {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	} else if exp.backend != nil {
+		item, err = exp.backendItem(r.Context(), tableName, pkName, pkValue, columns)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	} else {
+		item, err = exp.getItemColumns(exp.db(), tableName, pkName, pkValue, columns)
+		if err != nil {
+			item = nil
+		}
+	}
+
+	if item == nil {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+	exp.countReturned(tableName, 1)
+	addRowsScanned(r.Context(), 1)
+
+	res := GetTableItemResponse{
+		Record: item,
+	}
+
+	if r.URL.Query().Get("include_refs") == "true" {
+		res.References, err = exp.itemReferences(r.Context(), PrincipalFromContext(r.Context()), tableName, exp.getId(r.URL.Path), item)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+
+	if err := exp.expandReferences([]map[string]any{item}, expand); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	exp.pickFields(tableName, fields, item)
+
+	resp := Response{
+		Response: res,
+	}
+
+	data, _ := json.Marshal(resp)
+	w.Write(data)
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.handler != nil {
+		r.handler.ServeHTTP(w, req)
+		return
+	}
+
+	r.dispatch(w, req)
+}
+
+func (r *Router) dispatch(w http.ResponseWriter, req *http.Request) {
+	for _, route := range r.routes {
+		if route.Method != req.Method {
+			continue
+		}
+
+		if route.Pattern.MatchString(req.URL.Path) {
+			route.Handler(w, req)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (exp Explorer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if exp.prefix != "" {
+		path, ok := strings.CutPrefix(r.URL.Path, exp.prefix)
+		if !ok || path != "" && path[0] != '/' {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if path == "" {
+			path = "/"
+		}
+
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = path
+		r2.URL.RawPath = ""
+		r = r2
+	}
+
+	exp.handler.ServeHTTP(w, r)
+}
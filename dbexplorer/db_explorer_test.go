@@ -0,0 +1,202 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestIsValidValueNumbers(t *testing.T) {
+	cases := []struct {
+		dbTypeName string
+		value      any
+		expected   bool
+	}{
+		{"INT", float64(42), true},
+		{"INT", 4.2, false},
+		{"UNSIGNED BIGINT", float64(-1), false},
+		{"DOUBLE", 4.2, true},
+		{"DECIMAL", "12345678901234567.89", true},
+		{"DECIMAL", "abc", false},
+		{"BIGINT", "9007199254740993", true},
+		{"INT", "42", false},
+		{"VARCHAR", float64(42), false},
+	}
+
+	for _, c := range cases {
+		if got := isValidValue(c.dbTypeName, false, c.value); got != c.expected {
+			t.Errorf("%s %#v: expected %v, got %v", c.dbTypeName, c.value, c.expected, got)
+		}
+	}
+}
+
+func TestConvertValue(t *testing.T) {
+	exp := Explorer{}
+	precise := Explorer{numbersAsStrings: true}
+
+	cases := []struct {
+		exp        Explorer
+		dbTypeName string
+		value      any
+		expected   any
+	}{
+		{exp, "INT", []byte("42"), int64(42)},
+		{exp, "DECIMAL", []byte("1.50"), 1.5},
+		{exp, "VARCHAR", []byte("42"), "42"},
+		{exp, "BIGINT", nil, nil},
+		{precise, "DECIMAL", []byte("1.50"), "1.50"},
+		{precise, "BIGINT", int64(9007199254740993), "9007199254740993"},
+		{precise, "INT", int64(42), int64(42)},
+	}
+
+	for _, c := range cases {
+		if got := c.exp.convertValue(c.dbTypeName, c.value); got != c.expected {
+			t.Errorf("%s %#v: expected %#v, got %#v", c.dbTypeName, c.value, c.expected, got)
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	exp := Explorer{
+		prefix: "/api",
+		handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path))
+		}),
+	}
+
+	for path, expected := range map[string]string{
+		"/api/items/1": "/items/1",
+		"/api":         "/",
+		"/apix/items":  "",
+		"/items":       "",
+	} {
+		rec := httptest.NewRecorder()
+		exp.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Body.String() != expected || expected == "" && rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected %q, got %d %q", path, expected, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestPaginationLimits(t *testing.T) {
+	exp := Explorer{}
+	for _, option := range []Option{WithDefaultLimit(20), WithMaxLimit(10)} {
+		if err := option(&exp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		query    url.Values
+		expected int
+		valid    bool
+	}{
+		{url.Values{}, 10, true},
+		{url.Values{"limit": {"10"}}, 10, true},
+		{url.Values{"limit": {"1000000"}}, 0, false},
+		{url.Values{"limit": {"-5"}}, 0, false},
+		{url.Values{"offset": {"-1"}}, 0, false},
+	}
+
+	for _, c := range cases {
+		pagination, err := exp.getPagination(c.query)
+		if (err == nil) != c.valid {
+			t.Errorf("%v: expected valid %v, got %v", c.query, c.valid, err)
+			continue
+		}
+		if c.valid && pagination.Limit != c.expected {
+			t.Errorf("%v: expected limit %d, got %d", c.query, c.expected, pagination.Limit)
+		}
+	}
+
+	if p, err := (Explorer{}).getPagination(url.Values{"limit": {"1000000"}}); err != nil || p.Limit != 1000000 {
+		t.Errorf("expected no cap by default, got %d, %v", p.Limit, err)
+	}
+}
+
+func TestRouterUse(t *testing.T) {
+	router := NewRouter()
+	router.Handle(http.MethodGet, "/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("items"))
+	})
+
+	tag := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Middleware", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	router.Use(tag("outer"))
+	router.Use(tag("inner"))
+
+	cases := []struct {
+		path     string
+		expected int
+	}{
+		{"/items", http.StatusOK},
+		{"/unknown", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.path, nil))
+
+		if w.Code != c.expected {
+			t.Errorf("%s: expected %d, got %d", c.path, c.expected, w.Code)
+		}
+		if got := w.Header().Values("X-Middleware"); len(got) != 2 || got[0] != "outer" || got[1] != "inner" {
+			t.Errorf("%s: expected outer then inner, got %v", c.path, got)
+		}
+	}
+}
+
+func TestMiddlewareRunsAfterAuthentication(t *testing.T) {
+	exp := newTestExplorer(nil)
+	exp.router.Handle(http.MethodGet, "/_ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var seen []string
+	options := []Option{
+		WithAuthenticator(AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+			if r.Header.Get("X-User") == "" {
+				return nil, nil
+			}
+			return &Principal{Name: r.Header.Get("X-User")}, nil
+		})),
+		WithMiddleware(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal := PrincipalFromContext(r.Context())
+				if principal == nil {
+					t.Errorf("expected the principal to be set before the middleware")
+				} else {
+					seen = append(seen, principal.Name)
+				}
+				next.ServeHTTP(w, r)
+			})
+		}),
+	}
+	for _, option := range options {
+		if err := option(&exp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := exp.buildHandler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/_ping", nil))
+	if w.Code != http.StatusUnauthorized || len(seen) != 0 {
+		t.Errorf("expected anonymous requests to be rejected before the middleware, got %d %v", w.Code, seen)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/_ping", nil)
+	r.Header.Set("X-User", "alice")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || len(seen) != 1 || seen[0] != "alice" {
+		t.Errorf("expected the middleware to see alice, got %d %v", w.Code, seen)
+	}
+}
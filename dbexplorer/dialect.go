@@ -0,0 +1,491 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Dialect hides the differences of SQL databases from the explorer.
+// Queries are written with "?" placeholders and rebound to the dialect placeholder style before execution.
+type Dialect interface {
+	QuoteIdent(name string) string
+	Placeholder(n int) string
+	// CurrentSchema is an SQL expression of the schema tables are looked up in.
+	CurrentSchema() string
+	ListTablesQuery() string
+	// PrimaryKeyQuery, ColumnsQuery and ForeignKeysQuery take the table name as the only argument.
+	PrimaryKeyQuery() string
+	ColumnsQuery() string
+	ForeignKeysQuery() string
+	// MetaColumnType maps column kinds of the explorer meta tables (serial, bigint, int, string, text, time) to SQL types.
+	MetaColumnType(kind string) string
+	// InsertReturning reports whether inserted keys are read with INSERT ... RETURNING instead of LastInsertId.
+	InsertReturning() bool
+	// IsBoolean reports whether the column holds booleans.
+	IsBoolean(column ColumnInfo) bool
+	// TableCommentQuery takes the table name and returns its comment.
+	TableCommentQuery() string
+	// PartitionsQuery lists name, method, expression, description and row estimate of the table partitions.
+	PartitionsQuery() string
+	// PartitionSource is the FROM clause reading only the given partitions of the table.
+	PartitionSource(table string, partitions []string) string
+	// CreateTableLike creates the table with the columns and indexes of the source table unless it exists.
+	CreateTableLike(table string, source string) string
+	// TimeBucket is an SQL expression truncating the time column to the start of its bucket of the given size.
+	TimeBucket(column string, seconds int64) string
+	// TryLockQuery takes a lock name and returns whether the session advisory lock was acquired without waiting.
+	TryLockQuery() string
+	// UnlockQuery releases the session advisory lock with the name given as the only argument.
+	UnlockQuery() string
+}
+
+// WithDialect sets the SQL dialect of the database, MySQLDialect by default.
+func WithDialect(dialect Dialect) Option {
+	return func(exp *Explorer) error {
+		exp.dialect = dialect
+		return nil
+	}
+}
+
+type MySQLDialect struct{}
+
+func (MySQLDialect) QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (MySQLDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (MySQLDialect) CurrentSchema() string {
+	return "DATABASE()"
+}
+
+func (MySQLDialect) ListTablesQuery() string {
+	return "SHOW TABLES"
+}
+
+func (MySQLDialect) PrimaryKeyQuery() string {
+	return `SELECT COLUMN_NAME
+    FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+    WHERE TABLE_NAME = ?
+      AND CONSTRAINT_NAME = 'PRIMARY'
+      AND TABLE_SCHEMA = DATABASE()`
+}
+
+func (MySQLDialect) ColumnsQuery() string {
+	return `SELECT COLUMN_NAME, DATA_TYPE, COLUMN_TYPE, CHARACTER_MAXIMUM_LENGTH, IS_NULLABLE,
+       COLUMN_DEFAULT, COLUMN_KEY, EXTRA, COLUMN_COMMENT
+    FROM INFORMATION_SCHEMA.COLUMNS
+    WHERE TABLE_SCHEMA = DATABASE()
+      AND TABLE_NAME = ?
+    ORDER BY ORDINAL_POSITION`
+}
+
+func (MySQLDialect) ForeignKeysQuery() string {
+	return `SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+    FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+    WHERE TABLE_SCHEMA = DATABASE()
+      AND TABLE_NAME = ?
+      AND REFERENCED_TABLE_NAME IS NOT NULL
+    ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`
+}
+
+func (MySQLDialect) MetaColumnType(kind string) string {
+	switch kind {
+	case "serial":
+		return "bigint NOT NULL AUTO_INCREMENT"
+	case "text":
+		return "longtext"
+	case "time":
+		return "datetime(6)"
+	case "string":
+		return "varchar(255)"
+	}
+
+	return kind
+}
+
+func (MySQLDialect) InsertReturning() bool {
+	return false
+}
+
+// IsBoolean treats TINYINT(1), the type of BOOLEAN columns, and BIT(1) as booleans.
+func (MySQLDialect) IsBoolean(column ColumnInfo) bool {
+	columnType := strings.ToLower(column.ColumnType)
+	return strings.HasPrefix(columnType, "tinyint(1)") || columnType == "bit(1)"
+}
+
+func (MySQLDialect) TableCommentQuery() string {
+	return `SELECT TABLE_COMMENT
+    FROM INFORMATION_SCHEMA.TABLES
+    WHERE TABLE_SCHEMA = DATABASE()
+      AND TABLE_NAME = ?`
+}
+
+func (MySQLDialect) PartitionsQuery() string {
+	return `SELECT PARTITION_NAME, PARTITION_METHOD, COALESCE(PARTITION_EXPRESSION, ''),
+       COALESCE(PARTITION_DESCRIPTION, ''), COALESCE(TABLE_ROWS, 0)
+    FROM INFORMATION_SCHEMA.PARTITIONS
+    WHERE TABLE_SCHEMA = DATABASE()
+      AND TABLE_NAME = ?
+      AND PARTITION_NAME IS NOT NULL
+    ORDER BY PARTITION_ORDINAL_POSITION`
+}
+
+func (d MySQLDialect) PartitionSource(table string, partitions []string) string {
+	quoted := make([]string, len(partitions))
+	for i, p := range partitions {
+		quoted[i] = d.QuoteIdent(p)
+	}
+
+	return fmt.Sprintf("%s PARTITION (%s)", d.QuoteIdent(table), strings.Join(quoted, ", "))
+}
+
+func (d MySQLDialect) CreateTableLike(table string, source string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s LIKE %s", d.QuoteIdent(table), d.QuoteIdent(source))
+}
+
+func (MySQLDialect) TimeBucket(column string, seconds int64) string {
+	return fmt.Sprintf("FROM_UNIXTIME(UNIX_TIMESTAMP(%s) DIV %d * %d)", column, seconds, seconds)
+}
+
+func (MySQLDialect) TryLockQuery() string {
+	return "SELECT GET_LOCK(?, 0) = 1"
+}
+
+func (MySQLDialect) UnlockQuery() string {
+	return "SELECT RELEASE_LOCK(?)"
+}
+
+// PostgresDialect works with lib/pq and pgx stdlib connections.
+type PostgresDialect struct{}
+
+func (PostgresDialect) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (PostgresDialect) Placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+func (PostgresDialect) CurrentSchema() string {
+	return "current_schema()"
+}
+
+func (PostgresDialect) ListTablesQuery() string {
+	return `SELECT table_name
+    FROM information_schema.tables
+    WHERE table_schema = current_schema()
+      AND table_type = 'BASE TABLE'
+    ORDER BY table_name`
+}
+
+func (PostgresDialect) PrimaryKeyQuery() string {
+	return `SELECT kcu.column_name
+    FROM information_schema.table_constraints tc
+    JOIN information_schema.key_column_usage kcu
+      ON kcu.constraint_name = tc.constraint_name
+     AND kcu.table_schema = tc.table_schema
+     AND kcu.table_name = tc.table_name
+    WHERE tc.table_name = ?
+      AND tc.constraint_type = 'PRIMARY KEY'
+      AND tc.table_schema = current_schema()`
+}
+
+func (PostgresDialect) ColumnsQuery() string {
+	// enum labels are rendered as enum('a','b') to match the MySQL column type format
+	return `SELECT c.column_name,
+       CASE WHEN e.labels IS NOT NULL THEN 'enum' ELSE c.data_type END,
+       CASE WHEN e.labels IS NOT NULL THEN 'enum(' || e.labels || ')' ELSE c.udt_name END,
+       c.character_maximum_length,
+       c.is_nullable,
+       c.column_default,
+       CASE WHEN pk.column_name IS NOT NULL THEN 'PRI' ELSE '' END,
+       CASE WHEN c.column_default LIKE 'nextval(%' OR c.is_identity = 'YES' THEN 'auto_increment' ELSE '' END,
+       COALESCE(col_description((quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass, c.ordinal_position), '')
+    FROM information_schema.columns c
+    LEFT JOIN (
+        SELECT kcu.table_schema, kcu.table_name, kcu.column_name
+        FROM information_schema.table_constraints tc
+        JOIN information_schema.key_column_usage kcu
+          ON kcu.constraint_name = tc.constraint_name
+         AND kcu.table_schema = tc.table_schema
+         AND kcu.table_name = tc.table_name
+        WHERE tc.constraint_type = 'PRIMARY KEY'
+    ) pk ON pk.table_schema = c.table_schema AND pk.table_name = c.table_name AND pk.column_name = c.column_name
+    LEFT JOIN (
+        SELECT t.typname, string_agg(quote_literal(en.enumlabel), ',' ORDER BY en.enumsortorder) AS labels
+        FROM pg_type t
+        JOIN pg_enum en ON en.enumtypid = t.oid
+        GROUP BY t.typname
+    ) e ON e.typname = c.udt_name
+    WHERE c.table_schema = current_schema()
+      AND c.table_name = ?
+    ORDER BY c.ordinal_position`
+}
+
+func (PostgresDialect) ForeignKeysQuery() string {
+	return `SELECT tc.constraint_name, kcu.column_name, ccu.table_name, ccu.column_name
+    FROM information_schema.table_constraints tc
+    JOIN information_schema.key_column_usage kcu
+      ON kcu.constraint_name = tc.constraint_name
+     AND kcu.table_schema = tc.table_schema
+    JOIN information_schema.constraint_column_usage ccu
+      ON ccu.constraint_name = tc.constraint_name
+     AND ccu.table_schema = tc.table_schema
+    WHERE tc.constraint_type = 'FOREIGN KEY'
+      AND tc.table_schema = current_schema()
+      AND tc.table_name = ?
+    ORDER BY tc.constraint_name, kcu.ordinal_position`
+}
+
+func (PostgresDialect) MetaColumnType(kind string) string {
+	switch kind {
+	case "serial":
+		return "bigserial"
+	case "time":
+		return "timestamp"
+	case "string":
+		return "varchar(255)"
+	}
+
+	return kind
+}
+
+func (PostgresDialect) InsertReturning() bool {
+	return true
+}
+
+func (PostgresDialect) IsBoolean(column ColumnInfo) bool {
+	return column.DataType == "boolean"
+}
+
+func (PostgresDialect) TableCommentQuery() string {
+	return `SELECT COALESCE(obj_description(to_regclass(quote_ident(?)), 'pg_class'), '')`
+}
+
+func (PostgresDialect) PartitionsQuery() string {
+	return `SELECT c.relname, split_part(pg_get_partkeydef(p.oid), ' ', 1),
+       substring(pg_get_partkeydef(p.oid) from '\((.*)\)'),
+       pg_get_expr(c.relpartbound, c.oid), GREATEST(c.reltuples, 0)::bigint
+    FROM pg_inherits i
+    JOIN pg_class c ON c.oid = i.inhrelid
+    JOIN pg_class p ON p.oid = i.inhparent
+    JOIN pg_namespace n ON n.oid = p.relnamespace
+    WHERE n.nspname = current_schema()
+      AND p.relname = ?
+    ORDER BY c.relname`
+}
+
+// PartitionSource reads a single partition through its own table, PostgreSQL has no PARTITION clause.
+func (d PostgresDialect) PartitionSource(table string, partitions []string) string {
+	if len(partitions) != 1 {
+		return d.QuoteIdent(table)
+	}
+
+	return fmt.Sprintf("%s AS %s", d.QuoteIdent(partitions[0]), d.QuoteIdent(table))
+}
+
+func (d PostgresDialect) CreateTableLike(table string, source string) string {
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (LIKE %s INCLUDING ALL)", d.QuoteIdent(table), d.QuoteIdent(source))
+}
+
+func (PostgresDialect) TimeBucket(column string, seconds int64) string {
+	return fmt.Sprintf("to_timestamp(floor(extract(epoch FROM %s) / %d) * %d)", column, seconds, seconds)
+}
+
+func (PostgresDialect) TryLockQuery() string {
+	return "SELECT pg_try_advisory_lock(hashtext(?))"
+}
+
+func (PostgresDialect) UnlockQuery() string {
+	return "SELECT pg_advisory_unlock(hashtext(?))"
+}
+
+// rebind replaces "?" placeholders outside of quoted strings and identifiers with the dialect placeholders.
+func rebind(dialect Dialect, query string) string {
+	if _, ok := dialect.(MySQLDialect); ok {
+		return query
+	}
+
+	var (
+		res   strings.Builder
+		quote byte
+		n     int
+	)
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?':
+			n++
+			res.WriteString(dialect.Placeholder(n))
+			continue
+		}
+
+		res.WriteByte(ch)
+	}
+
+	return res.String()
+}
+
+// dialectDB and dialectTx rebind queries to the dialect placeholder style.
+type dialectDB struct {
+	*sql.DB
+	dialect Dialect
+}
+
+func (db dialectDB) Exec(query string, args ...any) (sql.Result, error) {
+	res, err := db.DB.Exec(rebind(db.dialect, query), args...)
+	return res, wrapQueryError(query, err)
+}
+
+func (db dialectDB) Query(query string, args ...any) (*sql.Rows, error) {
+	rows, err := db.DB.Query(rebind(db.dialect, query), args...)
+	return rows, wrapQueryError(query, err)
+}
+
+func (db dialectDB) QueryRow(query string, args ...any) *sql.Row {
+	return db.DB.QueryRow(rebind(db.dialect, query), args...)
+}
+
+func (db dialectDB) Begin() (dialectTx, error) {
+	tx, err := db.DB.Begin()
+	return dialectTx{Tx: tx, dialect: db.dialect}, err
+}
+
+type dialectTx struct {
+	*sql.Tx
+	dialect Dialect
+}
+
+func (tx dialectTx) Exec(query string, args ...any) (sql.Result, error) {
+	res, err := tx.Tx.Exec(rebind(tx.dialect, query), args...)
+	return res, wrapQueryError(query, err)
+}
+
+func (tx dialectTx) Query(query string, args ...any) (*sql.Rows, error) {
+	rows, err := tx.Tx.Query(rebind(tx.dialect, query), args...)
+	return rows, wrapQueryError(query, err)
+}
+
+func (tx dialectTx) QueryRow(query string, args ...any) *sql.Row {
+	return tx.Tx.QueryRow(rebind(tx.dialect, query), args...)
+}
+
+func (exp Explorer) db() dialectDB {
+	return dialectDB{DB: exp.DB, dialect: exp.dialect}
+}
+
+// quote quotes the database name of a table.
+func (exp Explorer) quote(table string) string {
+	return exp.dialect.QuoteIdent(exp.dbName(table))
+}
+
+// quoteColumn quotes the database name of a column of table.
+func (exp Explorer) quoteColumn(table, column string) string {
+	return exp.dialect.QuoteIdent(exp.dbColumn(table, column))
+}
+
+// insertReturningID runs an INSERT and returns the generated value of idColumn, a database column name.
+func (exp Explorer) insertReturningID(q queryer, query string, idColumn string, args ...any) (any, error) {
+	if exp.dialect.InsertReturning() {
+		var id any
+		err := q.QueryRow(query+" RETURNING "+exp.dialect.QuoteIdent(idColumn), args...).Scan(&id)
+		return id, err
+	}
+
+	result, err := q.Exec(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.LastInsertId()
+}
+
+type metaColumn struct {
+	Name     string
+	Kind     string
+	Nullable bool
+}
+
+// metaTable describes a table the explorer keeps its own state in.
+type metaTable struct {
+	Name       string
+	Columns    []metaColumn
+	PrimaryKey string
+	Unique     [][]string
+	Indexes    [][]string
+}
+
+func (exp Explorer) tableExists(name string) (bool, error) {
+	var count int
+	err := exp.db().QueryRow(`SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = `+exp.dialect.CurrentSchema()+` AND TABLE_NAME = ?`, name).Scan(&count)
+	return count > 0, err
+}
+
+func (exp Explorer) quoteColumns(table string, columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = exp.quoteColumn(table, column)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func (exp Explorer) ensureMetaTable(table metaTable) error {
+	exists, err := exp.tableExists(table.Name)
+	if err != nil || exists {
+		return err
+	}
+
+	definitions := make([]string, 0)
+	for _, c := range table.Columns {
+		definition := exp.quoteColumn(table.Name, c.Name) + " " + exp.dialect.MetaColumnType(c.Kind)
+		if !c.Nullable && c.Kind != "serial" {
+			definition += " NOT NULL"
+		}
+		if c.Nullable {
+			definition += " DEFAULT NULL"
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	definitions = append(definitions, "PRIMARY KEY ("+exp.quoteColumn(table.Name, table.PrimaryKey)+")")
+	for _, columns := range table.Unique {
+		definitions = append(definitions, "UNIQUE ("+exp.quoteColumns(table.Name, columns)+")")
+	}
+
+	_, err = exp.db().Exec("CREATE TABLE IF NOT EXISTS " + exp.quote(table.Name) + " (\n  " + strings.Join(definitions, ",\n  ") + "\n)")
+	if err != nil {
+		return err
+	}
+
+	for i, columns := range table.Indexes {
+		indexName := fmt.Sprintf("%s_idx%d", table.Name, i+1)
+		_, err := exp.db().Exec("CREATE INDEX " + exp.dialect.QuoteIdent(indexName) + " ON " + exp.quote(table.Name) + " (" + exp.quoteColumns(table.Name, columns) + ")")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (exp Explorer) initMetaTables() error {
+	for _, table := range exp.metaTables {
+		if err := exp.ensureMetaTable(table); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package dbexplorer
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		query    string
+		expected string
+	}{
+		{MySQLDialect{}, "SELECT * FROM t WHERE a = ? AND b = ?", "SELECT * FROM t WHERE a = ? AND b = ?"},
+		{PostgresDialect{}, "SELECT * FROM t WHERE a = ? AND b = ?", "SELECT * FROM t WHERE a = $1 AND b = $2"},
+		{PostgresDialect{}, `SELECT '?' FROM "we?ird" WHERE a = ?`, `SELECT '?' FROM "we?ird" WHERE a = $1`},
+	}
+
+	for idx, item := range cases {
+		if got := rebind(item.dialect, item.query); got != item.expected {
+			t.Errorf("case %d: expected %q, got %q", idx, item.expected, got)
+		}
+	}
+}
+
+func TestIsBoolean(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		column   ColumnInfo
+		expected bool
+	}{
+		{MySQLDialect{}, ColumnInfo{DataType: "tinyint", ColumnType: "tinyint(1)"}, true},
+		{MySQLDialect{}, ColumnInfo{DataType: "tinyint", ColumnType: "tinyint(1) unsigned"}, true},
+		{MySQLDialect{}, ColumnInfo{DataType: "tinyint", ColumnType: "tinyint(4)"}, false},
+		{MySQLDialect{}, ColumnInfo{DataType: "bit", ColumnType: "bit(1)"}, true},
+		{PostgresDialect{}, ColumnInfo{DataType: "boolean", ColumnType: "boolean"}, true},
+		{PostgresDialect{}, ColumnInfo{DataType: "smallint", ColumnType: "smallint"}, false},
+	}
+
+	for _, c := range cases {
+		if got := c.dialect.IsBoolean(c.column); got != c.expected {
+			t.Errorf("%T %s: expected %v, got %v", c.dialect, c.column.ColumnType, c.expected, got)
+		}
+	}
+}
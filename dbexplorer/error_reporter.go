@@ -0,0 +1,174 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// ErrorReport describes a request answered with a 5xx status or a panic.
+type ErrorReport struct {
+	Time      time.Time
+	Method    string
+	Path      string
+	Status    int
+	Principal string
+	// Err is the error the response was written for, nil when the handler did not record one.
+	Err error
+	// SQL is the failed statement with the string literals removed, arguments are never included.
+	SQL string
+	// Panic is the recovered value and Stack the stack of the panicking goroutine.
+	Panic any
+	Stack []byte
+}
+
+// ErrorReporter receives the reports of failed requests, see NewSentryReporter. Report is called in the
+// request goroutine, slow reporters should send the reports in the background.
+type ErrorReporter interface {
+	Report(ctx context.Context, report ErrorReport)
+}
+
+// WithErrorReporter reports 5xx responses and recovered panics to the reporter.
+func WithErrorReporter(reporter ErrorReporter) Option {
+	return func(exp *Explorer) error {
+		exp.errorReporter = reporter
+		return nil
+	}
+}
+
+// queryError keeps the statement of a failed query for error reports.
+type queryError struct {
+	Query string
+	Err   error
+}
+
+func (e queryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e queryError) Unwrap() error {
+	return e.Err
+}
+
+func wrapQueryError(query string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return queryError{Query: query, Err: err}
+}
+
+// sanitizeSQL replaces the string literals of the statement, they may hold user data. Database error
+// messages quote values the same way.
+func sanitizeSQL(query string) string {
+	var res strings.Builder
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if c != '\'' {
+			res.WriteByte(c)
+			continue
+		}
+
+		for i++; i < len(query); i++ {
+			if query[i] == '\\' {
+				i++
+				continue
+			}
+			if query[i] == '\'' {
+				break
+			}
+		}
+		res.WriteString("'?'")
+	}
+
+	return res.String()
+}
+
+type reportContextKey struct{}
+
+// requestError holds the error a handler failed with until the response is reported.
+type requestError struct {
+	err       error
+	principal *Principal
+}
+
+// withRequestError returns the error holder of the request, adding it to its context on first use.
+func withRequestError(r *http.Request) (*http.Request, *requestError) {
+	if holder, ok := r.Context().Value(reportContextKey{}).(*requestError); ok {
+		return r, holder
+	}
+
+	holder := &requestError{}
+	return r.WithContext(context.WithValue(r.Context(), reportContextKey{}, holder)), holder
+}
+
+// recordPrincipal keeps the authenticated principal of the request for the error reporter.
+func recordPrincipal(r *http.Request) {
+	if holder, ok := r.Context().Value(reportContextKey{}).(*requestError); ok {
+		holder.principal = PrincipalFromContext(r.Context())
+	}
+}
+
+// writeInternalError answers 500 and keeps err for the error reporter.
+func writeInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	if holder, ok := r.Context().Value(reportContextKey{}).(*requestError); ok {
+		holder.err = err
+	}
+
+	w.WriteHeader(http.StatusInternalServerError)
+}
+
+func (exp Explorer) errorReportingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, holder := withRequestError(r)
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				if recorder.status >= http.StatusInternalServerError {
+					exp.reportError(r, holder, recorder.status, holder.err, nil, nil)
+				}
+				return
+			}
+
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			exp.reportError(r, holder, http.StatusInternalServerError, fmt.Errorf("panic: %v", recovered), recovered, debug.Stack())
+			if recorder.status == 0 {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+	})
+}
+
+func (exp Explorer) reportError(r *http.Request, holder *requestError, status int, err error, recovered any, stack []byte) {
+	report := ErrorReport{
+		Time:   time.Now(),
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Status: status,
+		Err:    err,
+		Panic:  recovered,
+		Stack:  stack,
+	}
+
+	if holder.principal != nil {
+		report.Principal = holder.principal.Name
+	}
+
+	var qe queryError
+	if errors.As(err, &qe) {
+		report.SQL = sanitizeSQL(qe.Query)
+	}
+
+	exp.errorReporter.Report(r.Context(), report)
+}
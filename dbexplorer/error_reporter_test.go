@@ -0,0 +1,98 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type recordingReporter struct {
+	reports []ErrorReport
+}
+
+func (r *recordingReporter) Report(ctx context.Context, report ErrorReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestErrorReportingMiddleware(t *testing.T) {
+	reporter := &recordingReporter{}
+	exp := Explorer{errorReporter: reporter}
+
+	handler := exp.errorReportingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail":
+			writeInternalError(w, r, wrapQueryError("SELECT * FROM items WHERE title = 'secret'", errors.New("broken")))
+		case "/panic":
+			panic("boom")
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+
+	for _, path := range []string{"/ok", "/fail", "/panic"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		expected := http.StatusInternalServerError
+		if path == "/ok" {
+			expected = http.StatusOK
+		}
+		if rec.Code != expected {
+			t.Errorf("%s: expected status %d, got %d", path, expected, rec.Code)
+		}
+	}
+
+	if len(reporter.reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reporter.reports))
+	}
+
+	if report := reporter.reports[0]; report.SQL != "SELECT * FROM items WHERE title = '?'" || report.Err.Error() != "broken" {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	if report := reporter.reports[1]; report.Panic != "boom" || len(report.Stack) == 0 {
+		t.Errorf("unexpected panic report %+v", report)
+	}
+}
+
+func TestSentryReporter(t *testing.T) {
+	reporter, err := NewSentryReporter("https://public@sentry.example.com/prefix/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reporter.endpoint != "https://sentry.example.com/prefix/api/42/store/" {
+		t.Errorf("unexpected endpoint %s", reporter.endpoint)
+	}
+
+	data, err := reporter.event(ErrorReport{Method: "GET", Path: "/items", Status: 500, Principal: "bob", SQL: "SELECT 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event map[string]any
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event["transaction"] != "GET /items" || event["user"].(map[string]any)["username"] != "bob" || event["extra"].(map[string]any)["sql"] != "SELECT 1" {
+		t.Errorf("unexpected event %s", data)
+	}
+
+	data, err = reporter.event(ErrorReport{Status: 500, Err: errors.New("Error 1062: Duplicate entry 'alice@example.com' for key 'email'")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "alice@example.com") || !strings.Contains(string(data), "Duplicate entry '?' for key '?'") {
+		t.Errorf("expected the message without values, got %s", data)
+	}
+
+	for _, dsn := range []string{"https://sentry.example.com/42", "https://key@sentry.example.com/"} {
+		if _, err := NewSentryReporter(dsn); err == nil {
+			t.Errorf("%s: expected error", dsn)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+)
+
+type ItemExistsResponse struct {
+	Exists bool `json:"exists"`
+}
+
+// itemExists looks up the record by its primary key without reading its columns.
+func (exp Explorer) itemExists(ctx context.Context, q queryer, table string, id string) (bool, error) {
+	pkName, err := exp.getPrimaryKey(table)
+	if err != nil {
+		return false, err
+	}
+
+	pkValue, err := exp.primaryKeyValue(table, id)
+	if err != nil {
+		return false, nil
+	}
+
+	if exp.backend != nil {
+		item, err := exp.backendItem(ctx, table, pkName, pkValue, []string{pkName})
+		return item != nil, err
+	}
+
+	builder, err := exp.queryBuilder(table)
+	if err != nil {
+		return false, err
+	}
+
+	query, err := builder.existsByKey(pkName)
+	if err != nil {
+		return false, err
+	}
+
+	var one int
+	err = q.QueryRow(query, pkValue).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// handlerHeadItem answers HEAD /table/id with 200 or 404 and no body.
+func (exp Explorer) handlerHeadItem(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	exists, err := exp.itemExists(r.Context(), exp.db(), tableName, exp.getId(r.URL.Path))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	addRowsScanned(r.Context(), 1)
+
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (exp Explorer) handlerItemExists(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	exists, err := exp.itemExists(r.Context(), exp.db(), tableName, exp.getId(r.URL.Path))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	addRowsScanned(r.Context(), 1)
+
+	writeResponse(w, ItemExistsResponse{Exists: exists})
+}
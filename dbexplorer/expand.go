@@ -0,0 +1,198 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// parseExpand returns the foreign keys named by ?expand=author_id,category_id.
+func (exp Explorer) parseExpand(table string, query url.Values) ([]ForeignKey, error) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	fks := make([]ForeignKey, 0)
+	for _, column := range splitList(query.Get("expand")) {
+		fk, ok := schema.ForeignKey(column)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a foreign key of %s", column, table)
+		}
+		if !exp.isValidTableName(fk.RefTable) {
+			return nil, fmt.Errorf("%s references an unknown table", column)
+		}
+
+		fks = append(fks, fk)
+	}
+
+	return fks, nil
+}
+
+// requireExpand parses ?expand of the request and checks that the referenced tables may be read.
+// It writes the error response and returns false when the expansion is not possible.
+func (exp Explorer) requireExpand(w http.ResponseWriter, r *http.Request, table string) ([]ForeignKey, bool) {
+	fks, err := exp.parseExpand(table, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	for _, fk := range fks {
+		if err := exp.authorize(r.Context(), principal, Action{Table: fk.RefTable, Op: OpRead}); err != nil {
+			exp.writeForbidden(w, r, err)
+			return nil, false
+		}
+	}
+
+	return fks, true
+}
+
+// expandReferences replaces the foreign key values of the items with the referenced rows, one query per key.
+// Values without a referenced row are kept as they are.
+func (exp Explorer) expandReferences(items []map[string]any, fks []ForeignKey) error {
+	for _, fk := range fks {
+		values := make([]any, 0, len(items))
+		seen := make(map[string]bool)
+		for _, item := range items {
+			value := item[fk.Column]
+			if value == nil || seen[fmt.Sprint(value)] {
+				continue
+			}
+
+			seen[fmt.Sprint(value)] = true
+			values = append(values, value)
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		query := fmt.Sprintf("SELECT * FROM %s WHERE %s IN (%s)", exp.quote(fk.RefTable), exp.quoteColumn(fk.RefTable, fk.RefColumn), placeholders)
+		rows, err := exp.db().Query(query, values...)
+		if err != nil {
+			return err
+		}
+
+		referenced, err := exp.scanRows(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+
+		exp.renderColumns(fk.RefTable, referenced...)
+		exp.countReturned(fk.RefTable, len(referenced))
+
+		byKey := make(map[string]map[string]any, len(referenced))
+		for _, row := range referenced {
+			byKey[fmt.Sprint(row[fk.RefColumn])] = row
+		}
+
+		for _, item := range items {
+			if row, ok := byKey[fmt.Sprint(item[fk.Column])]; ok && item[fk.Column] != nil {
+				item[fk.Column] = row
+			}
+		}
+	}
+
+	return nil
+}
+
+// handlerGetRelated lists the rows of the related table referencing the record, GET /{table}/{id}/{related_table}.
+// When the related table has several foreign keys to the table, ?via= names the one to follow.
+func (exp Explorer) handlerGetRelated(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(r.URL.Path, "/")
+	table, id, related := parts[1], parts[2], parts[3]
+	if !exp.isValidTableName(table) || !exp.isValidTableName(related) {
+		writeError(w, http.StatusNotFound, errUnknownTable)
+		return
+	}
+
+	if err := exp.authorize(r.Context(), PrincipalFromContext(r.Context()), Action{Table: related, Op: OpRead}); err != nil {
+		exp.writeForbidden(w, r, err)
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(table)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	pkValue, err := exp.primaryKeyValue(table, id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	via := query.Get("via")
+	query.Del("via")
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	// with WithPrimaryKey the foreign keys usually still reference the surrogate key
+	refs := make([]foreignKeyRef, 0)
+	for _, column := range schema.Columns {
+		for _, ref := range exp.referencingKeys(table, column.Name) {
+			if ref.Table == related && (via == "" || ref.ForeignKey.Column == via) {
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	switch {
+	case len(refs) == 0:
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s does not reference %s", related, table))
+		return
+	case len(refs) > 1:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%s references %s several times, choose the foreign key with ?via=", related, table))
+		return
+	}
+
+	refValue := pkValue
+	if refColumn := refs[0].ForeignKey.RefColumn; refColumn != primaryKey {
+		item, err := exp.getItem(exp.db(), table, primaryKey, pkValue)
+		if err != nil {
+			writeError(w, http.StatusNotFound, errRecordNotFound)
+			return
+		}
+		refValue = item[refColumn]
+	}
+
+	listQuery, err := exp.parseListQuery(related, query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	listQuery.Filters = append(listQuery.Filters, Filter{
+		Column:   refs[0].ForeignKey.Column,
+		Operator: opEq,
+		Value:    fmt.Sprint(refValue),
+	})
+
+	expand, ok := exp.requireExpand(w, r, related)
+	if !ok {
+		return
+	}
+
+	items, err := exp.getTableItems(r.Context(), related, listQuery)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	if err := exp.expandReferences(items, expand); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, GetTableItemsResponse{Records: items})
+}
@@ -0,0 +1,34 @@
+package dbexplorer
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseExpand(t *testing.T) {
+	exp := Explorer{
+		schema: newSchemaCache([]string{"posts", "users"}, map[string]*TableSchema{
+			"posts": {
+				Columns: []ColumnInfo{{Name: "id"}, {Name: "author_id"}, {Name: "title"}},
+				ForeignKeys: []ForeignKey{
+					{Column: "author_id", RefTable: "users", RefColumn: "id"},
+				},
+			},
+			"users": {Columns: []ColumnInfo{{Name: "id"}}},
+		}),
+	}
+
+	fks, err := exp.parseExpand("posts", url.Values{"expand": {"author_id"}})
+	if err != nil || len(fks) != 1 || fks[0].RefTable != "users" {
+		t.Errorf("unexpected expansion %+v, %v", fks, err)
+	}
+
+	fks, err = exp.parseExpand("posts", url.Values{})
+	if err != nil || len(fks) != 0 {
+		t.Errorf("expected no expansion, got %+v, %v", fks, err)
+	}
+
+	if _, err := exp.parseExpand("posts", url.Values{"expand": {"title"}}); err == nil {
+		t.Errorf("expected error for a column that is not a foreign key")
+	}
+}
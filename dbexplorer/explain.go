@@ -0,0 +1,184 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// indexHinter is implemented by dialects that can force a query to use an index.
+type indexHinter interface {
+	// IndexesQuery takes the table name and lists index name and column of every index column in order.
+	IndexesQuery() string
+	// ForceIndex is the hint following the table in the FROM clause, the index name is quoted.
+	ForceIndex(index string) string
+}
+
+func (MySQLDialect) IndexesQuery() string {
+	return `SELECT INDEX_NAME, COLUMN_NAME FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX`
+}
+
+func (MySQLDialect) ForceIndex(index string) string {
+	return "FORCE INDEX (" + index + ")"
+}
+
+// explainParams are the parameters of _explain, the others are the ones of the list endpoint.
+var explainParams = []string{"analyze", "compare", "index"}
+
+// ExplainResponse is the plan of the list query. For ?compare=index_hint, Index is the index the
+// query was forced to use and IndexPlan the plan with the hint.
+type ExplainResponse struct {
+	Query     string           `json:"query"`
+	Plan      []map[string]any `json:"plan"`
+	Index     string           `json:"index,omitempty"`
+	IndexPlan []map[string]any `json:"index_plan,omitempty"`
+}
+
+// tableIndexes returns the columns of the table indexes by index name.
+func (exp Explorer) tableIndexes(hinter indexHinter, table string) (map[string][]string, error) {
+	rows, err := exp.db().Query(hinter.IndexesQuery(), exp.dbName(table))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	indexes := make(map[string][]string)
+	for rows.Next() {
+		var index, column string
+		if err := rows.Scan(&index, &column); err != nil {
+			return nil, err
+		}
+
+		indexes[index] = append(indexes[index], exp.apiColumn(column))
+	}
+
+	return indexes, rows.Err()
+}
+
+// suggestIndex picks the index leading with a filtered column, or else with the first sort column.
+func suggestIndex(indexes map[string][]string, listQuery ListQuery) string {
+	candidates := make([]string, 0)
+	for _, f := range listQuery.Filters {
+		candidates = append(candidates, f.Column)
+	}
+	if len(listQuery.Sort) > 0 {
+		candidates = append(candidates, listQuery.Sort[0].Column)
+	}
+
+	for _, column := range candidates {
+		best := ""
+		for name, columns := range indexes {
+			// the lowest name keeps the choice stable
+			if columns[0] == column && (best == "" || name < best) {
+				best = name
+			}
+		}
+
+		if best != "" {
+			return best
+		}
+	}
+
+	return ""
+}
+
+func (exp Explorer) explain(r *http.Request, table string, listQuery ListQuery, analyze bool) ([]map[string]any, string, error) {
+	query, args, err := exp.planListQuery(r.Context(), table, listQuery)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix := "EXPLAIN "
+	if analyze {
+		prefix = "EXPLAIN ANALYZE "
+	}
+
+	rows, release, err := exp.queryContext(r.Context(), prefix+query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer release()
+	defer rows.Close()
+
+	plan, err := exp.scanRows(rows)
+	return plan, query, err
+}
+
+// handlerGetExplain serves GET /{table}/_explain with the parameters of the list endpoint. EXPLAIN
+// ANALYZE runs the query, so with authentication ?analyze=true is limited to the admin role.
+func (exp Explorer) handlerGetExplain(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	analyze := r.URL.Query().Get("analyze") == "true"
+	if analyze && exp.adminRestricted() && !exp.requireAdmin(w, r) {
+		return
+	}
+
+	values := url.Values{}
+	for key, value := range r.URL.Query() {
+		values[key] = value
+	}
+	for _, key := range explainParams {
+		values.Del(key)
+	}
+
+	listQuery, err := exp.parseListQuery(tableName, values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var hinter indexHinter
+	index := r.URL.Query().Get("index")
+	switch compare := r.URL.Query().Get("compare"); compare {
+	case "":
+	case "index_hint":
+		var ok bool
+		if hinter, ok = exp.dialect.(indexHinter); !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("index hints are not supported by the database"))
+			return
+		}
+
+		indexes, err := exp.tableIndexes(hinter, tableName)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		if index == "" {
+			index = suggestIndex(indexes, listQuery)
+		}
+		if _, ok := indexes[index]; !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("no index %q to compare with", index))
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown compare %s", compare))
+		return
+	}
+
+	plan, query, err := exp.explain(r, tableName, listQuery, analyze)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	res := ExplainResponse{Query: query, Plan: plan}
+	if hinter != nil {
+		listQuery.IndexHint = index
+		res.IndexPlan, _, err = exp.explain(r, tableName, listQuery, analyze)
+		if err != nil {
+			writeQueryError(w, r, err)
+			return
+		}
+		res.Index = index
+	}
+
+	writeResponse(w, res)
+}
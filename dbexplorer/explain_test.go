@@ -0,0 +1,50 @@
+package dbexplorer
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestSuggestIndex(t *testing.T) {
+	indexes := map[string][]string{
+		"PRIMARY":       {"id"},
+		"status_date":   {"status", "created"},
+		"by_status":     {"status"},
+		"created_index": {"created"},
+	}
+
+	cases := []struct {
+		listQuery ListQuery
+		expected  string
+	}{
+		{ListQuery{Filters: []Filter{{Column: "status"}}}, "by_status"},
+		{ListQuery{Filters: []Filter{{Column: "title"}}, Sort: []SortField{{Column: "created"}}}, "created_index"},
+		{ListQuery{Filters: []Filter{{Column: "title"}}}, ""},
+	}
+
+	for _, c := range cases {
+		if index := suggestIndex(indexes, c.listQuery); index != c.expected {
+			t.Errorf("%+v: expected %q, got %q", c.listQuery, c.expected, index)
+		}
+	}
+}
+
+func TestIndexHint(t *testing.T) {
+	exp := newItemsExplorer()
+
+	listQuery, err := exp.parseListQuery("items", url.Values{"status": {"new"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listQuery.IndexHint = "by_status"
+
+	query, _, err := exp.buildListQuery("items", listQuery)
+	if err != nil || query != "SELECT `items`.* FROM `items` FORCE INDEX (`by_status`) WHERE `status` = ? LIMIT ? OFFSET ?" {
+		t.Errorf("unexpected query %q %v", query, err)
+	}
+
+	exp.dialect = PostgresDialect{}
+	if _, _, err := exp.buildListQuery("items", listQuery); err == nil {
+		t.Errorf("expected error for a dialect without index hints")
+	}
+}
@@ -0,0 +1,73 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// parseFields parses ?fields=id,name, the columns a read returns. Without it all columns are returned.
+func (exp Explorer) parseFields(table string, query url.Values) ([]string, error) {
+	if !query.Has("fields") {
+		return nil, nil
+	}
+
+	fields := make([]string, 0)
+	for _, name := range strings.Split(query.Get("fields"), ",") {
+		name = strings.TrimSpace(name)
+		if !exp.isValidColumnName(table, name) {
+			return nil, fmt.Errorf("unknown field %s", name)
+		}
+
+		fields = withColumns(fields, name)
+	}
+
+	return fields, nil
+}
+
+// withColumns returns fields with the missing columns appended, e.g. the ones a cursor or an expansion
+// needs on top of the requested fields. No fields means all columns.
+func withColumns(fields []string, columns ...string) []string {
+	if fields == nil {
+		return nil
+	}
+
+	res := fields
+	for _, column := range columns {
+		if !containsString(res, column) {
+			res = append(res[:len(res):len(res)], column)
+		}
+	}
+
+	return res
+}
+
+// pickFields removes the table columns that are not in fields from the items. Computed values such
+// as the search score are kept.
+func (exp Explorer) pickFields(table string, fields []string, items ...map[string]any) {
+	if fields == nil {
+		return
+	}
+
+	for _, item := range items {
+		for name := range item {
+			if !containsString(fields, name) && exp.isValidColumnName(table, name) {
+				delete(item, name)
+			}
+		}
+	}
+}
+
+// selectColumns returns the select list of the columns, all of the table's for none.
+func (exp Explorer) selectColumns(table string, columns []string) string {
+	if columns == nil {
+		return exp.quote(table) + ".*"
+	}
+
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = exp.quote(table) + "." + exp.quoteColumn(table, column)
+	}
+
+	return strings.Join(quoted, ", ")
+}
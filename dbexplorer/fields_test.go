@@ -0,0 +1,46 @@
+package dbexplorer
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id"}, {Name: "title"}, {Name: "updated"}}},
+	})
+
+	if _, err := exp.parseFields("items", url.Values{"fields": {"id,secret"}}); err == nil {
+		t.Errorf("expected error for an unknown field")
+	}
+
+	listQuery, err := exp.parseListQuery("items", url.Values{"fields": {"title, id,title"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(listQuery.Fields, []string{"title", "id"}) {
+		t.Fatalf("unexpected fields %v", listQuery.Fields)
+	}
+
+	query, _, err := exp.buildListQuery("items", listQuery)
+	if err != nil || query != "SELECT `items`.`title`, `items`.`id` FROM `items` LIMIT ? OFFSET ?" {
+		t.Errorf("unexpected query %q %v", query, err)
+	}
+
+	// the cursor needs the sort columns even if they are not returned
+	listQuery, err = exp.parseListQuery("items", url.Values{"fields": {"title"}, "sort": {"updated"}, "cursor": {""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, _, err = exp.buildListQuery("items", listQuery)
+	if err != nil || query != "SELECT `items`.`title`, `items`.`updated`, `items`.`id` FROM `items` ORDER BY `updated`, `id` LIMIT ? OFFSET ?" {
+		t.Errorf("unexpected query %q %v", query, err)
+	}
+
+	item := map[string]any{"id": 1, "title": "a", "updated": "x", scoreColumn: 0.5}
+	exp.pickFields("items", listQuery.Fields, item)
+	if !reflect.DeepEqual(item, map[string]any{"title": "a", scoreColumn: 0.5}) {
+		t.Errorf("unexpected item %v", item)
+	}
+}
@@ -0,0 +1,186 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"strings"
+)
+
+const maxFilterExprDepth = 16
+
+// FilterExpr is a boolean combination of filters from ?filter=, e.g. (status=new OR status=open) AND id__in=1,2.
+// Leaves have Filter set, inner nodes combine their Children with Op "AND" or "OR".
+type FilterExpr struct {
+	Op       string       `json:"op,omitempty"`
+	Children []FilterExpr `json:"children,omitempty"`
+	Filter   *Filter      `json:"filter,omitempty"`
+}
+
+type filterExprParser struct {
+	exp    Explorer
+	table  string
+	tokens []string
+	pos    int
+	depth  int
+}
+
+// tokenizeFilterExpr splits the expression into parentheses and words. Quotes keep spaces and
+// parentheses inside a value: title="a (b)".
+func tokenizeFilterExpr(input string) ([]string, error) {
+	tokens := make([]string, 0)
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			flush()
+		case c == '(' || c == ')':
+			flush()
+			tokens = append(tokens, string(c))
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(input[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in filter")
+			}
+			current.WriteString(input[i+1 : i+1+end])
+			i += end + 1
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+// parseFilterExpr parses ?filter=. Predicates take the keys of the list query parameters, so operators
+// like title__fuzzy=x or id__in=1,2 work in the expression as well. AND binds tighter than OR.
+func (exp Explorer) parseFilterExpr(table string, input string) (*FilterExpr, error) {
+	tokens, err := tokenizeFilterExpr(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	p := &filterExprParser{exp: exp, table: table, tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in filter", p.tokens[p.pos])
+	}
+
+	return &expr, nil
+}
+
+func (p *filterExprParser) peekKeyword(keyword string) bool {
+	return p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], keyword)
+}
+
+func (p *filterExprParser) parseOr() (FilterExpr, error) {
+	return p.parseList("OR", p.parseAnd)
+}
+
+func (p *filterExprParser) parseAnd() (FilterExpr, error) {
+	return p.parseList("AND", p.parseFactor)
+}
+
+func (p *filterExprParser) parseList(op string, next func() (FilterExpr, error)) (FilterExpr, error) {
+	first, err := next()
+	if err != nil {
+		return first, err
+	}
+
+	children := []FilterExpr{first}
+	for p.peekKeyword(op) {
+		p.pos++
+		child, err := next()
+		if err != nil {
+			return child, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+
+	return FilterExpr{Op: op, Children: children}, nil
+}
+
+func (p *filterExprParser) parseFactor() (FilterExpr, error) {
+	if p.pos >= len(p.tokens) {
+		return FilterExpr{}, fmt.Errorf("unexpected end of filter")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	if token == "(" {
+		p.depth++
+		if p.depth > maxFilterExprDepth {
+			return FilterExpr{}, fmt.Errorf("filter is nested too deep")
+		}
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return expr, err
+		}
+
+		if p.pos >= len(p.tokens) || p.tokens[p.pos] != ")" {
+			return expr, fmt.Errorf("missing ) in filter")
+		}
+		p.pos++
+		p.depth--
+
+		return expr, nil
+	}
+
+	key, value, ok := strings.Cut(token, "=")
+	if !ok || key == "" {
+		return FilterExpr{}, fmt.Errorf("expected key=value in filter, got %q", token)
+	}
+
+	filter, err := p.exp.parseFilter(p.table, key, value)
+	if err != nil {
+		return FilterExpr{}, err
+	}
+
+	if filter.Operator == opNear {
+		return FilterExpr{}, fmt.Errorf("operator %s is not supported in filter", opNear)
+	}
+
+	return FilterExpr{Filter: &filter}, nil
+}
+
+// exprCondition returns the SQL condition of the expression.
+func (exp Explorer) exprCondition(table string, expr FilterExpr) (string, []any, error) {
+	if expr.Filter != nil {
+		return exp.filterCondition(table, *expr.Filter)
+	}
+
+	conditions := make([]string, len(expr.Children))
+	args := make([]any, 0)
+	for i, child := range expr.Children {
+		condition, childArgs, err := exp.exprCondition(table, child)
+		if err != nil {
+			return "", nil, err
+		}
+
+		conditions[i] = condition
+		args = append(args, childArgs...)
+	}
+
+	return "(" + strings.Join(conditions, " "+expr.Op+" ") + ")", args, nil
+}
@@ -0,0 +1,41 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFilterExpr(t *testing.T) {
+	exp := newItemsExplorer()
+
+	expr, err := exp.parseFilterExpr("items", `(status=new OR status="in progress") and id__in=1,2`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	where, args, err := exp.exprCondition("items", *expr)
+	if err != nil || where != "((`status` = ? OR `status` = ?) AND `id` IN (?, ?))" || !reflect.DeepEqual(args, []any{"new", "in progress", "1", "2"}) {
+		t.Errorf("unexpected condition %s %v %v", where, args, err)
+	}
+
+	expr, err = exp.parseFilterExpr("items", "status=a OR status=b AND id=1")
+	if err != nil || expr.Op != "OR" || len(expr.Children) != 2 || expr.Children[1].Op != "AND" {
+		t.Errorf("expected AND to bind tighter than OR, got %+v %v", expr, err)
+	}
+
+	for _, input := range []string{
+		"",
+		"(status=a",
+		"status=a)",
+		"status=a OR",
+		"status",
+		"missing=1",
+		`status="a`,
+		strings.Repeat("(", maxFilterExprDepth+1) + "id=1" + strings.Repeat(")", maxFilterExprDepth+1),
+	} {
+		if _, err := exp.parseFilterExpr("items", input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	opEq     = "eq"
+	opNe     = "ne"
+	opGt     = "gt"
+	opGte    = "gte"
+	opLt     = "lt"
+	opLte    = "lte"
+	opLike   = "like"
+	opIsNull = "is_null"
+	opFuzzy  = "fuzzy"
+	opIn     = "in"
+	opNotIn  = "nin"
+)
+
+// comparisonOperators map the operators comparing the column with a single value to SQL.
+var comparisonOperators = map[string]string{
+	opEq:  "=",
+	opNe:  "<>",
+	opGt:  ">",
+	opGte: ">=",
+	opLt:  "<",
+	opLte: "<=",
+}
+
+// maxInListSize caps the values of __in and __nin, each one is a bound parameter.
+const maxInListSize = 500
+
+const operatorSeparator = "__"
+
+var knownOperators = map[string]bool{
+	opEq:     true,
+	opNe:     true,
+	opGt:     true,
+	opGte:    true,
+	opLt:     true,
+	opLte:    true,
+	opLike:   true,
+	opIsNull: true,
+	opFuzzy:  true,
+	opNear:   true,
+	opIn:     true,
+	opNotIn:  true,
+}
+
+var functionNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+type levenshteinFunc struct {
+	name        string
+	maxDistance int
+}
+
+// WithLevenshteinFunction makes the __fuzzy operator use a Levenshtein distance UDF installed in the database
+// instead of SOUNDEX: name(column, value) <= maxDistance.
+func WithLevenshteinFunction(name string, maxDistance int) Option {
+	return func(exp *Explorer) error {
+		if !functionNameRe.MatchString(name) {
+			return fmt.Errorf("invalid function name %q", name)
+		}
+
+		exp.levenshtein = &levenshteinFunc{
+			name:        name,
+			maxDistance: maxDistance,
+		}
+		return nil
+	}
+}
+
+// splitFilterKey splits "title__fuzzy" into the column and the operator, plain "title" means equality.
+func splitFilterKey(key string) (column string, operator string) {
+	idx := strings.LastIndex(key, operatorSeparator)
+	if idx > 0 && knownOperators[key[idx+len(operatorSeparator):]] {
+		return key[:idx], key[idx+len(operatorSeparator):]
+	}
+
+	return key, opEq
+}
+
+// Filter is a single predicate of a list request, e.g. ?title=foo, ?created__gte=2024-01-01, ?title__like=%25foo%25,
+// ?updated__is_null=true, ?title__fuzzy=fo or ?id__in=1,2,3.
+type Filter struct {
+	Column   string
+	Operator string
+	Value    string
+	// Radius in meters for the near operator.
+	Radius float64
+}
+
+// reservedListParams are query parameters of the list endpoint that are not column filters.
+var reservedListParams = map[string]bool{
+	"limit":     true,
+	"offset":    true,
+	"q":         true,
+	"sort":      true,
+	"order":     true,
+	"radius":    true,
+	"partition": true,
+	"format":    true,
+	"expand":    true,
+	"meta":      true,
+	"cursor":    true,
+	"filter":    true,
+	"fields":    true,
+}
+
+func (exp Explorer) isValidColumnName(table string, column string) bool {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return false
+	}
+
+	_, ok := schema.Column(column)
+	return ok
+}
+
+func (exp Explorer) parseFilters(table string, query url.Values) ([]Filter, error) {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if !reservedListParams[key] {
+			keys = append(keys, key)
+		}
+	}
+	// stable order keeps generated queries identical for identical requests
+	sort.Strings(keys)
+
+	filters := make([]Filter, 0)
+	for _, key := range keys {
+		for _, value := range query[key] {
+			filter, err := exp.parseFilter(table, key, value)
+			if err != nil {
+				return nil, err
+			}
+
+			if filter.Operator == opNear {
+				if !query.Has("radius") {
+					return nil, fmt.Errorf("radius is required for %s", key)
+				}
+
+				filter.Radius, err = parseDistance(query.Get("radius"))
+				if err != nil {
+					return nil, err
+				}
+
+				if _, _, err := parseLatLon(value); err != nil {
+					return nil, err
+				}
+			}
+
+			filters = append(filters, filter)
+		}
+	}
+
+	return filters, nil
+}
+
+// parseFilter validates a single predicate like title=foo or id__in=1,2. The radius of __near is up to the caller.
+func (exp Explorer) parseFilter(table string, key string, value string) (Filter, error) {
+	column, operator := splitFilterKey(key)
+	if !exp.isValidColumnName(table, column) {
+		return Filter{}, fmt.Errorf("unknown column %s", column)
+	}
+
+	if err := exp.checkOperator(table, column, operator); err != nil {
+		return Filter{}, err
+	}
+
+	if operator == opIn || operator == opNotIn {
+		if err := checkInList(key, value); err != nil {
+			return Filter{}, err
+		}
+	}
+
+	if operator == opIsNull && value != "true" && value != "false" {
+		return Filter{}, fmt.Errorf("%s must be true or false", key)
+	}
+
+	return Filter{Column: column, Operator: operator, Value: value}, nil
+}
+
+// checkOperator rejects operators that make no sense for the column type.
+func (exp Explorer) checkOperator(table string, column string, operator string) error {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return err
+	}
+
+	info, _ := schema.Column(column)
+
+	if (operator == opFuzzy || operator == opLike) && !isTextDataType(info.DataType) {
+		return fmt.Errorf("operator %s is not supported for column %s", operator, column)
+	}
+
+	if operator == opNear && !isSpatialDataType(info.DataType) {
+		return fmt.Errorf("operator %s is not supported for column %s", operator, column)
+	}
+
+	return nil
+}
+
+func (exp Explorer) filterCondition(table string, f Filter) (string, []any, error) {
+	column := exp.quoteColumn(table, f.Column)
+
+	if sqlOperator, ok := comparisonOperators[f.Operator]; ok {
+		return column + " " + sqlOperator + " ?", []any{f.Value}, nil
+	}
+
+	switch f.Operator {
+	case opLike:
+		return column + " LIKE ?", []any{f.Value}, nil
+	case opIsNull:
+		if f.Value == "true" {
+			return column + " IS NULL", nil, nil
+		}
+
+		return column + " IS NOT NULL", nil, nil
+	case opFuzzy:
+		if exp.levenshtein != nil {
+			return fmt.Sprintf("%s(%s, ?) <= ?", exp.levenshtein.name, column), []any{f.Value, exp.levenshtein.maxDistance}, nil
+		}
+
+		return fmt.Sprintf("SOUNDEX(%s) = SOUNDEX(?)", column), []any{f.Value}, nil
+	case opNear:
+		return exp.nearCondition(table, f)
+	case opIn, opNotIn:
+		values := splitInList(f.Value)
+		args := make([]any, len(values))
+		for i, v := range values {
+			args[i] = v
+		}
+
+		keyword := "IN"
+		if f.Operator == opNotIn {
+			keyword = "NOT IN"
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		return fmt.Sprintf("%s %s (%s)", column, keyword, placeholders), args, nil
+	}
+
+	return "", nil, fmt.Errorf("unknown operator %s", f.Operator)
+}
+
+func (exp Explorer) filterWhere(table string, filters []Filter) (string, []any, error) {
+	conditions := make([]string, 0, len(filters))
+	args := make([]any, 0)
+	for _, f := range filters {
+		condition, conditionArgs, err := exp.filterCondition(table, f)
+		if err != nil {
+			return "", nil, err
+		}
+
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// parseWhere builds a WHERE condition from filters given as a JSON object, e.g. {"status": "new", "title__fuzzy": "memcash"}.
+// The keys follow the query parameters of the list endpoint.
+func (exp Explorer) parseWhere(table string, where map[string]any) (string, []any, error) {
+	query := make(url.Values)
+	for key, value := range where {
+		if reservedListParams[key] {
+			return "", nil, fmt.Errorf("unknown column %s", key)
+		}
+		if value == nil {
+			return "", nil, fmt.Errorf("null value of %s", key)
+		}
+
+		if list, ok := value.([]any); ok {
+			values := make([]string, len(list))
+			for i, v := range list {
+				values[i] = formatParam(v)
+			}
+			query.Set(key, strings.Join(values, ","))
+			continue
+		}
+
+		query.Set(key, formatParam(value))
+	}
+
+	filters, err := exp.parseFilters(table, query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return exp.filterWhere(table, filters)
+}
+
+// formatParam formats a JSON decoded value as a query parameter. fmt.Sprint would turn
+// float64(1000000) into 1e+06, which no integer column accepts.
+func formatParam(value any) string {
+	if v, ok := value.(float64); ok {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// splitInList splits the comma separated values of __in and __nin.
+func splitInList(value string) []string {
+	values := strings.Split(value, ",")
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+
+	return values
+}
+
+func checkInList(key string, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("empty list for %s", key)
+	}
+
+	if n := len(splitInList(value)); n > maxInListSize {
+		return fmt.Errorf("too many values for %s: %d, at most %d", key, n, maxInListSize)
+	}
+
+	return nil
+}
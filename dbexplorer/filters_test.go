@@ -0,0 +1,74 @@
+package dbexplorer
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInFilters(t *testing.T) {
+	exp := newItemsExplorer()
+
+	filters, err := exp.parseFilters("items", url.Values{"id__in": {"1, 2,3"}, "status__nin": {"a,b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	where, args, err := exp.filterWhere("items", filters)
+	if err != nil || where != "`id` IN (?, ?, ?) AND `status` NOT IN (?, ?)" || !reflect.DeepEqual(args, []any{"1", "2", "3", "a", "b"}) {
+		t.Errorf("unexpected condition %s %v %v", where, args, err)
+	}
+
+	where, args, err = exp.parseWhere("items", map[string]any{"id__in": []any{float64(4), float64(1000000)}})
+	if err != nil || where != "`id` IN (?, ?)" || !reflect.DeepEqual(args, []any{"4", "1000000"}) {
+		t.Errorf("unexpected JSON condition %s %v %v", where, args, err)
+	}
+
+	if _, err := exp.parseFilters("items", url.Values{"id__in": {""}}); err == nil {
+		t.Errorf("expected error for an empty list")
+	}
+
+	if _, err := exp.parseFilters("items", url.Values{"id__in": {strings.Repeat("1,", maxInListSize) + "1"}}); err == nil {
+		t.Errorf("expected error for a list over the cap")
+	}
+}
+
+func TestComparisonFilters(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{
+			{Name: "id", DataType: "int"},
+			{Name: "title", DataType: "varchar"},
+			{Name: "updated", DataType: "datetime"},
+		}},
+	})
+
+	filters, err := exp.parseFilters("items", url.Values{
+		"id__gt":           {"1"},
+		"id__lte":          {"10"},
+		"id__ne":           {"5"},
+		"title__like":      {"%db%"},
+		"updated__gte":     {"2024-01-01"},
+		"updated__is_null": {"false"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	where, args, err := exp.filterWhere("items", filters)
+	expected := "`id` > ? AND `id` <= ? AND `id` <> ? AND `title` LIKE ? AND `updated` >= ? AND `updated` IS NOT NULL"
+	if err != nil || where != expected || !reflect.DeepEqual(args, []any{"1", "10", "5", "%db%", "2024-01-01"}) {
+		t.Errorf("unexpected condition %s %v %v", where, args, err)
+	}
+
+	where, args, err = exp.parseWhere("items", map[string]any{"updated__lt": "2025-01-01", "title__is_null": true})
+	if err != nil || where != "`title` IS NULL AND `updated` < ?" || !reflect.DeepEqual(args, []any{"2025-01-01"}) {
+		t.Errorf("unexpected JSON condition %s %v %v", where, args, err)
+	}
+
+	for _, query := range []url.Values{{"id__like": {"1%"}}, {"updated__is_null": {"yes"}}} {
+		if _, err := exp.parseFilters("items", query); err == nil {
+			t.Errorf("%v: expected error", query)
+		}
+	}
+}
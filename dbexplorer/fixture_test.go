@@ -0,0 +1,69 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestExplorer returns an explorer over the cached tables for tests without a database,
+// the primary keys are taken from the tables so nothing is looked up.
+func newTestExplorer(tables map[string]*TableSchema) Explorer {
+	primaryKeys := make(map[string]string, len(tables))
+	for name, table := range tables {
+		if table.PrimaryKey != "" {
+			primaryKeys[name] = table.PrimaryKey
+		}
+	}
+
+	return Explorer{
+		dialect:         MySQLDialect{},
+		router:          NewRouter(),
+		metrics:         newMetrics(),
+		schema:          newSchemaCache(nil, tables),
+		primaryKeys:     primaryKeys,
+		authExemptPaths: make(map[string]bool),
+	}
+}
+
+// newItemsExplorer returns a test explorer with an items table of an int id and a varchar status.
+func newItemsExplorer() Explorer {
+	return newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "int"}, {Name: "status", DataType: "varchar"}}},
+	})
+}
+
+// serveRequest sends a request with the body, if any, to the handler and returns the recorded response.
+func serveRequest(handler http.Handler, method string, path string, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(method, path, reader))
+	return w
+}
+
+// openTestDB connects to the MySQL database of TestApis and creates its items and users tables, the test
+// is skipped without a database. The tables and the extra ones created by the test are dropped at its end.
+func openTestDB(t *testing.T, extraTables ...string) *sql.DB {
+	db, err := sql.Open("mysql", DSN)
+	if err != nil || db.Ping() != nil {
+		t.Skip("mysql is not available")
+	}
+
+	PrepareTestApis(db)
+	t.Cleanup(func() {
+		CleanupTestApis(db)
+		for _, table := range extraTables {
+			db.Exec("DROP TABLE IF EXISTS " + table)
+		}
+		db.Close()
+	})
+
+	return db
+}
@@ -0,0 +1,98 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	opNear = "near"
+
+	metersPerDegree = 111320.0
+)
+
+func isSpatialDataType(dataType string) bool {
+	switch dataType {
+	case "point", "geometry", "multipoint", "linestring", "polygon", "geometrycollection", "multilinestring", "multipolygon":
+		return true
+	}
+
+	return false
+}
+
+// parseLatLon parses "52.5,13.4".
+func parseLatLon(value string) (lat float64, lon float64, err error) {
+	latStr, lonStr, ok := strings.Cut(value, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid point %q, expected lat,lon", value)
+	}
+
+	lat, err = strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("invalid latitude %q", latStr)
+	}
+
+	lon, err = strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("invalid longitude %q", lonStr)
+	}
+
+	return lat, lon, nil
+}
+
+// parseDistance parses "5km", "500m", "3mi" or a plain number of meters.
+func parseDistance(value string) (float64, error) {
+	units := []struct {
+		suffix string
+		meters float64
+	}{
+		{"km", 1000},
+		{"mi", 1609.344},
+		{"m", 1},
+	}
+
+	number := value
+	multiplier := 1.0
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			number = strings.TrimSuffix(value, unit.suffix)
+			multiplier = unit.meters
+			break
+		}
+	}
+
+	distance, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || distance <= 0 {
+		return 0, fmt.Errorf("invalid distance %q", value)
+	}
+
+	return distance * multiplier, nil
+}
+
+// nearCondition matches points within radius meters from lat,lon. Points are expected as POINT(lon lat) with SRID 0.
+// The bounding box check goes first so a spatial index can be used before the exact spherical distance is computed.
+func (exp Explorer) nearCondition(table string, f Filter) (string, []any, error) {
+	lat, lon, err := parseLatLon(f.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	column := exp.quoteColumn(table, f.Column)
+	distance := fmt.Sprintf("ST_Distance_Sphere(%s, POINT(?, ?)) <= ?", column)
+	distanceArgs := []any{lon, lat, f.Radius}
+
+	dLat := f.Radius / metersPerDegree
+	if math.Abs(lat)+dLat >= 90 {
+		return distance, distanceArgs, nil
+	}
+
+	dLon := math.Min(f.Radius/(metersPerDegree*math.Cos(lat*math.Pi/180)), 180)
+	minLon, maxLon := lon-dLon, lon+dLon
+	minLat, maxLat := lat-dLat, lat+dLat
+	box := fmt.Sprintf("POLYGON((%[1]f %[3]f, %[2]f %[3]f, %[2]f %[4]f, %[1]f %[4]f, %[1]f %[3]f))", minLon, maxLon, minLat, maxLat)
+
+	condition := fmt.Sprintf("MBRContains(ST_GeomFromText(?), %s) AND %s", column, distance)
+	return condition, append([]any{box}, distanceArgs...), nil
+}
@@ -0,0 +1,42 @@
+package dbexplorer
+
+import "testing"
+
+func TestParseDistance(t *testing.T) {
+	cases := map[string]float64{
+		"5km":  5000,
+		"500m": 500,
+		"250":  250,
+		"1mi":  1609.344,
+	}
+
+	for value, expected := range cases {
+		got, err := parseDistance(value)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", value, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("%s: expected %v, got %v", value, expected, got)
+		}
+	}
+
+	for _, value := range []string{"", "km", "-1km", "5 parsecs"} {
+		if _, err := parseDistance(value); err == nil {
+			t.Errorf("%s: expected error", value)
+		}
+	}
+}
+
+func TestParseLatLon(t *testing.T) {
+	lat, lon, err := parseLatLon("52.5,13.4")
+	if err != nil || lat != 52.5 || lon != 13.4 {
+		t.Errorf("unexpected result %v %v %v", lat, lon, err)
+	}
+
+	for _, value := range []string{"52.5", "91,0", "0,181", "a,b"} {
+		if _, _, err := parseLatLon(value); err == nil {
+			t.Errorf("%s: expected error", value)
+		}
+	}
+}
@@ -0,0 +1,545 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var graphqlNameRe = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// graphqlSchema is generated from the table schemas at startup. Tables and columns whose names are not
+// valid GraphQL names are left out.
+type graphqlSchema struct {
+	sdl    string
+	tables map[string]*graphqlTable
+}
+
+type graphqlTable struct {
+	Table    string
+	TypeName string
+	// Columns maps the column names to their GraphQL types.
+	Columns map[string]string
+}
+
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+type GraphQLError struct {
+	Message string   `json:"message"`
+	Path    []string `json:"path,omitempty"`
+}
+
+type GraphQLResponse struct {
+	Data   *gqlObject     `json:"data"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// gqlObject keeps the fields of a result in the order of the selection.
+type gqlObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func newGQLObject() *gqlObject {
+	return &gqlObject{values: make(map[string]any)}
+}
+
+func (o *gqlObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *gqlObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func graphqlTypeName(table string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(table, "_") {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+
+	return b.String()
+}
+
+func (exp Explorer) graphqlScalar(column ColumnInfo) string {
+	switch {
+	case exp.dialect.IsBoolean(column):
+		return "Boolean"
+	case isJSONDataType(column.DataType):
+		return "JSON"
+	case exp.numbersAsStrings && isPreciseType(strings.ToUpper(column.DataType)):
+		return "String"
+	case isIntegerDataType(column.DataType):
+		return "Int"
+	case isNumericDataType(column.DataType):
+		return "Float"
+	}
+
+	return "String"
+}
+
+// buildGraphQLSchema generates the GraphQL schema of the tables.
+func (exp Explorer) buildGraphQLSchema() *graphqlSchema {
+	schema := &graphqlSchema{
+		tables: make(map[string]*graphqlTable),
+	}
+
+	var types, query, mutation strings.Builder
+	typeNames := make(map[string]bool)
+	for _, table := range exp.tableNames() {
+		tableSchema, err := exp.getTableSchema(table)
+		typeName := graphqlTypeName(table)
+		if err != nil || !graphqlNameRe.MatchString(table) || strings.HasPrefix(table, "__") || !graphqlNameRe.MatchString(typeName) || typeNames[typeName] {
+			continue
+		}
+		typeNames[typeName] = true
+
+		t := &graphqlTable{
+			Table:    table,
+			TypeName: typeName,
+			Columns:  make(map[string]string),
+		}
+
+		fmt.Fprintf(&types, "type %s {\n", typeName)
+		var input strings.Builder
+		for _, column := range tableSchema.Columns {
+			if !graphqlNameRe.MatchString(column.Name) || strings.HasPrefix(column.Name, "__") {
+				continue
+			}
+
+			scalar := exp.graphqlScalar(column)
+			t.Columns[column.Name] = scalar
+
+			nonNull := ""
+			if !column.Nullable {
+				nonNull = "!"
+			}
+			fmt.Fprintf(&types, "  %s: %s%s\n", column.Name, scalar, nonNull)
+			fmt.Fprintf(&input, "  %s: %s\n", column.Name, scalar)
+		}
+		fmt.Fprintf(&types, "}\n\ninput %sInput {\n%s}\n\n", typeName, input.String())
+
+		fmt.Fprintf(&query, "  %s(limit: Int, offset: Int, sort: String, q: String, where: JSON): [%s!]!\n", table, typeName)
+		fmt.Fprintf(&query, "  %s_by_pk(id: ID!): %s\n", table, typeName)
+		fmt.Fprintf(&mutation, "  create_%s(input: %sInput!): %s\n", table, typeName, typeName)
+		fmt.Fprintf(&mutation, "  update_%s(id: ID!, input: %sInput!): %s\n", table, typeName, typeName)
+		fmt.Fprintf(&mutation, "  delete_%s(id: ID!): Int!\n", table)
+
+		schema.tables[table] = t
+	}
+
+	schema.sdl = fmt.Sprintf("scalar JSON\n\n%stype Query {\n%s}\n\ntype Mutation {\n%s}\n", types.String(), query.String(), mutation.String())
+	return schema
+}
+
+// handlerGraphQL executes queries and mutations, GET without a query returns the schema in SDL.
+func (exp Explorer) handlerGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req GraphQLRequest
+	if r.Method == http.MethodGet {
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if req.Query == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Write([]byte(exp.graphqlSchema().sdl))
+			return
+		}
+
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeGraphQLError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeGraphQLError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	doc, err := parseGraphQL(req.Query)
+	if err != nil {
+		writeGraphQLError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		writeGraphQLError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if op.Type == "mutation" && r.Method == http.MethodGet {
+		writeGraphQLError(w, http.StatusMethodNotAllowed, fmt.Errorf("mutations require POST"))
+		return
+	}
+
+	variables, err := operationVariables(op, req.Variables)
+	if err != nil {
+		writeGraphQLError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	resp := GraphQLResponse{Data: newGQLObject()}
+	for _, field := range op.Selections {
+		value, err := exp.resolveRootField(r, op.Type, field, variables)
+		if err != nil {
+			resp.Errors = append(resp.Errors, GraphQLError{Message: err.Error(), Path: []string{field.ResponseName()}})
+			value = nil
+		}
+		resp.Data.set(field.ResponseName(), value)
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func writeGraphQLError(w http.ResponseWriter, status int, err error) {
+	data, _ := json.Marshal(GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func selectOperation(doc *gqlDocument, name string) (*gqlOperation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("operationName is required for documents with several operations")
+		}
+		return doc.Operations[0], nil
+	}
+
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown operation %s", name)
+}
+
+// operationVariables applies the defaults of the variable definitions and checks the required variables.
+func operationVariables(op *gqlOperation, given map[string]any) (map[string]any, error) {
+	variables := make(map[string]any, len(op.Variables))
+	for _, v := range op.Variables {
+		value, ok := given[v.Name]
+		if !ok && v.HasDefault {
+			value, ok = resolveValue(v.Default, nil), true
+		}
+		if value == nil && strings.HasSuffix(v.Type, "!") {
+			return nil, fmt.Errorf("variable $%s of type %s is required", v.Name, v.Type)
+		}
+		if ok {
+			variables[v.Name] = value
+		}
+	}
+
+	return variables, nil
+}
+
+// resolveRootField runs a root field of the operation, query fields are <table> and <table>_by_pk,
+// mutation fields are create_<table>, update_<table> and delete_<table>.
+func (exp Explorer) resolveRootField(r *http.Request, opType string, field *gqlField, variables map[string]any) (any, error) {
+	args := make(map[string]any, len(field.Args))
+	for name, value := range field.Args {
+		args[name] = resolveValue(value, variables)
+	}
+
+	if field.Name == "__typename" {
+		return strings.ToUpper(opType[:1]) + opType[1:], nil
+	}
+
+	tables := exp.graphqlSchema().tables
+
+	if opType == "query" {
+		if t, ok := tables[field.Name]; ok {
+			return exp.resolveList(r, t, field, args)
+		}
+		if t, ok := tables[strings.TrimSuffix(field.Name, "_by_pk")]; ok && strings.HasSuffix(field.Name, "_by_pk") {
+			return exp.resolveByPK(r, t, field, args)
+		}
+	} else {
+		for _, writeType := range []string{writeCreate, writeUpdate, writeDelete} {
+			table, found := strings.CutPrefix(field.Name, writeType+"_")
+			if t, ok := tables[table]; found && ok {
+				return exp.resolveMutation(r, writeType, t, field, args)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unknown field %s on type %s", field.Name, strings.ToUpper(opType[:1])+opType[1:])
+}
+
+func checkArgs(field *gqlField, args map[string]any, allowed ...string) error {
+	for name := range args {
+		if !containsString(allowed, name) {
+			return fmt.Errorf("unknown argument %s of %s", name, field.Name)
+		}
+	}
+
+	return nil
+}
+
+// checkSelection validates the selection of a table type before it is queried.
+func checkSelection(t *graphqlTable, field *gqlField) error {
+	if len(field.Selections) == 0 {
+		return fmt.Errorf("field %s of type %s must have a selection of subfields", field.Name, t.TypeName)
+	}
+
+	for _, sub := range field.Selections {
+		if sub.Name == "__typename" {
+			continue
+		}
+		if _, ok := t.Columns[sub.Name]; !ok {
+			return fmt.Errorf("unknown field %s on type %s", sub.Name, t.TypeName)
+		}
+		if len(sub.Selections) > 0 || len(sub.Args) > 0 {
+			return fmt.Errorf("field %s of type %s is a scalar", sub.Name, t.TypeName)
+		}
+	}
+
+	return nil
+}
+
+func project(t *graphqlTable, item map[string]any, selections []*gqlField) *gqlObject {
+	object := newGQLObject()
+	for _, sub := range selections {
+		if sub.Name == "__typename" {
+			object.set(sub.ResponseName(), t.TypeName)
+			continue
+		}
+		object.set(sub.ResponseName(), item[sub.Name])
+	}
+
+	return object
+}
+
+// graphqlID converts an ID given as a string or a number to the text of the URL path.
+func graphqlID(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return fmt.Sprint(v), nil
+	case float64:
+		if v == math.Trunc(v) {
+			return fmt.Sprint(int64(v)), nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid id %v", value)
+}
+
+func (exp Explorer) resolveList(r *http.Request, t *graphqlTable, field *gqlField, args map[string]any) (any, error) {
+	if err := checkArgs(field, args, "limit", "offset", "sort", "q", "where"); err != nil {
+		return nil, err
+	}
+	if err := checkSelection(t, field); err != nil {
+		return nil, err
+	}
+	if err := exp.authorize(r.Context(), PrincipalFromContext(r.Context()), Action{Table: t.Table, Op: OpRead}); err != nil {
+		return nil, err
+	}
+
+	query := make(url.Values)
+	for _, name := range []string{"limit", "offset", "sort", "q"} {
+		if value, ok := args[name]; ok && value != nil {
+			query.Set(name, formatParam(value))
+		}
+	}
+
+	if where, ok := args["where"]; ok && where != nil {
+		filters, ok := where.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("where must be an object")
+		}
+
+		for key, value := range filters {
+			if reservedListParams[key] {
+				return nil, fmt.Errorf("unknown column %s", key)
+			}
+			if value == nil {
+				return nil, fmt.Errorf("null value of %s", key)
+			}
+			query.Set(key, formatParam(value))
+		}
+	}
+
+	listQuery, err := exp.parseListQuery(t.Table, query)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := exp.getTableItems(r.Context(), t.Table, listQuery)
+	if errors.As(err, &statusError{}) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("internal error")
+	}
+
+	records := make([]*gqlObject, len(items))
+	for i, item := range items {
+		records[i] = project(t, item, field.Selections)
+	}
+
+	return records, nil
+}
+
+func (exp Explorer) resolveByPK(r *http.Request, t *graphqlTable, field *gqlField, args map[string]any) (any, error) {
+	if err := checkArgs(field, args, "id"); err != nil {
+		return nil, err
+	}
+	if err := checkSelection(t, field); err != nil {
+		return nil, err
+	}
+	if err := exp.authorize(r.Context(), PrincipalFromContext(r.Context()), Action{Table: t.Table, Op: OpRead}); err != nil {
+		return nil, err
+	}
+
+	id, err := graphqlID(args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKey, err := exp.getPrimaryKey(t.Table)
+	if err != nil {
+		return nil, fmt.Errorf("internal error")
+	}
+
+	pkValue, err := exp.primaryKeyValue(t.Table, id)
+	if err != nil {
+		return nil, nil
+	}
+
+	return exp.fetchProjected(t, primaryKey, pkValue, field)
+}
+
+func (exp Explorer) fetchProjected(t *graphqlTable, primaryKey string, pkValue any, field *gqlField) (any, error) {
+	item, err := exp.getItem(exp.db(), t.Table, primaryKey, pkValue)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("internal error")
+	}
+	exp.countReturned(t.Table, 1)
+
+	return project(t, item, field.Selections), nil
+}
+
+// resolveMutation validates the input with processForm like the REST handlers and writes it.
+func (exp Explorer) resolveMutation(r *http.Request, writeType string, t *graphqlTable, field *gqlField, args map[string]any) (any, error) {
+	allowed := []string{"id", "input"}
+	if writeType == writeCreate {
+		allowed = []string{"input"}
+	} else if writeType == writeDelete {
+		allowed = []string{"id"}
+	}
+	if err := checkArgs(field, args, allowed...); err != nil {
+		return nil, err
+	}
+
+	if writeType == writeDelete {
+		if len(field.Selections) > 0 {
+			return nil, fmt.Errorf("field %s is a scalar", field.Name)
+		}
+	} else if err := checkSelection(t, field); err != nil {
+		return nil, err
+	}
+
+	if exp.readOnly {
+		return nil, errReadOnly
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		return nil, fmt.Errorf("mutations are not available for writes requiring approval")
+	}
+	if err := exp.authorize(r.Context(), principal, Action{Table: t.Table, Op: OpWrite}); err != nil {
+		return nil, err
+	}
+
+	operation := TxOperation{Op: writeType, Table: t.Table}
+	if writeType != writeDelete {
+		input, ok := args["input"].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("input is required")
+		}
+		operation.Record = input
+	}
+	if writeType != writeCreate {
+		id, err := graphqlID(args["id"])
+		if err != nil {
+			return nil, err
+		}
+		operation.ID = id
+	}
+
+	primaryKey, err := exp.getPrimaryKey(t.Table)
+	if err != nil {
+		return nil, fmt.Errorf("internal error")
+	}
+
+	op, err := exp.txWriteOp(r, operation, primaryKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	written, err := exp.runWrite(r.Context(), principal, op)
+	if errors.As(err, &statusError{}) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("write failed")
+	}
+	addRowsAffected(r.Context(), written.Affected)
+
+	exp.Invalidate(InvalidationEvent{Type: InvalidateWrite, Table: t.Table})
+
+	if writeType == writeDelete {
+		return written.Affected, nil
+	}
+
+	return exp.fetchProjected(t, primaryKey, written.ID, field)
+}
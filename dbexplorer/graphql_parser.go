@@ -0,0 +1,482 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The parser supports the subset of GraphQL the explorer executes: queries and mutations with variables,
+// aliases, arguments and nested selections. Fragments and directives are rejected.
+
+type gqlTokenKind int
+
+const (
+	gqlEOF gqlTokenKind = iota
+	gqlPunct
+	gqlName
+	gqlInt
+	gqlFloat
+	gqlString
+)
+
+type gqlToken struct {
+	kind  gqlTokenKind
+	value string
+	pos   int
+}
+
+type gqlDocument struct {
+	Operations []*gqlOperation
+}
+
+type gqlOperation struct {
+	Type       string
+	Name       string
+	Variables  []gqlVariable
+	Selections []*gqlField
+}
+
+type gqlVariable struct {
+	Name       string
+	Type       string
+	Default    any
+	HasDefault bool
+}
+
+type gqlField struct {
+	Alias      string
+	Name       string
+	Args       map[string]any
+	Selections []*gqlField
+}
+
+// gqlVariableRef is an argument value referring to a variable of the operation.
+type gqlVariableRef struct {
+	Name string
+}
+
+// gqlEnum is a bare name value, the explorer treats enum values as strings.
+type gqlEnum string
+
+// ResponseName is the key of the field in the result, the alias if given.
+func (f *gqlField) ResponseName() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+
+	return f.Name
+}
+
+type gqlParser struct {
+	tokens []gqlToken
+	pos    int
+}
+
+func parseGraphQL(source string) (*gqlDocument, error) {
+	tokens, err := lexGraphQL(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &gqlParser{tokens: tokens}
+	doc := &gqlDocument{}
+	for p.peek().kind != gqlEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.Operations = append(doc.Operations, op)
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("no operations in the document")
+	}
+
+	return doc, nil
+}
+
+func lexGraphQL(source string) ([]gqlToken, error) {
+	var tokens []gqlToken
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(source[i:], "..."):
+			tokens = append(tokens, gqlToken{kind: gqlPunct, value: "...", pos: i})
+			i += 3
+		case strings.IndexByte("!$():=@[]{}|", c) >= 0:
+			tokens = append(tokens, gqlToken{kind: gqlPunct, value: string(c), pos: i})
+			i++
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			start := i
+			for i < len(source) && (source[i] == '_' || (source[i] >= 'a' && source[i] <= 'z') || (source[i] >= 'A' && source[i] <= 'Z') || (source[i] >= '0' && source[i] <= '9')) {
+				i++
+			}
+			tokens = append(tokens, gqlToken{kind: gqlName, value: source[start:i], pos: start})
+		case c == '-' || (c >= '0' && c <= '9'):
+			start := i
+			kind := gqlInt
+			i++
+			for i < len(source) {
+				d := source[i]
+				if d == '.' || d == 'e' || d == 'E' || ((d == '+' || d == '-') && (source[i-1] == 'e' || source[i-1] == 'E')) {
+					kind = gqlFloat
+				} else if d < '0' || d > '9' {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, gqlToken{kind: kind, value: source[start:i], pos: start})
+		case c == '"':
+			if strings.HasPrefix(source[i:], `"""`) {
+				return nil, fmt.Errorf("block strings are not supported at %d", i)
+			}
+			start := i
+			i++
+			for i < len(source) && source[i] != '"' && source[i] != '\n' {
+				if source[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(source) || source[i] != '"' {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			value, err := strconv.Unquote(source[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d", start)
+			}
+			tokens = append(tokens, gqlToken{kind: gqlString, value: value, pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+		}
+	}
+
+	return append(tokens, gqlToken{kind: gqlEOF, pos: len(source)}), nil
+}
+
+func (p *gqlParser) peek() gqlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *gqlParser) next() gqlToken {
+	t := p.tokens[p.pos]
+	if t.kind != gqlEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *gqlParser) peekPunct(value string) bool {
+	t := p.peek()
+	return t.kind == gqlPunct && t.value == value
+}
+
+func (p *gqlParser) expectPunct(value string) error {
+	t := p.next()
+	if t.kind != gqlPunct || t.value != value {
+		return p.unexpected(t, value)
+	}
+	return nil
+}
+
+func (p *gqlParser) expectName() (string, error) {
+	t := p.next()
+	if t.kind != gqlName {
+		return "", p.unexpected(t, "name")
+	}
+	return t.value, nil
+}
+
+func (p *gqlParser) unexpected(t gqlToken, expected string) error {
+	if t.kind == gqlEOF {
+		return fmt.Errorf("expected %s, got end of document", expected)
+	}
+	return fmt.Errorf("expected %s, got %q at %d", expected, t.value, t.pos)
+}
+
+func (p *gqlParser) parseOperation() (*gqlOperation, error) {
+	op := &gqlOperation{Type: "query"}
+
+	if p.peekPunct("{") {
+		selections, err := p.parseSelectionSet()
+		op.Selections = selections
+		return op, err
+	}
+
+	t := p.next()
+	if t.kind != gqlName {
+		return nil, p.unexpected(t, "operation")
+	}
+	switch t.value {
+	case "query", "mutation":
+		op.Type = t.value
+	case "fragment":
+		return nil, fmt.Errorf("fragments are not supported")
+	default:
+		return nil, fmt.Errorf("unsupported operation %s", t.value)
+	}
+
+	if p.peek().kind == gqlName {
+		op.Name = p.next().value
+	}
+
+	if p.peekPunct("(") {
+		variables, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		op.Variables = variables
+	}
+
+	if p.peekPunct("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	selections, err := p.parseSelectionSet()
+	op.Selections = selections
+	return op, err
+}
+
+func (p *gqlParser) parseVariableDefinitions() ([]gqlVariable, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+
+	var variables []gqlVariable
+	for !p.peekPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		variable := gqlVariable{Name: name, Type: typ}
+		if p.peekPunct("=") {
+			p.next()
+			variable.Default, err = p.parseValue(true)
+			if err != nil {
+				return nil, err
+			}
+			variable.HasDefault = true
+		}
+
+		variables = append(variables, variable)
+	}
+
+	return variables, p.expectPunct(")")
+}
+
+// parseType returns the type as written, e.g. "[Int!]!".
+func (p *gqlParser) parseType() (string, error) {
+	var typ string
+	if p.peekPunct("[") {
+		p.next()
+		inner, err := p.parseType()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return "", err
+		}
+		typ = "[" + inner + "]"
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return "", err
+		}
+		typ = name
+	}
+
+	if p.peekPunct("!") {
+		p.next()
+		typ += "!"
+	}
+
+	return typ, nil
+}
+
+func (p *gqlParser) parseSelectionSet() ([]*gqlField, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var fields []*gqlField
+	for !p.peekPunct("}") {
+		if p.peekPunct("...") {
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty selection set")
+	}
+
+	return fields, nil
+}
+
+func (p *gqlParser) parseField() (*gqlField, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	field := &gqlField{Name: name}
+	if p.peekPunct(":") {
+		p.next()
+		field.Alias = name
+		field.Name, err = p.expectName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekPunct("(") {
+		p.next()
+		field.Args = make(map[string]any)
+		for !p.peekPunct(")") {
+			arg, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(":"); err != nil {
+				return nil, err
+			}
+			field.Args[arg], err = p.parseValue(false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		p.next()
+	}
+
+	if p.peekPunct("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	if p.peekPunct("{") {
+		field.Selections, err = p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return field, nil
+}
+
+// parseValue parses a literal, constant values like variable defaults cannot refer to variables.
+func (p *gqlParser) parseValue(constant bool) (any, error) {
+	t := p.next()
+	switch t.kind {
+	case gqlInt:
+		n, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int %s", t.value)
+		}
+		return n, nil
+	case gqlFloat:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid float %s", t.value)
+		}
+		return f, nil
+	case gqlString:
+		return t.value, nil
+	case gqlName:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return gqlEnum(t.value), nil
+	case gqlPunct:
+		switch t.value {
+		case "$":
+			if constant {
+				return nil, fmt.Errorf("variables are not allowed at %d", t.pos)
+			}
+			name, err := p.expectName()
+			return gqlVariableRef{Name: name}, err
+		case "[":
+			list := make([]any, 0)
+			for !p.peekPunct("]") {
+				value, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			p.next()
+			return list, nil
+		case "{":
+			object := make(map[string]any)
+			for !p.peekPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				object[name], err = p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+			}
+			p.next()
+			return object, nil
+		}
+	}
+
+	return nil, p.unexpected(t, "value")
+}
+
+// resolveValue replaces variable references with the variable values, missing variables are null.
+func resolveValue(value any, variables map[string]any) any {
+	switch v := value.(type) {
+	case gqlVariableRef:
+		return variables[v.Name]
+	case gqlEnum:
+		return string(v)
+	case []any:
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = resolveValue(item, variables)
+		}
+		return list
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[key] = resolveValue(item, variables)
+		}
+		return object
+	}
+
+	return value
+}
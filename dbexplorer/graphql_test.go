@@ -0,0 +1,93 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseGraphQL(t *testing.T) {
+	doc, err := parseGraphQL(`
+		# list and fetch
+		query Items($limit: Int = 2, $where: JSON) {
+			all: items(limit: $limit, sort: "-id", where: $where) { id title }
+			items_by_pk(id: 3) { __typename, title }
+		}
+		mutation { create_items(input: {title: "a \"b\"", flags: [1, 2.5, true, null, NEW]}) { id } }
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Operations) != 2 {
+		t.Fatalf("expected 2 operations, got %d", len(doc.Operations))
+	}
+
+	query := doc.Operations[0]
+	if query.Type != "query" || query.Name != "Items" || len(query.Variables) != 2 || query.Variables[0].Default != int64(2) {
+		t.Errorf("unexpected query %+v", query)
+	}
+
+	list := query.Selections[0]
+	if list.Alias != "all" || list.Name != "items" || list.ResponseName() != "all" || len(list.Selections) != 2 {
+		t.Errorf("unexpected field %+v", list)
+	}
+
+	args := resolveValue(list.Args, map[string]any{"limit": float64(5)}).(map[string]any)
+	if args["limit"] != float64(5) || args["sort"] != "-id" || args["where"] != nil {
+		t.Errorf("unexpected arguments %#v", args)
+	}
+
+	input := resolveValue(doc.Operations[1].Selections[0].Args["input"], nil)
+	data, _ := json.Marshal(input)
+	if string(data) != `{"flags":[1,2.5,true,null,"NEW"],"title":"a \"b\""}` {
+		t.Errorf("unexpected input %s", data)
+	}
+
+	for _, source := range []string{
+		"",
+		"{ items { ...fields } }",
+		"fragment f on Items { id }",
+		"{ items @skip(if: true) { id } }",
+		"{ items { id }",
+		`{ items(q: "unterminated) { id } }`,
+		"subscription { items { id } }",
+	} {
+		if _, err := parseGraphQL(source); err == nil {
+			t.Errorf("%q: expected error", source)
+		}
+	}
+}
+
+func TestBuildGraphQLSchema(t *testing.T) {
+	exp := Explorer{
+		dialect: MySQLDialect{},
+		schema: newSchemaCache([]string{"user_items", "bad-name"}, map[string]*TableSchema{
+			"user_items": {
+				PrimaryKey: "id",
+				Columns: []ColumnInfo{
+					{Name: "id", DataType: "int"},
+					{Name: "price", DataType: "decimal", Nullable: true},
+					{Name: "done", DataType: "tinyint", ColumnType: "tinyint(1)"},
+					{Name: "meta", DataType: "json", Nullable: true},
+				},
+			},
+			"bad-name": {},
+		}),
+	}
+
+	schema := exp.buildGraphQLSchema()
+	for _, expected := range []string{
+		"type UserItems {\n  id: Int!\n  price: Float\n  done: Boolean!\n  meta: JSON\n}",
+		"user_items(limit: Int, offset: Int, sort: String, q: String, where: JSON): [UserItems!]!",
+		"update_user_items(id: ID!, input: UserItemsInput!): UserItems",
+	} {
+		if !strings.Contains(schema.sdl, expected) {
+			t.Errorf("expected %q in schema:\n%s", expected, schema.sdl)
+		}
+	}
+
+	if _, ok := schema.tables["bad-name"]; ok || len(schema.tables) != 1 {
+		t.Errorf("unexpected tables %v", schema.tables)
+	}
+}
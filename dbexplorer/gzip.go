@@ -0,0 +1,156 @@
+package dbexplorer
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// gzipMinSize is the body size below which responses are sent uncompressed.
+const gzipMinSize = 1024
+
+var gzipWriters = sync.Pool{
+	New: func() any {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// WithGzip compresses responses for clients sending Accept-Encoding: gzip. The body is compressed while
+// it is written, so large lists and exports are never held in memory.
+func WithGzip() Option {
+	return func(exp *Explorer) error {
+		exp.gzip = true
+		return nil
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, header := range r.Header.Values("Accept-Encoding") {
+		for _, part := range strings.Split(header, ",") {
+			coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+			if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+				return strings.ReplaceAll(params, " ", "") != "q=0"
+			}
+		}
+	}
+
+	return false
+}
+
+// gzipWriter holds the status and the first bytes of the body until it knows whether the response
+// is large enough to compress.
+type gzipWriter struct {
+	http.ResponseWriter
+	status      int
+	buf         []byte
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+func (w *gzipWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	switch {
+	case w.passthrough:
+		return w.ResponseWriter.Write(data)
+	case w.gz != nil:
+		return w.gz.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= gzipMinSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+// start sends the header and the buffered body, compressed if compress is set and the response allows it.
+func (w *gzipWriter) start(compress bool) error {
+	header := w.Header()
+	if compress && header.Get("Content-Encoding") == "" && w.status != http.StatusNoContent && w.status != http.StatusNotModified {
+		if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+			header.Set("Content-Type", http.DetectContentType(w.buf))
+		}
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+
+		w.gz = gzipWriters.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	} else {
+		w.passthrough = true
+	}
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+
+	return err
+}
+
+// Flush starts compressing a streamed response, e.g. NDJSON, regardless of its size so far.
+func (w *gzipWriter) Flush() {
+	if w.gz == nil && !w.passthrough {
+		w.start(true)
+	}
+
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *gzipWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *gzipWriter) close() {
+	if w.gz == nil && !w.passthrough {
+		w.start(false)
+	}
+
+	if w.gz != nil {
+		w.gz.Close()
+		gzipWriters.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		writer := &gzipWriter{ResponseWriter: w}
+		defer writer.close()
+
+		next.ServeHTTP(writer, r)
+	})
+}
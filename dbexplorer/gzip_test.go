@@ -0,0 +1,52 @@
+package dbexplorer
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"id": 1}`, 500)
+	handler := gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, large[:100])
+			io.WriteString(w, large[100:])
+			return
+		}
+		io.WriteString(w, `{"response": {}}`)
+	}))
+
+	request := func(path string, encoding string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := request("/large", "br, gzip")
+	if w.Code != http.StatusCreated || w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("expected a gzip response, got %d %v", w.Code, w.Header())
+	}
+
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err := io.ReadAll(reader); err != nil || string(body) != large {
+		t.Errorf("unexpected body %d bytes, %v", len(body), err)
+	}
+
+	if w := request("/small", "gzip"); w.Header().Get("Content-Encoding") != "" || w.Body.String() != `{"response": {}}` {
+		t.Errorf("expected a small response to stay uncompressed, got %v %q", w.Header(), w.Body.String())
+	}
+
+	if w := request("/large", "gzip;q=0"); w.Header().Get("Content-Encoding") != "" || w.Body.Len() != len(large) {
+		t.Errorf("expected no compression for q=0, got %v", w.Header())
+	}
+}
@@ -0,0 +1,28 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const healthTimeout = 2 * time.Second
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// handlerHealth answers load balancer probes, 503 when the database can't be reached.
+// See WithAuthExemptPaths to let probes in without credentials.
+func (exp Explorer) handlerHealth(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthTimeout)
+	defer cancel()
+
+	if err := exp.DB.PingContext(ctx); err != nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("database unavailable"))
+		return
+	}
+
+	writeResponse(w, HealthResponse{Status: "ok"})
+}
@@ -0,0 +1,66 @@
+package dbexplorer
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACAuth(t *testing.T) {
+	auth := &hmacAuthenticator{
+		keys:    map[string]HMACKey{"billing": {Secret: []byte("secret"), Roles: []string{"writer"}}},
+		maxSkew: time.Minute,
+		seen:    make(map[string]time.Time),
+	}
+
+	newPathRequest := func(secret string, signedAt time.Time, uri string, body string) *http.Request {
+		timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+		req := httptest.NewRequest(http.MethodPost, uri, strings.NewReader(body))
+		req.Header.Set(hmacKeyHeader, "billing")
+		req.Header.Set(hmacTimestampHeader, timestamp)
+		req.Header.Set(hmacSignatureHeader, hex.EncodeToString(hmacSignature([]byte(secret), http.MethodPost, uri, timestamp, []byte(body))))
+		return req
+	}
+	newRequest := func(secret string, signedAt time.Time, body string) *http.Request {
+		return newPathRequest(secret, signedAt, "/items/1?x=1", body)
+	}
+
+	now := time.Now()
+	principal, err := auth.Authenticate(newRequest("secret", now, `{"title":"a"}`))
+	if err != nil || principal == nil || principal.Name != "billing" {
+		t.Fatalf("expected valid signature to pass, got %v %v", principal, err)
+	}
+
+	if _, err := auth.Authenticate(newRequest("secret", now, `{"title":"a"}`)); err == nil {
+		t.Fatalf("expected replayed request to fail")
+	}
+
+	replayed := newRequest("secret", now, `{"title":"a"}`)
+	replayed.Header.Set(hmacSignatureHeader, strings.ToUpper(replayed.Header.Get(hmacSignatureHeader)))
+	if _, err := auth.Authenticate(replayed); err == nil {
+		t.Fatalf("expected replayed request with an uppercased signature to fail")
+	}
+
+	if _, err := auth.Authenticate(newRequest("wrong", now, `{"title":"b"}`)); err == nil {
+		t.Fatalf("expected wrong secret to fail")
+	}
+
+	if _, err := auth.Authenticate(newRequest("secret", now.Add(-time.Hour), `{"title":"c"}`)); err == nil {
+		t.Fatalf("expected expired request to fail")
+	}
+
+	prefixed := newPathRequest("secret", now, "/api/items/1?x=1", `{"title":"d"}`)
+	prefixed.URL.Path = "/items/1"
+	if _, err := auth.Authenticate(prefixed); err != nil {
+		t.Fatalf("expected the signature of the sent uri to pass after the prefix is stripped, got %v", err)
+	}
+
+	principal, err = auth.Authenticate(httptest.NewRequest(http.MethodGet, "/", nil))
+	if principal != nil || err != nil {
+		t.Fatalf("expected request without signature to be skipped")
+	}
+}
@@ -0,0 +1,228 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Codes of the user facing messages, the keys of message bundles.
+const (
+	MsgRecordNotFound     = "record_not_found"
+	MsgUnknownTable       = "unknown_table"
+	MsgUnauthorized       = "unauthorized"
+	MsgForbidden          = "forbidden"
+	MsgInvalidCredentials = "invalid_credentials"
+	MsgTooManyRequests    = "too_many_requests"
+	MsgDuplicateKey       = "duplicate_key"
+	MsgForeignKey         = "foreign_key_violation"
+	MsgValueTooLong       = "value_too_long"
+	MsgDatabaseBusy       = "database_busy"
+	MsgValidationFailed   = "validation_failed"
+	// MsgInvalidField has the {field} and {reason} placeholders, reasons are translated by their codes, e.g. invalid_type.
+	MsgInvalidField = "invalid_field"
+)
+
+// MessageBundle maps message codes to the messages of one language, {name} placeholders are replaced by the arguments.
+type MessageBundle map[string]string
+
+var englishMessages = MessageBundle{
+	MsgRecordNotFound:     "record not found",
+	MsgUnknownTable:       "unknown table",
+	MsgUnauthorized:       "unauthorized",
+	MsgForbidden:          "forbidden",
+	MsgInvalidCredentials: "invalid credentials",
+	MsgTooManyRequests:    "too many requests",
+	MsgDuplicateKey:       "duplicate key",
+	MsgForeignKey:         "referenced record missing or still referenced",
+	MsgValueTooLong:       "value too long",
+	MsgDatabaseBusy:       "database busy, retry the request",
+	MsgValidationFailed:   "validation failed",
+	MsgInvalidField:       "field {field} have {reason}",
+
+	reasonCode(reasonInvalidType):      reasonInvalidType,
+	reasonCode(reasonRequired):         reasonRequired,
+	reasonCode(reasonTooLong):          reasonTooLong,
+	reasonCode(reasonInvalidEnumValue): reasonInvalidEnumValue,
+	reasonCode(reasonMissingReference): reasonMissingReference,
+	reasonCode(reasonUnknownField):     reasonUnknownField,
+}
+
+var (
+	errRecordNotFound = LocalizedError{Code: MsgRecordNotFound}
+	errUnknownTable   = LocalizedError{Code: MsgUnknownTable}
+	errDuplicateKey   = LocalizedError{Code: MsgDuplicateKey}
+	errForeignKey     = LocalizedError{Code: MsgForeignKey}
+	errValueTooLong   = LocalizedError{Code: MsgValueTooLong}
+	errDatabaseBusy   = LocalizedError{Code: MsgDatabaseBusy}
+)
+
+// LocalizedError is a user facing error translated to the language of the request by its code.
+type LocalizedError struct {
+	Code string
+	Args map[string]string
+}
+
+func (e LocalizedError) Error() string {
+	return englishMessages.format(e.Code, e.Args)
+}
+
+func reasonCode(reason string) string {
+	return strings.ReplaceAll(reason, " ", "_")
+}
+
+// format falls back to the English message and to the code itself for unknown codes.
+func (b MessageBundle) format(code string, args map[string]string) string {
+	message, ok := b[code]
+	if !ok {
+		message, ok = englishMessages[code]
+	}
+	if !ok {
+		return code
+	}
+
+	for name, value := range args {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+
+	return message
+}
+
+// reason translates a validation reason, reasons without a code stay as they are.
+func (b MessageBundle) reason(reason string) string {
+	if _, ok := englishMessages[reasonCode(reason)]; ok {
+		return b.format(reasonCode(reason), nil)
+	}
+
+	return reason
+}
+
+// translate returns the message of the error in the language of the bundle, errors without a code stay as they are.
+func (b MessageBundle) translate(err error) string {
+	switch e := err.(type) {
+	case LocalizedError:
+		return b.format(e.Code, e.Args)
+	case ValidationError:
+		return b.format(MsgInvalidField, map[string]string{"field": e.Field, "reason": b.reason(e.Reason)})
+	case ValidationErrors:
+		messages := make([]string, len(e))
+		for i, v := range e {
+			messages[i] = b.translate(v)
+		}
+		return strings.Join(messages, "; ")
+	}
+
+	return err.Error()
+}
+
+// WithMessageBundle adds the translations of the language, e.g. "de" or "pt-BR", selected by Accept-Language.
+// Bundles of the same language are merged, messages missing in a bundle are written in English.
+func WithMessageBundle(lang string, bundle MessageBundle) Option {
+	return func(exp *Explorer) error {
+		if exp.messageBundles == nil {
+			exp.messageBundles = make(map[string]MessageBundle)
+		}
+
+		lang = strings.ToLower(lang)
+		merged := exp.messageBundles[lang]
+		if merged == nil {
+			merged = make(MessageBundle)
+			exp.messageBundles[lang] = merged
+		}
+
+		for code, message := range bundle {
+			merged[code] = message
+		}
+		return nil
+	}
+}
+
+// negotiateLanguage picks the bundle of the most preferred language of the Accept-Language header,
+// nil when English or no supported language is preferred.
+func (exp Explorer) negotiateLanguage(header string) (string, MessageBundle) {
+	type preference struct {
+		lang string
+		q    float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if lang != "" && q > 0 {
+			preferences = append(preferences, preference{lang: strings.ToLower(lang), q: q})
+		}
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].q > preferences[j].q
+	})
+
+	for _, p := range preferences {
+		if p.lang == "*" || p.lang == "en" || strings.HasPrefix(p.lang, "en-") {
+			return "", nil
+		}
+
+		if bundle, ok := exp.messageBundles[p.lang]; ok {
+			return p.lang, bundle
+		}
+
+		base, _, _ := strings.Cut(p.lang, "-")
+		if bundle, ok := exp.messageBundles[base]; ok {
+			return base, bundle
+		}
+	}
+
+	return "", nil
+}
+
+// localizedWriter carries the message bundle of the request to writeError.
+type localizedWriter struct {
+	http.ResponseWriter
+	lang   string
+	bundle MessageBundle
+}
+
+func (w *localizedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *localizedWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (exp Explorer) localeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang, bundle := exp.negotiateLanguage(r.Header.Get("Accept-Language"))
+		if bundle != nil {
+			w = &localizedWriter{ResponseWriter: w, lang: lang, bundle: bundle}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// responseBundle finds the message bundle of the request through the wrapping response writers.
+func responseBundle(w http.ResponseWriter) *localizedWriter {
+	for {
+		switch v := w.(type) {
+		case *localizedWriter:
+			return v
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
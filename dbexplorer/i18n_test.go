@@ -0,0 +1,60 @@
+package dbexplorer
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	exp := Explorer{}
+	for _, opt := range []Option{
+		WithMessageBundle("de", MessageBundle{MsgRecordNotFound: "Datensatz nicht gefunden"}),
+		WithMessageBundle("pt-BR", MessageBundle{MsgRecordNotFound: "registro não encontrado"}),
+	} {
+		if err := opt(&exp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]string{
+		"":                       "",
+		"de":                     "de",
+		"de-AT":                  "de",
+		"pt-br":                  "pt-br",
+		"fr, de;q=0.5":           "de",
+		"en;q=0.9, de;q=0.8":     "",
+		"de;q=0.2, en-US;q=0.9":  "",
+		"de;q=0, pt-BR;q=0.1":    "pt-br",
+		"ja, *;q=0.1, de;q=0.05": "",
+	}
+
+	for header, expected := range cases {
+		lang, _ := exp.negotiateLanguage(header)
+		if lang != expected {
+			t.Errorf("%q: expected %q, got %q", header, expected, lang)
+		}
+	}
+}
+
+func TestWriteLocalizedError(t *testing.T) {
+	bundle := MessageBundle{
+		MsgValidationFailed:           "Validierung fehlgeschlagen",
+		reasonCode(reasonInvalidType): "ungültiger Typ",
+	}
+
+	rec := httptest.NewRecorder()
+	w := &statusRecorder{ResponseWriter: &localizedWriter{ResponseWriter: rec, lang: "de", bundle: bundle}}
+	writeError(w, 400, ValidationErrors{NewValidationError("id"), {Field: "title", Reason: reasonRequired}})
+
+	expected := `{"error":"Validierung fehlgeschlagen","errors":[{"field":"id","reason":"invalid type"},{"field":"title","reason":"required value"}],"fields":{"id":"ungültiger Typ","title":"required value"}}`
+	if rec.Body.String() != expected {
+		t.Errorf("expected %s, got %s", expected, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Language") != "de" {
+		t.Errorf("expected Content-Language de, got %q", rec.Header().Get("Content-Language"))
+	}
+
+	if errRecordNotFound.Error() != "record not found" {
+		t.Errorf("expected English message, got %q", errRecordNotFound.Error())
+	}
+}
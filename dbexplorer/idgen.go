@@ -0,0 +1,146 @@
+package dbexplorer
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IDGenerator returns the primary key of a new record.
+type IDGenerator func() (any, error)
+
+// WithIDGenerator generates the primary key of records created in the table, for keys that aren't
+// auto-increment. Keys sent by clients are ignored and the generated one is returned by the create.
+func WithIDGenerator(table string, generator IDGenerator) Option {
+	return func(exp *Explorer) error {
+		if exp.idGenerators == nil {
+			exp.idGenerators = make(map[string]IDGenerator)
+		}
+
+		exp.idGenerators[table] = generator
+		return nil
+	}
+}
+
+// generateID returns a copy of the form with a generated primary key, or the form itself for tables without a generator.
+func (exp Explorer) generateID(table string, form map[string]any) (map[string]any, error) {
+	generator, ok := exp.idGenerators[table]
+	if !ok {
+		return form, nil
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := generator()
+	if err != nil {
+		return nil, fmt.Errorf("generate id of %s: %w", table, err)
+	}
+
+	generated := make(map[string]any, len(form)+1)
+	for column, value := range form {
+		generated[column] = value
+	}
+	generated[schema.PrimaryKey] = id
+
+	return generated, nil
+}
+
+func timestampedRandom() ([16]byte, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[6:]); err != nil {
+		return b, err
+	}
+
+	ms := uint64(time.Now().UnixMilli())
+	b[0], b[1], b[2], b[3], b[4], b[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+
+	return b, nil
+}
+
+// GenerateUUIDv7 returns time ordered UUIDs (RFC 9562) as strings.
+func GenerateUUIDv7() (any, error) {
+	b, err := timestampedRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	b[6] = b[6]&0x0f | 0x70
+	b[8] = b[8]&0x3f | 0x80
+
+	s := hex.EncodeToString(b[:])
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// GenerateULID returns ULIDs, 26 characters sortable by creation time.
+func GenerateULID() (any, error) {
+	b, err := timestampedRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	// 128 bits in 26 groups of 5 bits, the first group has the 3 leftover high bits
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(out), nil
+}
+
+// snowflakeEpoch is the epoch of Twitter snowflakes, 2010-11-04.
+const snowflakeEpoch = 1288834974657
+
+type snowflake struct {
+	mu       sync.Mutex
+	node     int64
+	lastMs   int64
+	sequence int64
+}
+
+// NewSnowflakeGenerator returns 64-bit ids of 41 bits of milliseconds, 10 bits of node and 12 bits
+// of sequence. Every explorer instance writing the table needs its own node, 0 to 1023.
+func NewSnowflakeGenerator(node int64) (IDGenerator, error) {
+	if node < 0 || node > 1023 {
+		return nil, fmt.Errorf("snowflake node must be between 0 and 1023")
+	}
+
+	s := &snowflake{node: node}
+	return s.next, nil
+}
+
+func (s *snowflake) next() (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms := time.Now().UnixMilli() - snowflakeEpoch
+	if ms < s.lastMs {
+		// the clock went back, keep counting from the last timestamp
+		ms = s.lastMs
+	}
+
+	if ms == s.lastMs {
+		s.sequence = (s.sequence + 1) & 0xfff
+		if s.sequence == 0 {
+			for ms <= s.lastMs {
+				time.Sleep(time.Millisecond / 10)
+				ms = time.Now().UnixMilli() - snowflakeEpoch
+			}
+		}
+	} else {
+		s.sequence = 0
+	}
+	s.lastMs = ms
+
+	return ms<<22 | s.node<<12 | s.sequence, nil
+}
@@ -0,0 +1,53 @@
+package dbexplorer
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestIDGenerators(t *testing.T) {
+	uuid, err := GenerateUUIDv7()
+	if err != nil || !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid.(string)) {
+		t.Errorf("unexpected uuid %v, %v", uuid, err)
+	}
+
+	ulid, err := GenerateULID()
+	if err != nil || !regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`).MatchString(ulid.(string)) {
+		t.Errorf("unexpected ulid %v, %v", ulid, err)
+	}
+
+	if _, err := NewSnowflakeGenerator(1024); err == nil {
+		t.Errorf("expected error for a node out of range")
+	}
+
+	generator, err := NewSnowflakeGenerator(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last int64
+	for i := 0; i < 5000; i++ {
+		id, _ := generator()
+		if id.(int64) <= last || id.(int64)>>12&0x3ff != 5 {
+			t.Fatalf("unexpected snowflake %d after %d", id, last)
+		}
+		last = id.(int64)
+	}
+}
+
+func TestGenerateID(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"events": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "char"}}},
+	})
+	exp.idGenerators = map[string]IDGenerator{"events": func() (any, error) { return "generated", nil }}
+
+	form := map[string]any{"title": "x"}
+	generated, err := exp.generateID("events", form)
+	if err != nil || generated["id"] != "generated" || generated["title"] != "x" || len(form) != 1 {
+		t.Errorf("unexpected form %v, %v", generated, err)
+	}
+
+	if !exp.primaryKeyGenerated("events") {
+		t.Errorf("expected the key to count as generated")
+	}
+}
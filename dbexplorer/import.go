@@ -0,0 +1,234 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxImportSize = 32 << 20
+
+	importCSV  = "csv"
+	importXLSX = "xlsx"
+
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+type ImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+// importFormat detects the upload format by the file extension or the content type.
+func importFormat(filename string, contentType string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return importCSV
+	case ".xlsx":
+		return importXLSX
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return importCSV
+	case xlsxContentType:
+		return importXLSX
+	}
+
+	return ""
+}
+
+// readImportUpload returns the uploaded file and its format. The file is either the "file" field
+// of a multipart form or the request body itself.
+func readImportUpload(r *http.Request) ([]byte, string, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxImportSize)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		data, err := io.ReadAll(r.Body)
+		return data, importFormat("", r.Header.Get("Content-Type")), err
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	return data, importFormat(header.Filename, header.Header.Get("Content-Type")), err
+}
+
+func readImportRows(data []byte, format string) ([][]string, error) {
+	switch format {
+	case importCSV:
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		return reader.ReadAll()
+	case importXLSX:
+		return readXLSX(data)
+	}
+
+	return nil, fmt.Errorf("unsupported import format, expected csv or xlsx")
+}
+
+// importValue converts a cell to the form value of the column. Empty cells of nullable columns are NULL.
+func importValue(column ColumnInfo, cell string) (any, error) {
+	if cell == "" && column.Nullable {
+		return nil, nil
+	}
+
+	if isNumericDataType(column.DataType) {
+		if _, err := strconv.ParseFloat(cell, 64); err != nil {
+			return nil, NewValidationError(column.Name)
+		}
+
+		return json.Number(cell), nil
+	}
+
+	return cell, nil
+}
+
+// importForms maps the rows to forms by the header row and validates them like created items.
+func (exp Explorer) importForms(ctx context.Context, table string, primaryKey string, rows [][]string, principal *Principal) ([]map[string]any, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("header row is required")
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]ColumnInfo, len(rows[0]))
+	seen := make(map[string]bool)
+	for i, name := range rows[0] {
+		name = strings.TrimSpace(name)
+		column, ok := schema.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %s", name)
+		}
+
+		seen[name] = true
+		header[i] = column
+	}
+
+	validationOptions := ValidationOptions{
+		IgnorePk:          true,
+		IncludePk:         !exp.primaryKeyGenerated(table),
+		WithDefaultValues: true,
+		ColumnWritable:    exp.columnWritable(ctx, table, principal),
+	}
+
+	forms := make([]map[string]any, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		line := i + 2
+		if len(row) > len(header) {
+			return nil, fmt.Errorf("row %d: too many values", line)
+		}
+
+		form := make(map[string]any)
+		errs := make(ValidationErrors, 0)
+		for j, cell := range row {
+			value, err := importValue(header[j], cell)
+			if err != nil {
+				errs = append(errs, err.(ValidationError))
+				continue
+			}
+			form[header[j].Name] = value
+		}
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("row %d: %w", line, errs)
+		}
+
+		newForm, err := exp.processForm(table, form, primaryKey, validationOptions)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", line, err)
+		}
+
+		forms = append(forms, newForm)
+	}
+
+	return forms, nil
+}
+
+// handlerImport inserts every row of an uploaded CSV or XLSX file in a single transaction,
+// nothing is imported if any row is invalid.
+func (exp Explorer) handlerImport(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("import is not allowed for writes requiring approval"))
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	data, format, err := readImportUpload(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, err := readImportRows(data, format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	forms, err := exp.importForms(r.Context(), tableName, primaryKey, rows, principal)
+	if err != nil {
+		exp.writeFormError(w, r, err)
+		return
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	for i, form := range forms {
+		op := writeOp{
+			Op:         writeCreate,
+			Table:      tableName,
+			PrimaryKey: primaryKey,
+			Form:       form,
+		}
+
+		if _, err := exp.executeWrite(r.Context(), tx, op, auditMeta{Principal: principal}); err != nil {
+			tx.Rollback()
+			writeError(w, http.StatusBadRequest, fmt.Errorf("row %d: insert failed", i+2))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	writeResponse(w, ImportResponse{Imported: len(forms)})
+}
@@ -0,0 +1,130 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type ipAllowlist struct {
+	allowed        []*net.IPNet
+	trustedProxies []*net.IPNet
+}
+
+// WithIPAllowlist rejects requests whose client address is outside of the given CIDR ranges with 403.
+func WithIPAllowlist(cidrs ...string) Option {
+	return func(exp *Explorer) error {
+		networks, err := parseCIDRs(cidrs)
+		if err != nil {
+			return err
+		}
+
+		if exp.ipAllowlist == nil {
+			exp.ipAllowlist = &ipAllowlist{}
+		}
+		exp.ipAllowlist.allowed = append(exp.ipAllowlist.allowed, networks...)
+
+		return nil
+	}
+}
+
+// WithTrustedProxies enables X-Forwarded-For handling for requests coming from the given CIDR ranges.
+// Without trusted proxies the header is ignored and the TCP peer address is used as is.
+func WithTrustedProxies(cidrs ...string) Option {
+	return func(exp *Explorer) error {
+		networks, err := parseCIDRs(cidrs)
+		if err != nil {
+			return err
+		}
+
+		if exp.ipAllowlist == nil {
+			exp.ipAllowlist = &ipAllowlist{}
+		}
+		exp.ipAllowlist.trustedProxies = append(exp.ipAllowlist.trustedProxies, networks...)
+
+		return nil
+	}
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip address %q", cidr)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			cidr = fmt.Sprintf("%s/%d", cidr, bits)
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP resolves the address of the client. X-Forwarded-For is walked from right to left
+// only while the hops are trusted proxies, so a client can't spoof its address by sending the header itself.
+func (l *ipAllowlist) clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(l.trustedProxies, ip) {
+		return ip
+	}
+
+	hops := make([]string, 0)
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			return ip
+		}
+
+		ip = hop
+		if !containsIP(l.trustedProxies, ip) {
+			return ip
+		}
+	}
+
+	return ip
+}
+
+func (l *ipAllowlist) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := l.clientIP(r)
+		if len(l.allowed) > 0 && (ip == nil || !containsIP(l.allowed, ip)) {
+			writeError(w, http.StatusForbidden, LocalizedError{Code: MsgForbidden})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,53 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPAllowlist(t *testing.T) {
+	allowed, err := parseCIDRs([]string{"10.0.0.0/8", "192.168.1.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxies, err := parseCIDRs([]string{"172.16.0.0/12"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowlist := &ipAllowlist{allowed: allowed, trustedProxies: proxies}
+	handler := allowlist.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	cases := []struct {
+		remoteAddr string
+		forwarded  string
+		status     int
+	}{
+		{"10.1.2.3:1234", "", http.StatusOK},
+		{"192.168.1.5:1234", "", http.StatusOK},
+		{"192.168.1.6:1234", "", http.StatusForbidden},
+		// заголовок от недоверенного клиента игнорируется
+		{"8.8.8.8:1234", "10.1.2.3", http.StatusForbidden},
+		{"172.16.0.1:1234", "10.1.2.3", http.StatusOK},
+		{"172.16.0.1:1234", "8.8.8.8", http.StatusForbidden},
+		{"172.16.0.1:1234", "10.1.2.3, 172.16.0.2", http.StatusOK},
+		{"172.16.0.1:1234", "10.1.2.3, 8.8.8.8", http.StatusForbidden},
+	}
+
+	for idx, item := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = item.remoteAddr
+		if item.forwarded != "" {
+			req.Header.Set("X-Forwarded-For", item.forwarded)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != item.status {
+			t.Errorf("case %d: expected status %d, got %d", idx, item.status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+)
+
+// Job is a long running operation executed in the background, e.g. an archival.
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Table      string     `json:"table,omitempty"`
+	Principal  any        `json:"principal"`
+	Status     string     `json:"status"`
+	Done       int64      `json:"done"`
+	Total      int64      `json:"total"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type GetJobsResponse struct {
+	Jobs []Job `json:"jobs"`
+}
+
+type GetJobResponse struct {
+	Job Job `json:"job"`
+}
+
+// jobManager keeps the jobs of the process in memory, they are lost on restart.
+type jobManager struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+func newJobManager() *jobManager {
+	return &jobManager{
+		jobs: make(map[string]*Job),
+	}
+}
+
+// jobProgress lets the job function report its progress.
+type jobProgress struct {
+	manager *jobManager
+	id      string
+}
+
+func (p jobProgress) SetTotal(total int64) {
+	p.manager.update(p.id, func(job *Job) {
+		job.Total = total
+	})
+}
+
+func (p jobProgress) Add(done int64) {
+	p.manager.update(p.id, func(job *Job) {
+		job.Done += done
+	})
+}
+
+func (m *jobManager) update(id string, fn func(job *Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if job, ok := m.jobs[id]; ok {
+		fn(job)
+	}
+}
+
+// start runs fn in a new goroutine and returns the job tracking it.
+func (m *jobManager) start(kind string, table string, principal *Principal, fn func(progress jobProgress) error) (Job, error) {
+	id, err := randomToken()
+	if err != nil {
+		return Job{}, err
+	}
+
+	job := &Job{
+		ID:        id[:16],
+		Kind:      kind,
+		Table:     table,
+		Principal: principalName(principal),
+		Status:    jobRunning,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	m.mu.Lock()
+	m.jobs[job.ID] = job
+	snapshot := *job
+	m.mu.Unlock()
+
+	go func() {
+		err := fn(jobProgress{manager: m, id: job.ID})
+
+		m.update(job.ID, func(job *Job) {
+			finishedAt := time.Now().UTC()
+			job.FinishedAt = &finishedAt
+			job.Status = jobSucceeded
+			if err != nil {
+				job.Status = jobFailed
+				job.Error = err.Error()
+			}
+		})
+	}()
+
+	return snapshot, nil
+}
+
+func (m *jobManager) get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+
+	return *job, true
+}
+
+func (m *jobManager) list() []Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, *job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+
+	return jobs
+}
+
+func (exp Explorer) handlerGetJobs(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireAdmin(w, r) {
+		return
+	}
+
+	writeResponse(w, GetJobsResponse{Jobs: exp.jobs.list()})
+}
+
+// handlerGetJob is available to admins and the principal who started the job.
+func (exp Explorer) handlerGetJob(w http.ResponseWriter, r *http.Request) {
+	id := strings.Split(r.URL.Path, "/")[2]
+	job, ok := exp.jobs.get(id)
+
+	principal := PrincipalFromContext(r.Context())
+	if ok && job.Principal != principalName(principal) && !principal.HasRole(exp.adminRole) {
+		ok = false
+	}
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job not found"))
+		return
+	}
+
+	writeResponse(w, GetJobResponse{Job: job})
+}
@@ -0,0 +1,55 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func waitJob(t *testing.T, m *jobManager, id string) Job {
+	for i := 0; i < 100; i++ {
+		job, ok := m.get(id)
+		if !ok {
+			t.Fatalf("job %s not found", id)
+		}
+		if job.Status != jobRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s is still running", id)
+	return Job{}
+}
+
+func TestJobManager(t *testing.T) {
+	m := newJobManager()
+
+	job, err := m.start("archive", "items", nil, func(progress jobProgress) error {
+		progress.SetTotal(3)
+		progress.Add(2)
+		progress.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job = waitJob(t, m, job.ID)
+	if job.Status != jobSucceeded || job.Done != 3 || job.Total != 3 || job.FinishedAt == nil {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	failed, _ := m.start("archive", "items", nil, func(progress jobProgress) error {
+		return fmt.Errorf("boom")
+	})
+
+	failed = waitJob(t, m, failed.ID)
+	if failed.Status != jobFailed || failed.Error != "boom" {
+		t.Errorf("unexpected job %+v", failed)
+	}
+
+	if jobs := m.list(); len(jobs) != 2 {
+		t.Errorf("expected 2 jobs, got %d", len(jobs))
+	}
+}
@@ -0,0 +1,131 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// journalSkippedPaths carry credentials, they are not journaled.
+var journalSkippedPaths = map[string]bool{
+	"/_login":  true,
+	"/_logout": true,
+}
+
+// JournalEntry is a mutating request as it was received. JSON bodies are kept as is in Body,
+// others base64 encoded in RawBody.
+type JournalEntry struct {
+	Time        time.Time       `json:"time"`
+	RequestID   string          `json:"request_id,omitempty"`
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	Query       string          `json:"query,omitempty"`
+	Principal   string          `json:"principal,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	RawBody     []byte          `json:"raw_body,omitempty"`
+}
+
+// Journal is an append-only log of requests. Append must return only once the entry is durable.
+type Journal interface {
+	Append(entry JournalEntry) error
+}
+
+type fileJournal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileJournal appends the entries as JSON lines to the file, synced to disk after every entry.
+func NewFileJournal(path string) (Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileJournal{file: file}, nil
+}
+
+func (j *fileJournal) Append(entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return j.file.Sync()
+}
+
+// WithJournal writes every mutating request to the journal before it is executed, e.g. to replay
+// it into another environment or to reconstruct what happened independently of the audit table.
+// Requests that can't be journaled are rejected with 503.
+func WithJournal(journal Journal) Option {
+	return func(exp *Explorer) error {
+		exp.journal = journal
+		return nil
+	}
+}
+
+func newJournalEntry(r *http.Request, body []byte) JournalEntry {
+	entry := JournalEntry{
+		Time:        time.Now().UTC(),
+		RequestID:   r.Header.Get(requestIDHeader),
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		Query:       r.URL.RawQuery,
+		ContentType: r.Header.Get("Content-Type"),
+	}
+
+	if principal := PrincipalFromContext(r.Context()); principal != nil {
+		entry.Principal = principal.Name
+	}
+
+	if len(body) > 0 {
+		if json.Valid(body) {
+			entry.Body = json.RawMessage(body)
+		} else {
+			entry.RawBody = body
+		}
+	}
+
+	return entry
+}
+
+// journalMiddleware runs after authentication and the permission checks, so only requests that are
+// about to be executed are journaled.
+func (exp Explorer) journalMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isReadRequest(r) || journalSkippedPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeBodyError(w, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err := exp.journal.Append(newJournalEntry(r, body)); err != nil {
+			if holder, ok := r.Context().Value(reportContextKey{}).(*requestError); ok {
+				holder.err = err
+			}
+			writeError(w, http.StatusServiceUnavailable, fmt.Errorf("journal unavailable"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,91 @@
+package dbexplorer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type failingJournal struct{}
+
+func (failingJournal) Append(JournalEntry) error {
+	return errors.New("disk full")
+}
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.log")
+	journal, err := NewFileJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Explorer{journal: journal}
+	var seen string
+	handler := exp.journalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seen = string(body)
+	}))
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/items", nil),
+		httptest.NewRequest(http.MethodPut, "/items/?dry_run=true", strings.NewReader(`{"title":"a"}`)),
+		httptest.NewRequest(http.MethodPost, "/_login", strings.NewReader(`{"password":"secret"}`)),
+		httptest.NewRequest(http.MethodDelete, "/items/1", strings.NewReader("not json")),
+	}
+	requests[1] = requests[1].WithContext(ContextWithPrincipal(requests[1].Context(), &Principal{Name: "bob"}))
+
+	for _, r := range requests {
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if seen != "not json" {
+		t.Errorf("body is not passed on: %q", seen)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if e := entries[0]; e.Method != http.MethodPut || e.Path != "/items/" || e.Query != "dry_run=true" ||
+		e.Principal != "bob" || string(e.Body) != `{"title":"a"}` {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if e := entries[1]; e.Method != http.MethodDelete || string(e.RawBody) != "not json" || e.Body != nil {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
+
+func TestJournalFailure(t *testing.T) {
+	exp := Explorer{journal: failingJournal{}}
+	executed := false
+	handler := exp.journalMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		executed = true
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items/1", strings.NewReader(`{}`)))
+	if w.Code != http.StatusServiceUnavailable || executed {
+		t.Errorf("expected 503 without executing, got %d", w.Code)
+	}
+}
@@ -0,0 +1,61 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+)
+
+func isJSONDataType(dataType string) bool {
+	return dataType == "json" || dataType == "jsonb"
+}
+
+func (exp Explorer) isJSONColumn(table string, column string) bool {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return false
+	}
+
+	info, ok := schema.Column(column)
+	return ok && isJSONDataType(info.DataType)
+}
+
+// jsonColumnValue encodes a form value of a JSON column, objects and arrays included.
+// nil is stored as SQL NULL rather than the JSON null.
+func jsonColumnValue(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// renderJSON returns the documents of JSON columns as nested JSON instead of escaped strings.
+func (exp Explorer) renderJSON(table string, items ...map[string]any) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return
+	}
+
+	for _, c := range schema.Columns {
+		if !isJSONDataType(c.DataType) {
+			continue
+		}
+
+		for _, item := range items {
+			text, ok := item[c.Name].(string)
+			if ok && json.Valid([]byte(text)) {
+				item[c.Name] = json.RawMessage(text)
+			}
+		}
+	}
+}
+
+// renderColumns converts the values of a table to their JSON representation.
+func (exp Explorer) renderColumns(table string, items ...map[string]any) {
+	exp.renderBooleans(table, items...)
+	exp.renderJSON(table, items...)
+}
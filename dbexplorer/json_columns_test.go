@@ -0,0 +1,31 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONColumns(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"events": {
+			PrimaryKey: "id",
+			Columns: []ColumnInfo{
+				{Name: "id", DataType: "int"},
+				{Name: "payload", DataType: "json"},
+			},
+		},
+	})
+
+	value, err := jsonColumnValue(map[string]any{"tags": []any{"a", "b"}})
+	if err != nil || value != `{"tags":["a","b"]}` {
+		t.Errorf("unexpected value %#v (%v)", value, err)
+	}
+
+	item := map[string]any{"id": int64(1), "payload": `{"tags":["a","b"]}`}
+	exp.renderColumns("events", item)
+
+	data, _ := json.Marshal(item)
+	if expected := `{"id":1,"payload":{"tags":["a","b"]}}`; string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
@@ -0,0 +1,150 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultLeaderCheckInterval = 5 * time.Second
+
+// leaderElection holds a session advisory lock on a dedicated connection, the instance holding it is the leader.
+type leaderElection struct {
+	lockName string
+	interval time.Duration
+
+	mu     sync.Mutex
+	conn   *sql.Conn
+	leader atomic.Bool
+}
+
+// WithLeaderElection runs the scheduled tasks, like retention purges, only on the instance holding the advisory
+// lock lockName, so that instances behind a load balancer don't run them N times. The leader keeps a database
+// connection for the lock, the other instances try to take it over every interval, 5 seconds by default.
+func WithLeaderElection(lockName string, interval time.Duration) Option {
+	return func(exp *Explorer) error {
+		if lockName == "" {
+			return fmt.Errorf("leader election: lock name is required")
+		}
+		if interval == 0 {
+			interval = defaultLeaderCheckInterval
+		}
+
+		exp.leader = &leaderElection{
+			lockName: lockName,
+			interval: interval,
+		}
+		return nil
+	}
+}
+
+// IsLeader reports whether the instance runs the scheduled tasks, always true without leader election.
+func (exp Explorer) IsLeader() bool {
+	if exp.leader == nil {
+		return true
+	}
+
+	return exp.leader.leader.Load()
+}
+
+func (exp Explorer) initLeader() {
+	exp.metrics.describe("db_explorer_leader_changes_total", "Leadership acquisitions and losses of the instance.")
+
+	if exp.leader == nil {
+		return
+	}
+
+	exp.scheduler.gate = exp.IsLeader
+	exp.scheduler.background("leader", func(stop <-chan struct{}) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			<-stop
+			cancel()
+		}()
+
+		ticker := time.NewTicker(exp.leader.interval)
+		defer ticker.Stop()
+
+		for {
+			exp.campaign(ctx)
+
+			select {
+			case <-stop:
+				exp.resign()
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// campaign checks that the held lock is still alive or tries to acquire it.
+func (exp Explorer) campaign(ctx context.Context) {
+	l := exp.leader
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return
+		}
+
+		// the lock is gone with the session
+		l.conn.Close()
+		l.conn = nil
+		exp.setLeader(false)
+	}
+
+	conn, err := exp.DB.Conn(ctx)
+	if err != nil {
+		return
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, rebind(exp.dialect, exp.dialect.TryLockQuery()), l.lockName).Scan(&acquired)
+	if err != nil || !acquired {
+		conn.Close()
+		return
+	}
+
+	l.conn = conn
+	exp.setLeader(true)
+}
+
+// resign releases the lock so that another instance takes over without waiting for the session to end.
+func (exp Explorer) resign() {
+	l := exp.leader
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.interval)
+	defer cancel()
+
+	l.conn.ExecContext(ctx, rebind(exp.dialect, exp.dialect.UnlockQuery()), l.lockName)
+	l.conn.Close()
+	l.conn = nil
+	exp.setLeader(false)
+}
+
+func (exp Explorer) setLeader(leader bool) {
+	if exp.leader.leader.Swap(leader) == leader {
+		return
+	}
+
+	event := "lost"
+	if leader {
+		event = "acquired"
+	}
+	exp.metrics.add("db_explorer_leader_changes_total", 1, "event", event)
+}
@@ -0,0 +1,30 @@
+package dbexplorer
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchedulerGate(t *testing.T) {
+	var leader atomic.Bool
+	var runs atomic.Int32
+
+	s := newScheduler()
+	s.gate = leader.Load
+	s.every("task", time.Millisecond, func() { runs.Add(1) })
+	s.start()
+
+	time.Sleep(20 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Fatalf("expected no runs while not the leader, got %d", runs.Load())
+	}
+
+	leader.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	s.shutdown()
+
+	if runs.Load() == 0 {
+		t.Errorf("expected runs after becoming the leader")
+	}
+}
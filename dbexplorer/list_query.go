@@ -0,0 +1,289 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const scoreColumn = "_score"
+
+// ListQuery is a parsed list request.
+type ListQuery struct {
+	Pagination Pagination
+	Search     string
+	Sort       []SortField
+	Filters    []Filter
+	Expr       *FilterExpr
+	Partition  string
+	// Fields are the columns to return, nil for all.
+	Fields []string
+	// IndexHint is an index the query is forced to use, see indexHinter.
+	IndexHint string
+	// Keyset and After are set for cursor pagination.
+	Keyset []SortField
+	After  []any
+}
+
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+func (exp Explorer) parseListQuery(table string, query url.Values) (ListQuery, error) {
+	pagination, err := exp.tablePagination(table, query)
+	if err != nil {
+		return ListQuery{}, err
+	}
+
+	listQuery := ListQuery{
+		Pagination: pagination,
+		Search:     query.Get("q"),
+	}
+
+	filters, err := exp.parseFilters(table, query)
+	if err != nil {
+		return listQuery, err
+	}
+	listQuery.Filters = filters
+
+	if query.Has("filter") {
+		listQuery.Expr, err = exp.parseFilterExpr(table, query.Get("filter"))
+		if err != nil {
+			return listQuery, err
+		}
+	}
+
+	listQuery.Partition, err = exp.parsePartition(table, query.Get("partition"))
+	if err != nil {
+		return listQuery, err
+	}
+
+	listQuery.Fields, err = exp.parseFields(table, query)
+	if err != nil {
+		return listQuery, err
+	}
+
+	sort, err := exp.parseSort(table, query, listQuery.Search != "")
+	if err != nil {
+		return listQuery, err
+	}
+	listQuery.Sort = sort
+
+	if err := exp.parseCursor(table, query, &listQuery); err != nil {
+		return listQuery, err
+	}
+
+	return listQuery, nil
+}
+
+// parseSort parses ?sort=a,-b or ?sort=a&order=desc. The order applies to columns without a "-" prefix.
+func (exp Explorer) parseSort(table string, query url.Values, withSearch bool) ([]SortField, error) {
+	sort := query.Get("sort")
+	if sort == "" {
+		return nil, nil
+	}
+
+	defaultDesc := false
+	switch strings.ToLower(query.Get("order")) {
+	case "", "asc":
+	case "desc":
+		defaultDesc = true
+	default:
+		return nil, fmt.Errorf("invalid order %s", query.Get("order"))
+	}
+
+	fields := make([]SortField, 0)
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(sort, ",") {
+		field := SortField{Column: strings.TrimSpace(name), Desc: defaultDesc}
+		if strings.HasPrefix(field.Column, "-") {
+			field.Column = field.Column[1:]
+			field.Desc = true
+		}
+
+		switch {
+		case field.Column == scoreColumn:
+			if !withSearch {
+				return nil, fmt.Errorf("sort by %s requires q", scoreColumn)
+			}
+			// the best matches go first unless the order is given explicitly
+			if query.Get("order") == "" {
+				field.Desc = true
+			}
+		case !exp.isValidColumnName(table, field.Column):
+			return nil, fmt.Errorf("unknown sort column %s", field.Column)
+		}
+
+		if seen[field.Column] {
+			continue
+		}
+		seen[field.Column] = true
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// orderBy returns the ORDER BY clause for the sort fields. The primary key is appended as a tie-breaker
+// so pages don't overlap when the sort columns have duplicates.
+func (exp Explorer) orderBy(table string, sort []SortField) (string, error) {
+	if len(sort) == 0 {
+		return "", nil
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return "", err
+	}
+
+	terms := make([]string, 0, len(sort)+1)
+	hasPrimaryKey := false
+	for _, field := range sort {
+		term := exp.quoteColumn(table, field.Column)
+		if field.Desc {
+			term += " DESC"
+		}
+		terms = append(terms, term)
+
+		if field.Column == schema.PrimaryKey {
+			hasPrimaryKey = true
+		}
+	}
+
+	if !hasPrimaryKey && schema.PrimaryKey != "" {
+		terms = append(terms, exp.quoteColumn(table, schema.PrimaryKey))
+	}
+
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
+
+func (exp Explorer) searchColumns(table string) ([]string, error) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0)
+	for _, c := range schema.Columns {
+		if isTextDataType(c.DataType) {
+			columns = append(columns, c.Name)
+		}
+	}
+
+	return columns, nil
+}
+
+// searchClause matches q against every text column of the table. The score is 1 for an exact match
+// of every column and decreases for prefix and substring matches, so it is comparable between tables.
+func (exp Explorer) searchClause(table string, q string) (score string, scoreArgs []any, condition string, conditionArgs []any, err error) {
+	columns, err := exp.searchColumns(table)
+	if err != nil {
+		return "", nil, "", nil, err
+	}
+
+	if len(columns) == 0 {
+		return "0", nil, "1 = 0", nil, nil
+	}
+
+	escaped := escapeLike(q)
+
+	scores := make([]string, len(columns))
+	conditions := make([]string, len(columns))
+	for i, c := range columns {
+		column := exp.quoteColumn(table, c)
+		scores[i] = fmt.Sprintf("CASE WHEN %s = ? THEN 3 WHEN %s LIKE ? THEN 2 WHEN %s LIKE ? THEN 1 ELSE 0 END", column, column, column)
+		scoreArgs = append(scoreArgs, q, escaped+"%", "%"+escaped+"%")
+
+		conditions[i] = fmt.Sprintf("%s LIKE ?", column)
+		conditionArgs = append(conditionArgs, "%"+escaped+"%")
+	}
+
+	score = fmt.Sprintf("(%s) / %de0", strings.Join(scores, " + "), 3*len(columns))
+	condition = "(" + strings.Join(conditions, " OR ") + ")"
+
+	return score, scoreArgs, condition, conditionArgs, nil
+}
+
+// buildListQuery returns the SELECT statement for a list request with its arguments.
+func (exp Explorer) buildListQuery(table string, listQuery ListQuery) (string, []any, error) {
+	columns := listQuery.Fields
+	for _, field := range listQuery.Keyset {
+		columns = withColumns(columns, field.Column)
+	}
+
+	selectList := exp.selectColumns(table, columns)
+	where := make([]string, 0)
+	args := make([]any, 0)
+	whereArgs := make([]any, 0)
+
+	if listQuery.Search != "" {
+		score, scoreArgs, condition, conditionArgs, err := exp.searchClause(table, listQuery.Search)
+		if err != nil {
+			return "", nil, err
+		}
+
+		selectList += ", " + score + " AS " + exp.dialect.QuoteIdent(scoreColumn)
+		args = append(args, scoreArgs...)
+		where = append(where, condition)
+		whereArgs = append(whereArgs, conditionArgs...)
+	}
+
+	for _, f := range listQuery.Filters {
+		condition, conditionArgs, err := exp.filterCondition(table, f)
+		if err != nil {
+			return "", nil, err
+		}
+
+		where = append(where, condition)
+		whereArgs = append(whereArgs, conditionArgs...)
+	}
+
+	if listQuery.Expr != nil {
+		condition, conditionArgs, err := exp.exprCondition(table, *listQuery.Expr)
+		if err != nil {
+			return "", nil, err
+		}
+
+		where = append(where, condition)
+		whereArgs = append(whereArgs, conditionArgs...)
+	}
+
+	if len(listQuery.After) > 0 {
+		condition, conditionArgs := exp.keysetCondition(table, listQuery)
+		where = append(where, condition)
+		whereArgs = append(whereArgs, conditionArgs...)
+	}
+
+	from, err := exp.fromClause(table, listQuery)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", selectList, from)
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+		args = append(args, whereArgs...)
+	}
+
+	sort := listQuery.Sort
+	if len(listQuery.Keyset) > 0 {
+		sort = listQuery.Keyset
+	}
+
+	orderBy, err := exp.orderBy(table, sort)
+	if err != nil {
+		return "", nil, err
+	}
+	query += orderBy
+
+	query += " LIMIT ? OFFSET ?"
+	args = append(args, listQuery.Pagination.Limit, listQuery.Pagination.Offset)
+
+	return query, args, nil
+}
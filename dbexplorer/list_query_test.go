@@ -0,0 +1,30 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchClause(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"users": {PrimaryKey: "id", Columns: []ColumnInfo{
+			{Name: "id", DataType: "int"},
+			{Name: "name", DataType: "varchar"},
+			{Name: "bio", DataType: "text"},
+			{Name: "born", DataType: "date"},
+		}},
+		"counters": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "int"}}},
+	})
+
+	_, _, condition, args, err := exp.searchClause("users", `50%_off\`)
+	if err != nil || condition != "(`name` LIKE ? OR `bio` LIKE ?)" ||
+		!reflect.DeepEqual(args, []any{`%50\%\_off\\%`, `%50\%\_off\\%`}) {
+		t.Errorf("unexpected condition %q %v %v", condition, args, err)
+	}
+
+	// without text columns nothing matches
+	_, _, condition, args, err = exp.searchClause("counters", "smith")
+	if err != nil || condition != "1 = 0" || len(args) != 0 {
+		t.Errorf("unexpected condition %q %v %v", condition, args, err)
+	}
+}
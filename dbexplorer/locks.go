@@ -0,0 +1,256 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	locksTable = metaTablePrefix + "locks"
+	// lockTokenHeader carries the token of the lock held on the record written.
+	lockTokenHeader = "X-Lock-Token"
+)
+
+// RecordLock is an advisory lock of a record, the token is only returned to the one who took it.
+type RecordLock struct {
+	Token     string `json:"token,omitempty"`
+	Owner     string `json:"owner"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type GetLockResponse struct {
+	Locked bool        `json:"locked"`
+	Lock   *RecordLock `json:"lock,omitempty"`
+}
+
+type DeleteLockResponse struct {
+	Released int `json:"released"`
+}
+
+// WithRecordLocks enables POST /table/id/_lock to take a lock of the record for ttl, e.g. while a user
+// edits it. Updates and deletes of a locked record, including bulk writes, /_tx, GraphQL mutations, undo
+// and merge, need the token of the lock in the X-Lock-Token header and fail with 423 otherwise. The lock is renewed by taking it again with the token, released
+// with DELETE /table/id/_lock or expires. The locks are stored in the _explorer_locks table.
+func WithRecordLocks(ttl time.Duration) Option {
+	return func(exp *Explorer) error {
+		if ttl <= 0 {
+			return fmt.Errorf("record locks: ttl must be positive")
+		}
+
+		exp.metaTables = append(exp.metaTables, metaTable{
+			Name: locksTable,
+			Columns: []metaColumn{
+				{Name: "id", Kind: "serial"},
+				{Name: "table_name", Kind: "string"},
+				{Name: "record_id", Kind: "string"},
+				{Name: "token", Kind: "string"},
+				{Name: "owner", Kind: "string"},
+				{Name: "expires_at", Kind: "time"},
+			},
+			PrimaryKey: "id",
+			Unique:     [][]string{{"table_name", "record_id"}},
+		})
+		exp.lockTTL = ttl
+
+		return nil
+	}
+}
+
+// lockOwner is the principal taking a lock, "" without authentication.
+func lockOwner(r *http.Request) string {
+	if principal := PrincipalFromContext(r.Context()); principal != nil {
+		return principal.Name
+	}
+
+	return ""
+}
+
+// lockedRecord returns the table and the id of the record a _lock request is about.
+func (exp Explorer) lockedRecord(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return "", "", false
+	}
+
+	return tableName, exp.getId(r.URL.Path), true
+}
+
+// currentLock returns the unexpired lock of the record or nil.
+func (exp Explorer) currentLock(q queryer, table string, id string) (*RecordLock, error) {
+	var lock RecordLock
+	err := q.QueryRow(`SELECT token, owner, expires_at FROM `+locksTable+` WHERE table_name = ? AND record_id = ? AND expires_at > ?`,
+		table, id, time.Now().UTC()).Scan(&lock.Token, &lock.Owner, &lock.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &lock, nil
+}
+
+func isDuplicateKey(err error) bool {
+	code := sqlErrorCode(err)
+	return code == "1062" || code == "23505"
+}
+
+func errLocked(lock *RecordLock) error {
+	return statusError{
+		Status: http.StatusLocked,
+		Err:    fmt.Errorf("record is locked by %s until %s", lock.Owner, lock.ExpiresAt),
+	}
+}
+
+// checkRecordLock lets updates and deletes of a locked record through only with the token of its lock.
+// executeWrite calls it, so every write path of a single record is covered.
+func (exp Explorer) checkRecordLock(q queryer, op writeOp) error {
+	if exp.lockTTL == 0 || op.Op == writeCreate {
+		return nil
+	}
+
+	lock, err := exp.currentLock(q, op.Table, fmt.Sprint(op.ID))
+	if err != nil || lock == nil {
+		return err
+	}
+
+	if op.LockToken != lock.Token {
+		return errLocked(lock)
+	}
+
+	return nil
+}
+
+// acquireLock takes the lock of the record, or renews it when the request has its token.
+func (exp Explorer) acquireLock(r *http.Request, table string, id string) (*RecordLock, error) {
+	tx, err := exp.db().Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(exp.lockTTL)
+	lock := &RecordLock{Owner: lockOwner(r), ExpiresAt: expiresAt.Format(time.RFC3339)}
+
+	if _, err := tx.Exec(`DELETE FROM `+locksTable+` WHERE table_name = ? AND record_id = ? AND expires_at <= ?`, table, id, now); err != nil {
+		return nil, err
+	}
+
+	if token := r.Header.Get(lockTokenHeader); token != "" {
+		result, err := tx.Exec(`UPDATE `+locksTable+` SET expires_at = ? WHERE table_name = ? AND record_id = ? AND token = ?`, expiresAt, table, id, token)
+		if err != nil {
+			return nil, err
+		}
+
+		if renewed, err := result.RowsAffected(); err != nil {
+			return nil, err
+		} else if renewed > 0 {
+			lock.Token = token
+			return lock, tx.Commit()
+		}
+	}
+
+	lock.Token, err = randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO `+locksTable+` (table_name, record_id, token, owner, expires_at) VALUES (?, ?, ?, ?, ?)`,
+		table, id, lock.Token, lock.Owner, expiresAt)
+	if isDuplicateKey(err) {
+		held, err := exp.currentLock(exp.db(), table, id)
+		if err != nil {
+			return nil, err
+		}
+		if held == nil {
+			return nil, statusError{Status: http.StatusConflict, Err: fmt.Errorf("record is being locked, retry")}
+		}
+		return nil, errLocked(held)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return lock, tx.Commit()
+}
+
+func (exp Explorer) handlerLockItem(w http.ResponseWriter, r *http.Request) {
+	tableName, id, ok := exp.lockedRecord(w, r)
+	if !ok {
+		return
+	}
+
+	exists, err := exp.itemExists(r.Context(), exp.db(), tableName, id)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	lock, err := exp.acquireLock(r, tableName, id)
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	writeResponse(w, lock)
+}
+
+func (exp Explorer) handlerGetLock(w http.ResponseWriter, r *http.Request) {
+	tableName, id, ok := exp.lockedRecord(w, r)
+	if !ok {
+		return
+	}
+
+	lock, err := exp.currentLock(exp.db(), tableName, id)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	res := GetLockResponse{Locked: lock != nil}
+	if lock != nil {
+		if r.Header.Get(lockTokenHeader) != lock.Token {
+			lock.Token = ""
+		}
+		res.Lock = lock
+	}
+
+	writeResponse(w, res)
+}
+
+func (exp Explorer) handlerUnlockItem(w http.ResponseWriter, r *http.Request) {
+	tableName, id, ok := exp.lockedRecord(w, r)
+	if !ok {
+		return
+	}
+
+	token := strings.TrimSpace(r.Header.Get(lockTokenHeader))
+	if token == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%s header is required", lockTokenHeader))
+		return
+	}
+
+	result, err := exp.db().Exec(`DELETE FROM `+locksTable+` WHERE table_name = ? AND record_id = ? AND token = ?`, tableName, id, token)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	released, err := result.RowsAffected()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, DeleteLockResponse{Released: int(released)})
+}
@@ -0,0 +1,86 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordLocks(t *testing.T) {
+	var exp Explorer
+	if err := WithRecordLocks(0)(&exp); err == nil {
+		t.Errorf("expected error for a zero ttl")
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/items/1", nil)
+	if err := exp.checkRecordLock(nil, writeOp{Op: writeUpdate, Table: "items", ID: 1}); err != nil {
+		t.Errorf("expected writes to pass without locks, got %v", err)
+	}
+
+	if err := WithRecordLocks(5 * time.Minute)(&exp); err != nil {
+		t.Fatal(err)
+	}
+	if exp.lockTTL != 5*time.Minute || len(exp.metaTables) != 1 || exp.metaTables[0].Name != locksTable {
+		t.Errorf("expected the locks table to be registered, got %+v", exp.metaTables)
+	}
+
+	w := httptest.NewRecorder()
+	writeQueryError(w, r, errLocked(&RecordLock{Owner: "alice", ExpiresAt: "2026-10-15T12:00:00Z"}))
+	expected := `{"error":"record is locked by alice until 2026-10-15T12:00:00Z"}`
+	if w.Code != http.StatusLocked || w.Body.String() != expected {
+		t.Errorf("expected 423 %s, got %d %s", expected, w.Code, w.Body.String())
+	}
+}
+
+// TestRecordLocksWritePaths needs the database of TestApis and checks that a locked record can't be
+// changed around the single record handlers.
+func TestRecordLocksWritePaths(t *testing.T) {
+	handler, err := New(openTestDB(t, locksTable), WithRecordLocks(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method string, path string, body string, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if token != "" {
+			r.Header.Set(lockTokenHeader, token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodPost, "/items/1/_lock", "", "")
+	var resp struct {
+		Response RecordLock `json:"response"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Response.Token == "" {
+		t.Fatalf("expected a lock, got %d %s", w.Code, w.Body.String())
+	}
+	token := resp.Response.Token
+
+	cases := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    string
+		expected int
+	}{
+		{"tx update", http.MethodPost, "/_tx", `{"operations":[{"op":"update","table":"items","id":1,"record":{"title":"tx"}}]}`, "", http.StatusLocked},
+		{"tx delete", http.MethodPost, "/_tx", `{"operations":[{"op":"delete","table":"items","id":1}]}`, "", http.StatusLocked},
+		{"bulk update", http.MethodPost, "/items", `{"set":{"title":"bulk"},"where":{"id__in":[1,2]}}`, "", http.StatusLocked},
+		{"bulk delete", http.MethodDelete, "/items?ids=1,2", "", "", http.StatusLocked},
+		{"tx update with token", http.MethodPost, "/_tx", `{"operations":[{"op":"update","table":"items","id":1,"record":{"title":"tx"}}]}`, token, http.StatusOK},
+		{"bulk update with token", http.MethodPost, "/items", `{"set":{"title":"bulk"},"where":{"id__in":[1,2]}}`, token, http.StatusOK},
+	}
+
+	for _, c := range cases {
+		if w := do(c.method, c.path, c.body, c.token); w.Code != c.expected {
+			t.Errorf("%s: expected %d, got %d %s", c.name, c.expected, w.Code, w.Body.String())
+		}
+	}
+}
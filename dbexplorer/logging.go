@@ -0,0 +1,113 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// RequestLog is the structured log entry of a request.
+type RequestLog struct {
+	Time         time.Time `json:"time"`
+	Method       string    `json:"method"`
+	Path         string    `json:"path"`
+	Table        string    `json:"table"`
+	Status       int       `json:"status"`
+	DurationMs   float64   `json:"duration_ms"`
+	RowsScanned  int64     `json:"rows_scanned"`
+	RowsAffected int64     `json:"rows_affected"`
+	Principal    string    `json:"principal,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	// SQLError is the error number of MySQL or the SQLSTATE of drivers reporting one.
+	SQLError string `json:"sql_error,omitempty"`
+}
+
+// RequestLogger receives a RequestLog after every request, e.g. NewJSONLogger or an adapter to the
+// logger of the application.
+type RequestLogger interface {
+	LogRequest(entry RequestLog)
+}
+
+type jsonLogger struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewJSONLogger writes every entry as a line of JSON to out.
+func NewJSONLogger(out io.Writer) RequestLogger {
+	return &jsonLogger{out: out}
+}
+
+func (l *jsonLogger) LogRequest(entry RequestLog) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.out.Write(append(data, '\n'))
+}
+
+// WithRequestLogger logs every request with its outcome to logger.
+func WithRequestLogger(logger RequestLogger) Option {
+	return func(exp *Explorer) error {
+		exp.requestLogger = logger
+		return nil
+	}
+}
+
+func sqlErrorCode(err error) string {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return strconv.Itoa(int(mysqlErr.Number))
+	}
+
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState()
+	}
+
+	return ""
+}
+
+func (exp Explorer) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r, stats := withRequestStats(r)
+		r, holder := withRequestError(r)
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r)
+
+		entry := RequestLog{
+			Time:         start,
+			Method:       r.Method,
+			Path:         r.URL.Path,
+			Table:        exp.metricsTable(r),
+			Status:       recorder.status,
+			DurationMs:   float64(time.Since(start).Microseconds()) / 1000,
+			RowsScanned:  stats.rowsScanned.Load(),
+			RowsAffected: stats.rowsAffected.Load(),
+		}
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+		if holder.principal != nil {
+			entry.Principal = holder.principal.Name
+		}
+		if holder.err != nil {
+			entry.Error = holder.err.Error()
+			entry.SQLError = sqlErrorCode(holder.err)
+		}
+
+		exp.requestLogger.LogRequest(entry)
+	})
+}
@@ -0,0 +1,36 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	var out bytes.Buffer
+	exp := Explorer{
+		schema:        newSchemaCache([]string{"items"}, nil),
+		requestLogger: NewJSONLogger(&out),
+	}
+
+	handler := exp.loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addRowsAffected(r.Context(), 2)
+		writeInternalError(w, r, fmt.Errorf("update: %w", &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items/1", nil))
+
+	var entry RequestLog
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log line %q: %v", out.String(), err)
+	}
+
+	if entry.Method != http.MethodPost || entry.Table != "items" || entry.Status != http.StatusInternalServerError ||
+		entry.RowsAffected != 2 || entry.SQLError != "1213" {
+		t.Errorf("unexpected log entry %+v", entry)
+	}
+}
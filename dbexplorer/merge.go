@@ -0,0 +1,178 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+type MergeRequest struct {
+	Keep   any `json:"keep"`
+	Remove any `json:"remove"`
+}
+
+type MergeResponse struct {
+	Kept       any              `json:"kept"`
+	Removed    int64            `json:"removed"`
+	Reassigned map[string]int64 `json:"reassigned"`
+}
+
+// foreignKeyRef is a foreign key of Table.
+type foreignKeyRef struct {
+	Table      string
+	ForeignKey ForeignKey
+}
+
+// referencingKeys returns the foreign keys of exposed tables pointing at the column of the table.
+func (exp Explorer) referencingKeys(table string, column string) []foreignKeyRef {
+	schemas := exp.tableSchemas()
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	refs := make([]foreignKeyRef, 0)
+	for _, name := range names {
+		for _, fk := range schemas[name].ForeignKeys {
+			if fk.RefTable == table && fk.RefColumn == column {
+				refs = append(refs, foreignKeyRef{Table: name, ForeignKey: fk})
+			}
+		}
+	}
+
+	return refs
+}
+
+// handlerMerge repoints the rows referencing the removed record to the kept one and deletes
+// the removed record in one transaction. Only references from exposed tables are known,
+// others make the delete fail and nothing is changed.
+func (exp Explorer) handlerMerge(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("merge is not allowed for writes requiring approval"))
+		return
+	}
+
+	var req MergeRequest
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Keep == nil || req.Remove == nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("keep and remove are required"))
+		return
+	}
+
+	keep, err := exp.primaryKeyValue(tableName, fmt.Sprint(req.Keep))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	remove, err := exp.primaryKeyValue(tableName, fmt.Sprint(req.Remove))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if keep == remove {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("keep and remove must differ"))
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	refs := exp.referencingKeys(tableName, primaryKey)
+	for _, ref := range refs {
+		if err := exp.authorize(r.Context(), principal, Action{Table: ref.Table, Op: OpWrite}); err != nil {
+			exp.writeForbidden(w, r, err)
+			return
+		}
+
+		writable := exp.columnWritable(r.Context(), ref.Table, principal)
+		if writable != nil && !writable(ref.ForeignKey.Column) {
+			exp.writeForbidden(w, r, NewColumnPermissionError(ref.ForeignKey.Column))
+			return
+		}
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	for _, id := range []any{keep, remove} {
+		var found any
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ? FOR UPDATE", exp.quoteColumn(tableName, primaryKey), exp.quote(tableName), exp.quoteColumn(tableName, primaryKey))
+		if err := tx.QueryRow(query, id).Scan(&found); err != nil {
+			writeError(w, http.StatusNotFound, fmt.Errorf("record %v not found", id))
+			return
+		}
+	}
+
+	result := MergeResponse{
+		Kept:       keep,
+		Reassigned: make(map[string]int64),
+	}
+
+	for _, ref := range refs {
+		column := exp.quoteColumn(ref.Table, ref.ForeignKey.Column)
+		query := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", exp.quote(ref.Table), column, column)
+		res, err := tx.Exec(query, keep, remove)
+		if err != nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("reassign %s.%s failed", ref.Table, ref.ForeignKey.Column))
+			return
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		result.Reassigned[ref.Table+"."+ref.ForeignKey.Column] = affected
+		exp.countAffected(ref.Table, writeUpdate, affected)
+	}
+
+	op := writeOp{
+		Op:         writeDelete,
+		Table:      tableName,
+		PrimaryKey: primaryKey,
+		ID:         remove,
+		LockToken:  r.Header.Get(lockTokenHeader),
+	}
+
+	written, err := exp.executeWrite(r.Context(), tx, op, auditMeta{Principal: principal})
+	if err != nil {
+		if !writeSQLError(w, r, err) {
+			writeError(w, http.StatusConflict, fmt.Errorf("delete of %v failed", remove))
+		}
+		return
+	}
+	result.Removed = written.Affected
+
+	if err := tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	writeResponse(w, result)
+}
@@ -0,0 +1,102 @@
+package dbexplorer
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const requestIDHeader = "X-Request-Id"
+
+// ResponseMeta is added to responses as "_meta" when the request asks for it with ?meta=true.
+type ResponseMeta struct {
+	DurationMs  float64 `json:"duration_ms"`
+	RowsScanned int     `json:"rows_scanned"`
+	CacheHit    bool    `json:"cache_hit"`
+	RequestID   string  `json:"request_id"`
+
+	start time.Time
+	stats *requestStats
+}
+
+type statsContextKey struct{}
+
+// requestStats counts the rows a request read and changed for the _meta block and the request log.
+type requestStats struct {
+	rowsScanned  atomic.Int64
+	rowsAffected atomic.Int64
+}
+
+// withRequestStats returns the stats of the request, adding them to its context on first use.
+func withRequestStats(r *http.Request) (*http.Request, *requestStats) {
+	if stats, ok := r.Context().Value(statsContextKey{}).(*requestStats); ok {
+		return r, stats
+	}
+
+	stats := &requestStats{}
+	return r.WithContext(context.WithValue(r.Context(), statsContextKey{}, stats)), stats
+}
+
+// metaWriter carries the metadata of the request to writeResponse and writeError.
+type metaWriter struct {
+	http.ResponseWriter
+	meta *ResponseMeta
+}
+
+func (w *metaWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *metaWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func responseMeta(w http.ResponseWriter) *ResponseMeta {
+	for {
+		switch v := w.(type) {
+		case *metaWriter:
+			v.meta.DurationMs = float64(time.Since(v.meta.start).Microseconds()) / 1000
+			v.meta.RowsScanned = int(v.meta.stats.rowsScanned.Load())
+			return v.meta
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// addRowsScanned counts rows read from the database for the request, if anything collects its stats.
+func addRowsScanned(ctx context.Context, n int) {
+	if stats, ok := ctx.Value(statsContextKey{}).(*requestStats); ok {
+		stats.rowsScanned.Add(int64(n))
+	}
+}
+
+// addRowsAffected counts rows changed by the request, if anything collects its stats.
+func addRowsAffected(ctx context.Context, n int64) {
+	if stats, ok := ctx.Value(statsContextKey{}).(*requestStats); ok {
+		stats.rowsAffected.Add(n)
+	}
+}
+
+func metaMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("meta") != "true" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r, stats := withRequestStats(r)
+		meta := &ResponseMeta{RequestID: r.Header.Get(requestIDHeader), start: time.Now(), stats: stats}
+		if meta.RequestID == "" {
+			meta.RequestID, _ = randomToken()
+		}
+		w.Header().Set(requestIDHeader, meta.RequestID)
+
+		next.ServeHTTP(&metaWriter{ResponseWriter: w, meta: meta}, r)
+	})
+}
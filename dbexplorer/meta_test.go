@@ -0,0 +1,37 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetaMiddleware(t *testing.T) {
+	handler := metaMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addRowsScanned(r.Context(), 3)
+		writeResponse(w, "ok")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items?meta=true", nil)
+	req.Header.Set(requestIDHeader, "abc")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var resp struct {
+		Response string        `json:"response"`
+		Meta     *ResponseMeta `json:"_meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Meta == nil || resp.Meta.RowsScanned != 3 || resp.Meta.RequestID != "abc" || rec.Header().Get(requestIDHeader) != "abc" {
+		t.Errorf("unexpected response %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if rec.Body.String() != `{"response":"ok"}` {
+		t.Errorf("expected no _meta, got %s", rec.Body.String())
+	}
+}
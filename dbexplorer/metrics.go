@@ -0,0 +1,131 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// metrics is a minimal registry of counters exposed in the Prometheus text format on /_metrics.
+type metrics struct {
+	mu       sync.Mutex
+	counters map[string]float64
+	help     map[string]string
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		counters: make(map[string]float64),
+		help:     make(map[string]string),
+	}
+}
+
+// describe sets the help text of the metric name.
+func (m *metrics) describe(name string, help string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.help[name] = help
+}
+
+// add increases the counter of the metric with the labels given as name/value pairs.
+func (m *metrics) add(name string, value float64, labels ...string) {
+	key := name
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf("%s=%q", labels[i], labels[i+1]))
+		}
+		key += "{" + strings.Join(pairs, ",") + "}"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[key] += value
+}
+
+func (m *metrics) write(w http.ResponseWriter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.counters))
+	for key := range m.counters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	described := make(map[string]bool)
+	for _, key := range keys {
+		name, _, _ := strings.Cut(key, "{")
+		if !described[name] {
+			described[name] = true
+			if help, ok := m.help[name]; ok {
+				fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+			}
+			fmt.Fprintf(w, "# TYPE %s counter\n", name)
+		}
+
+		fmt.Fprintf(w, "%s %v\n", key, m.counters[key])
+	}
+}
+
+func (exp Explorer) initMetrics() {
+	exp.metrics.describe("db_explorer_request_bytes_total", "Bytes of request bodies by table.")
+	exp.metrics.describe("db_explorer_response_bytes_total", "Bytes of response bodies by table.")
+	exp.metrics.describe("db_explorer_rows_returned_total", "Rows read from the table and returned to clients.")
+	exp.metrics.describe("db_explorer_rows_affected_total", "Rows of the table changed by operation.")
+}
+
+// metricsTable is the table label of the request, requests not addressing a table are counted as _other.
+func (exp Explorer) metricsTable(r *http.Request) string {
+	table := strings.Split(r.URL.Path, "/")[1]
+	if exp.isValidTableName(table) {
+		return table
+	}
+
+	return "_other"
+}
+
+func (exp Explorer) countReturned(table string, rows int) {
+	exp.metrics.add("db_explorer_rows_returned_total", float64(rows), "table", table)
+}
+
+func (exp Explorer) countAffected(table string, op string, rows int64) {
+	exp.metrics.add("db_explorer_rows_affected_total", float64(rows), "table", table, "op", op)
+}
+
+// countingReader counts the bytes read from the request body.
+type countingReader struct {
+	io.ReadCloser
+	bytes int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// sizeMiddleware counts the bytes of request and response bodies per table.
+func (exp Explorer) sizeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := &countingReader{ReadCloser: r.Body}
+		r.Body = body
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r)
+
+		table := exp.metricsTable(r)
+		exp.metrics.add("db_explorer_request_bytes_total", float64(body.bytes), "table", table)
+		exp.metrics.add("db_explorer_response_bytes_total", float64(recorder.bytes), "table", table)
+	})
+}
+
+func (exp Explorer) handlerGetMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	exp.metrics.write(w)
+}
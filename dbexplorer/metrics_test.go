@@ -0,0 +1,57 @@
+package dbexplorer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsWrite(t *testing.T) {
+	m := newMetrics()
+	m.describe("db_explorer_purged_rows_total", "Rows deleted by retention rules.")
+	m.add("db_explorer_purged_rows_total", 5, "table", "logs")
+	m.add("db_explorer_purged_rows_total", 2, "table", "logs")
+	m.add("db_explorer_purged_rows_total", 1, "table", "events")
+
+	w := httptest.NewRecorder()
+	m.write(w)
+
+	expected := `# HELP db_explorer_purged_rows_total Rows deleted by retention rules.
+# TYPE db_explorer_purged_rows_total counter
+db_explorer_purged_rows_total{table="events"} 1
+db_explorer_purged_rows_total{table="logs"} 7
+`
+	if got := w.Body.String(); got != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestSizeMiddleware(t *testing.T) {
+	exp := Explorer{
+		schema:  newSchemaCache([]string{"items"}, nil),
+		metrics: newMetrics(),
+	}
+
+	handler := exp.sizeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		w.Write([]byte(`{"response":{}}`))
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/items/", strings.NewReader(`{"title":"a"}`)))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	w := httptest.NewRecorder()
+	exp.metrics.write(w)
+
+	for _, expected := range []string{
+		`db_explorer_request_bytes_total{table="items"} 13`,
+		`db_explorer_response_bytes_total{table="items"} 15`,
+		`db_explorer_response_bytes_total{table="_other"} 15`,
+	} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("expected %s in\n%s", expected, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type GetMinMaxResponse struct {
+	Min any `json:"min"`
+	Max any `json:"max"`
+}
+
+// buildMinMaxQuery selects the range of the column over the rows matching the filters of the list endpoint.
+func (exp Explorer) buildMinMaxQuery(table string, column string, query url.Values) (string, []any, error) {
+	if !exp.isValidColumnName(table, column) {
+		return "", nil, fmt.Errorf("unknown column %s", column)
+	}
+
+	where, args, err := exp.listWhere(table, query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sqlQuery := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", exp.quoteColumn(table, column), exp.quoteColumn(table, column), exp.quote(table))
+	if where != "" {
+		sqlQuery += " WHERE " + where
+	}
+
+	return sqlQuery, args, nil
+}
+
+// listWhere returns the condition of the column filters and ?filter= of the list endpoint, "" for none.
+func (exp Explorer) listWhere(table string, query url.Values) (string, []any, error) {
+	filters, err := exp.parseFilters(table, query)
+	if err != nil {
+		return "", nil, err
+	}
+
+	where, args, err := exp.filterWhere(table, filters)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if query.Has("filter") {
+		expr, err := exp.parseFilterExpr(table, query.Get("filter"))
+		if err != nil {
+			return "", nil, err
+		}
+
+		condition, conditionArgs, err := exp.exprCondition(table, *expr)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if where != "" {
+			where += " AND "
+		}
+		where += condition
+		args = append(args, conditionArgs...)
+	}
+
+	return where, args, nil
+}
+
+func (exp Explorer) handlerGetMinMax(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if !exp.requireAggregate(w, r) {
+		return
+	}
+
+	query, args, err := exp.buildMinMaxQuery(tableName, strings.Split(r.URL.Path, "/")[2], r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	rows, release, err := exp.queryContext(r.Context(), query, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer release()
+	defer rows.Close()
+
+	var res GetMinMaxResponse
+	if rows.Next() {
+		if err := rows.Scan(&res.Min, &res.Max); err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+	}
+	if err := rows.Err(); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	res.Min = normalizeValue(res.Min)
+	res.Max = normalizeValue(res.Max)
+
+	writeResponse(w, res)
+}
@@ -0,0 +1,75 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBuildMinMaxQuery(t *testing.T) {
+	exp := newItemsExplorer()
+
+	query, args, err := exp.buildMinMaxQuery("items", "id", url.Values{})
+	if err != nil || query != "SELECT MIN(`id`), MAX(`id`) FROM `items`" || len(args) != 0 {
+		t.Errorf("unexpected query %q %v %v", query, args, err)
+	}
+
+	query, args, err = exp.buildMinMaxQuery("items", "id", url.Values{"status": {"new"}, "filter": {"id=1 OR id=2"}})
+	if err != nil || query != "SELECT MIN(`id`), MAX(`id`) FROM `items` WHERE `status` = ? AND (`id` = ? OR `id` = ?)" ||
+		!reflect.DeepEqual(args, []any{"new", "1", "2"}) {
+		t.Errorf("unexpected filtered query %q %v %v", query, args, err)
+	}
+
+	if _, _, err := exp.buildMinMaxQuery("items", "missing", url.Values{}); err == nil {
+		t.Errorf("expected error for an unknown column")
+	}
+}
+
+func TestGetMinMaxHandler(t *testing.T) {
+	exp := newItemsExplorer()
+	exp.initRoutes()
+
+	cases := []struct {
+		path     string
+		expected int
+	}{
+		{"/missing/id/_minmax", http.StatusNotFound},
+		{"/items/missing/_minmax", http.StatusBadRequest},
+		{"/items/id/_minmax?missing=1", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		if w := serveRequest(exp.router, http.MethodGet, c.path, ""); w.Code != c.expected {
+			t.Errorf("%s: expected %d, got %d %s", c.path, c.expected, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestGetMinMaxHandlerDB(t *testing.T) {
+	handler, err := New(openTestDB(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string][2]string{
+		"/items/id/_minmax":                {"1", "2"},
+		"/items/title/_minmax":             {"database/sql", "memcache"},
+		"/items/id/_minmax?title=memcache": {"2", "2"},
+	}
+
+	for path, expected := range cases {
+		w := serveRequest(handler, http.MethodGet, path, "")
+
+		// MySQL returns the values as text or typed depending on the query protocol
+		var resp struct {
+			Response GetMinMaxResponse `json:"response"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		if got := [2]string{fmt.Sprint(resp.Response.Min), fmt.Sprint(resp.Response.Max)}; w.Code != http.StatusOK || got != expected {
+			t.Errorf("%s: expected %v, got %d %s", path, expected, w.Code, w.Body.String())
+		}
+	}
+}
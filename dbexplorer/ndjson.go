@@ -0,0 +1,86 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// wantsNDJSON reports whether the records are requested as newline delimited JSON by ?format=ndjson or Accept.
+func wantsNDJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "ndjson" {
+		return true
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == ndjsonContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// streamTableItems calls fn with the records of the list query as they are read from the database.
+func (exp Explorer) streamTableItems(ctx context.Context, table string, listQuery ListQuery, fn func(item map[string]any) error) error {
+	query, args, err := exp.planListQuery(ctx, table, listQuery)
+	if err != nil {
+		return err
+	}
+
+	rows, release, err := exp.queryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	defer release()
+	defer rows.Close()
+
+	count := 0
+	defer func() {
+		exp.countReturned(table, count)
+		addRowsScanned(ctx, count)
+	}()
+
+	return exp.eachRow(rows, func(item map[string]any) error {
+		exp.renderColumns(table, item)
+		exp.pickFields(table, listQuery.Fields, item)
+		count++
+		return fn(item)
+	})
+}
+
+// writeTableItemsNDJSON writes a record per line and flushes it right away, so the table is never held in memory.
+// Without a limit all records are written. Errors after the first record can only cut the stream short.
+func (exp Explorer) writeTableItemsNDJSON(w http.ResponseWriter, r *http.Request, table string, listQuery ListQuery) {
+	controller := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	started := false
+
+	err := exp.streamTableItems(r.Context(), table, listQuery, func(item map[string]any) error {
+		if !started {
+			w.Header().Set("Content-Type", ndjsonContentType)
+			started = true
+		}
+
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+
+		controller.Flush()
+		return nil
+	})
+	if err != nil && !started {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	if !started {
+		w.Header().Set("Content-Type", ndjsonContentType)
+	}
+}
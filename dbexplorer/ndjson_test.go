@@ -0,0 +1,31 @@
+package dbexplorer
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWantsNDJSON(t *testing.T) {
+	cases := []struct {
+		url    string
+		accept string
+		ndjson bool
+	}{
+		{"/items", "", false},
+		{"/items", "application/json", false},
+		{"/items?format=ndjson", "", true},
+		{"/items", "application/x-ndjson", true},
+		{"/items", "application/json;q=0.5, application/x-ndjson", true},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", c.url, nil)
+		if c.accept != "" {
+			r.Header.Set("Accept", c.accept)
+		}
+
+		if got := wantsNDJSON(r); got != c.ndjson {
+			t.Errorf("%s with Accept %q: expected %v, got %v", c.url, c.accept, c.ndjson, got)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const opaTimeout = 5 * time.Second
+
+// OPAAuthorizer asks an Open Policy Agent sidecar for every authorization decision through its data API,
+// use its Authorize method with WithAuthorizer. The policy gets the input
+//
+//	{"principal": {"name": ..., "roles": [...], "scopes": [...]}, "table": ..., "op": "read", "method": "GET", "columns": [...]}
+//
+// and allows the action with a true result or {"allow": true}, {"allow": false, "reason": ...} explains a denial.
+// An undefined decision or an unreachable agent denies the action.
+type OPAAuthorizer struct {
+	Client *http.Client
+
+	endpoint string
+}
+
+type opaInput struct {
+	Principal *opaPrincipal `json:"principal"`
+	Table     string        `json:"table"`
+	Op        string        `json:"op"`
+	Method    string        `json:"method,omitempty"`
+	Columns   []string      `json:"columns,omitempty"`
+}
+
+type opaPrincipal struct {
+	Name   string   `json:"name"`
+	Roles  []string `json:"roles"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+type opaDecision struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason"`
+}
+
+// NewOPAAuthorizer evaluates the decision at the URL of the data API, e.g. http://localhost:8181/v1/data/dbexplorer/authz.
+func NewOPAAuthorizer(decisionURL string) (*OPAAuthorizer, error) {
+	u, err := url.Parse(decisionURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid opa url: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid opa url: expected http(s)://host/v1/data/path")
+	}
+
+	return &OPAAuthorizer{
+		Client:   &http.Client{Timeout: opaTimeout},
+		endpoint: u.String(),
+	}, nil
+}
+
+// Authorize is the Authorizer querying the agent.
+func (o *OPAAuthorizer) Authorize(ctx context.Context, principal *Principal, action Action) error {
+	input := opaInput{
+		Table:   action.Table,
+		Op:      action.Op,
+		Method:  action.Method,
+		Columns: action.Columns,
+	}
+	if principal != nil {
+		input.Principal = &opaPrincipal{Name: principal.Name, Roles: principal.Roles, Scopes: principal.Scopes}
+	}
+
+	body, err := json.Marshal(map[string]any{"input": input})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("policy unavailable")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("policy unavailable")
+	}
+
+	var result struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("policy unavailable")
+	}
+
+	decision, err := parseOPADecision(result.Result)
+	if err != nil {
+		return err
+	}
+
+	if decision.Allow {
+		return nil
+	}
+	if decision.Reason != "" {
+		return fmt.Errorf("%s", decision.Reason)
+	}
+
+	return fmt.Errorf("%s of %s denied by policy", action.Op, action.Table)
+}
+
+// parseOPADecision reads a boolean or {"allow", "reason"} result, no result means the rule is undefined.
+func parseOPADecision(result json.RawMessage) (opaDecision, error) {
+	var decision opaDecision
+	if len(result) == 0 {
+		return decision, nil
+	}
+
+	if err := json.Unmarshal(result, &decision.Allow); err == nil {
+		return decision, nil
+	}
+
+	if err := json.Unmarshal(result, &decision); err != nil {
+		return decision, fmt.Errorf("unexpected policy result %s", result)
+	}
+
+	return decision, nil
+}
@@ -0,0 +1,70 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOPAAuthorizer(t *testing.T) {
+	var input opaInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input opaInput `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		input = body.Input
+
+		switch {
+		case input.Table == "missing":
+			w.Write([]byte(`{}`))
+		case input.Op == OpRead:
+			w.Write([]byte(`{"result": true}`))
+		case input.Principal != nil && input.Principal.Name == "admin":
+			w.Write([]byte(`{"result": {"allow": true}}`))
+		default:
+			w.Write([]byte(`{"result": {"allow": false, "reason": "writes need admin"}}`))
+		}
+	}))
+	defer server.Close()
+
+	opa, err := NewOPAAuthorizer(server.URL + "/v1/data/dbexplorer/authz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := opa.Authorize(ctx, nil, Action{Table: "orders", Op: OpRead, Method: http.MethodGet}); err != nil {
+		t.Errorf("expected read to be allowed, got %v", err)
+	}
+
+	admin := &Principal{Name: "admin", Roles: []string{"admin"}}
+	if err := opa.Authorize(ctx, admin, Action{Table: "orders", Op: OpWrite, Columns: []string{"total"}}); err != nil {
+		t.Errorf("expected admin write to be allowed, got %v", err)
+	}
+	if input.Principal.Roles[0] != "admin" || input.Columns[0] != "total" {
+		t.Errorf("unexpected input %+v", input)
+	}
+
+	err = opa.Authorize(ctx, &Principal{Name: "bob"}, Action{Table: "orders", Op: OpWrite})
+	if err == nil || err.Error() != "writes need admin" {
+		t.Errorf("expected the reason of the denial, got %v", err)
+	}
+
+	if err := opa.Authorize(ctx, admin, Action{Table: "missing", Op: OpRead}); err == nil {
+		t.Errorf("expected an undefined decision to deny")
+	}
+
+	server.Close()
+	if err := opa.Authorize(ctx, admin, Action{Table: "orders", Op: OpRead}); err == nil {
+		t.Errorf("expected an unreachable agent to deny")
+	}
+
+	if _, err := NewOPAAuthorizer("localhost:8181"); err == nil {
+		t.Errorf("expected error for a url without scheme")
+	}
+}
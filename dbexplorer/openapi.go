@@ -0,0 +1,286 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// openAPIObject is a node of the OpenAPI document.
+type openAPIObject = map[string]any
+
+// WithSwaggerUI serves a Swagger UI page for /_openapi.json at GET /_docs. The UI assets are loaded from unpkg.
+func WithSwaggerUI() Option {
+	return func(exp *Explorer) error {
+		exp.swaggerUI = true
+		return nil
+	}
+}
+
+// openAPIColumnSchema maps the column type like graphqlScalar does.
+func (exp Explorer) openAPIColumnSchema(column ColumnInfo) openAPIObject {
+	schema := openAPIObject{}
+
+	switch {
+	case exp.dialect.IsBoolean(column):
+		schema["type"] = "boolean"
+	case isJSONDataType(column.DataType):
+	case exp.numbersAsStrings && isPreciseType(strings.ToUpper(column.DataType)):
+		schema["type"] = "string"
+	case isIntegerDataType(column.DataType):
+		schema["type"] = "integer"
+		if column.DataType == "bigint" {
+			schema["format"] = "int64"
+		}
+	case isNumericDataType(column.DataType):
+		schema["type"] = "number"
+	case column.DataType == "date":
+		schema["type"] = "string"
+		schema["format"] = "date"
+	case isTimeDataType(column.DataType):
+		schema["type"] = "string"
+		schema["format"] = "date-time"
+	default:
+		schema["type"] = "string"
+		if column.MaxLength != nil {
+			schema["maxLength"] = *column.MaxLength
+		}
+	}
+
+	if len(column.EnumValues) > 0 {
+		schema["enum"] = column.EnumValues
+	}
+	if column.Nullable {
+		schema["nullable"] = true
+	}
+	if column.Comment != "" {
+		schema["description"] = column.Comment
+	}
+
+	return schema
+}
+
+func openAPIRef(name string) openAPIObject {
+	return openAPIObject{"$ref": "#/components/schemas/" + name}
+}
+
+// openAPIResponse wraps the schema into the {"response": ...} envelope of writeResponse.
+func openAPIResponse(description string, schema openAPIObject) openAPIObject {
+	return openAPIObject{
+		"description": description,
+		"content": openAPIObject{
+			"application/json": openAPIObject{
+				"schema": openAPIObject{
+					"type":       "object",
+					"properties": openAPIObject{"response": schema},
+				},
+			},
+		},
+	}
+}
+
+func openAPIObjectSchema(properties openAPIObject) openAPIObject {
+	return openAPIObject{"type": "object", "properties": properties}
+}
+
+func openAPIQueryParam(name string, schemaType string, description string) openAPIObject {
+	return openAPIObject{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"schema":      openAPIObject{"type": schemaType},
+	}
+}
+
+// buildOpenAPI generates the OpenAPI 3 document of the tables the principal can read.
+func (exp Explorer) buildOpenAPI(ctx context.Context, principal *Principal) (openAPIObject, error) {
+	schemas := openAPIObject{
+		"Error": openAPIObjectSchema(openAPIObject{"error": openAPIObject{"type": "string"}}),
+	}
+	paths := openAPIObject{}
+	errorResponse := openAPIObject{
+		"description": "error",
+		"content": openAPIObject{
+			"application/json": openAPIObject{"schema": openAPIRef("Error")},
+		},
+	}
+
+	for _, table := range exp.tableNames() {
+		if exp.authorize(ctx, principal, Action{Table: table, Op: OpRead}) != nil {
+			continue
+		}
+
+		tableSchema, err := exp.getTableSchema(table)
+		if err != nil {
+			return nil, err
+		}
+
+		// component names allow the characters of table names, so tables can't collide like GraphQL type names
+		typeName := table
+		properties := openAPIObject{}
+		inputProperties := openAPIObject{}
+		required := make([]string, 0)
+		for _, column := range tableSchema.Columns {
+			properties[column.Name] = exp.openAPIColumnSchema(column)
+			inputProperties[column.Name] = exp.openAPIColumnSchema(column)
+			if !column.Nullable && column.Default == nil && !(column.Name == tableSchema.PrimaryKey && exp.primaryKeyGenerated(table)) {
+				required = append(required, column.Name)
+			}
+		}
+
+		schemas[typeName] = openAPIObjectSchema(properties)
+		input := openAPIObjectSchema(inputProperties)
+		if len(required) > 0 {
+			input["required"] = required
+		}
+		schemas[typeName+"_input"] = input
+
+		tag := []string{table}
+		paths["/"+table] = openAPIObject{
+			"get": openAPIObject{
+				"tags":        tag,
+				"operationId": "list_" + table,
+				"parameters": []openAPIObject{
+					openAPIQueryParam("limit", "integer", "page size"),
+					openAPIQueryParam("offset", "integer", "records to skip"),
+					openAPIQueryParam("cursor", "string", "next_cursor of the previous page"),
+					openAPIQueryParam("q", "string", "full text search"),
+					openAPIQueryParam("sort", "string", "columns to sort by, - for descending"),
+					openAPIQueryParam("filter", "string", "filter expression, e.g. (status=new OR status=open) AND id__in=1,2"),
+					openAPIQueryParam("fields", "string", "columns to return, e.g. id,title"),
+				},
+				"responses": openAPIObject{
+					"200": openAPIResponse("records", openAPIObjectSchema(openAPIObject{
+						"records":     openAPIObject{"type": "array", "items": openAPIRef(typeName)},
+						"next_cursor": openAPIObject{"type": "string"},
+					})),
+					"400": errorResponse,
+				},
+			},
+		}
+
+		if tableSchema.PrimaryKey == "" {
+			continue
+		}
+
+		pkSchema := openAPIObject{"type": "string"}
+		if column, ok := tableSchema.Column(tableSchema.PrimaryKey); ok {
+			pkSchema = exp.openAPIColumnSchema(column)
+		}
+		idParam := []openAPIObject{{"name": "id", "in": "path", "required": true, "schema": pkSchema}}
+		returnParam := []openAPIObject{openAPIQueryParam("return", "string", "representation to get the written record back")}
+		body := openAPIObject{
+			"required": true,
+			"content": openAPIObject{
+				"application/json": openAPIObject{"schema": openAPIRef(typeName + "_input")},
+			},
+		}
+
+		paths["/"+table+"/"] = openAPIObject{
+			"put": openAPIObject{
+				"tags":        tag,
+				"operationId": "create_" + table,
+				"parameters":  returnParam,
+				"requestBody": body,
+				"responses": openAPIObject{
+					"200": openAPIResponse("primary key of the created record", openAPIObjectSchema(openAPIObject{tableSchema.PrimaryKey: pkSchema})),
+					"400": errorResponse,
+				},
+			},
+		}
+		paths["/"+table+"/{id}"] = openAPIObject{
+			"parameters": idParam,
+			"get": openAPIObject{
+				"tags":        tag,
+				"operationId": "get_" + table,
+				"responses": openAPIObject{
+					"200": openAPIResponse("record", openAPIObjectSchema(openAPIObject{"record": openAPIRef(typeName)})),
+					"404": errorResponse,
+				},
+			},
+			"post": openAPIObject{
+				"tags":        tag,
+				"operationId": "update_" + table,
+				"parameters":  returnParam,
+				"requestBody": body,
+				"responses": openAPIObject{
+					"200": openAPIResponse("updated records", openAPIObjectSchema(openAPIObject{
+						"updated": openAPIObject{"type": "integer"},
+						"matched": openAPIObject{"type": "integer"},
+						"changed": openAPIObject{"type": "integer"},
+					})),
+					"400": errorResponse,
+				},
+			},
+			"delete": openAPIObject{
+				"tags":        tag,
+				"operationId": "delete_" + table,
+				"responses": openAPIObject{
+					"200": openAPIResponse("deleted records", openAPIObjectSchema(openAPIObject{"deleted": openAPIObject{"type": "integer"}})),
+				},
+			},
+		}
+
+		if exp.readOnly {
+			delete(paths, "/"+table+"/")
+			item := paths["/"+table+"/{id}"].(openAPIObject)
+			delete(item, "post")
+			delete(item, "delete")
+		}
+	}
+
+	server := exp.prefix
+	if server == "" {
+		server = "/"
+	}
+
+	return openAPIObject{
+		"openapi": "3.0.3",
+		"info": openAPIObject{
+			"title":   "db explorer",
+			"version": "1.0.0",
+		},
+		"servers":    []openAPIObject{{"url": server}},
+		"paths":      paths,
+		"components": openAPIObject{"schemas": schemas},
+	}, nil
+}
+
+func (exp Explorer) handlerGetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	doc, err := exp.buildOpenAPI(r.Context(), PrincipalFromContext(r.Context()))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>db explorer</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>SwaggerUIBundle({url: %q, dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+func (exp Explorer) handlerSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, swaggerUIPage, exp.prefix+"/_openapi.json")
+}
@@ -0,0 +1,71 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildOpenAPI(t *testing.T) {
+	maxLength := int64(255)
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{
+			{Name: "id", DataType: "int", Extra: "auto_increment"},
+			{Name: "title", DataType: "varchar", MaxLength: &maxLength},
+			{Name: "updated", DataType: "datetime", Nullable: true},
+		}},
+		"logs": {Columns: []ColumnInfo{{Name: "message", DataType: "text"}}},
+	})
+	exp.prefix = "/api"
+
+	doc, err := exp.buildOpenAPI(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(parsed.Servers) != 1 || parsed.Servers[0].URL != "/api" {
+		t.Errorf("unexpected servers %+v", parsed.Servers)
+	}
+	if _, ok := parsed.Paths["/items/{id}"]["delete"]; !ok {
+		t.Errorf("expected the item paths of items, got %v", parsed.Paths)
+	}
+	if _, ok := parsed.Paths["/logs/{id}"]; ok {
+		t.Errorf("expected no item paths for a table without primary key")
+	}
+
+	item := parsed.Components.Schemas["items"]
+	if item.Properties["id"]["type"] != "integer" || item.Properties["title"]["maxLength"] != float64(255) ||
+		item.Properties["updated"]["format"] != "date-time" || item.Properties["updated"]["nullable"] != true {
+		t.Errorf("unexpected properties %v", item.Properties)
+	}
+	if required := parsed.Components.Schemas["items_input"].Required; len(required) != 1 || required[0] != "title" {
+		t.Errorf("unexpected required columns %v", required)
+	}
+
+	exp.readOnly = true
+	doc, _ = exp.buildOpenAPI(context.Background(), nil)
+	if _, ok := doc["paths"].(openAPIObject)["/items/{id}"].(openAPIObject)["post"]; ok {
+		t.Errorf("expected no writes in read-only mode")
+	}
+}
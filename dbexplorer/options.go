@@ -0,0 +1,63 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Option configures optional Explorer behaviour in New.
+type Option func(exp *Explorer) error
+
+const defaultPageLimit = 5
+
+// WithPrefix serves the API under prefix, e.g. "/api" for GET /api/items, when the explorer is mounted
+// into a larger mux without http.StripPrefix.
+func WithPrefix(prefix string) Option {
+	return func(exp *Explorer) error {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix != "" && !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("prefix must start with /")
+		}
+
+		exp.prefix = prefix
+		return nil
+	}
+}
+
+// WithMiddleware adds middlewares to the router. They always run inside the built-in chain of
+// buildHandler: after gzip, body limits, logging, error reporting, the IP allowlist, authentication,
+// rate and table limits and permission checks, so PrincipalFromContext is available to them and
+// rejected requests never reach them. The built-ins can't be reordered; middleware that has to run
+// before them, e.g. in front of authentication, wraps the Explorer itself as an http.Handler.
+func WithMiddleware(middlewares ...func(http.Handler) http.Handler) Option {
+	return func(exp *Explorer) error {
+		exp.router.Use(middlewares...)
+		return nil
+	}
+}
+
+// WithDefaultLimit sets the page size of list requests without ?limit=, 5 by default.
+func WithDefaultLimit(limit int) Option {
+	return func(exp *Explorer) error {
+		if limit <= 0 {
+			return fmt.Errorf("default limit must be positive")
+		}
+
+		exp.defaultLimit = limit
+		return nil
+	}
+}
+
+// WithMaxLimit rejects list requests asking for more than limit records with 400, by default ?limit= is not capped.
+// NDJSON streams without ?limit= are exempt and return every record, they never hold more than one in memory.
+func WithMaxLimit(limit int) Option {
+	return func(exp *Explorer) error {
+		if limit <= 0 {
+			return fmt.Errorf("max limit must be positive")
+		}
+
+		exp.maxLimit = limit
+		return nil
+	}
+}
@@ -0,0 +1,150 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type Partition struct {
+	Name        string `json:"name"`
+	Method      string `json:"method"`
+	Expression  string `json:"expression"`
+	Description string `json:"description"`
+	Rows        int64  `json:"rows"`
+}
+
+type GetTableSchemaResponse struct {
+	Table  string       `json:"table"`
+	Schema *TableSchema `json:"schema"`
+}
+
+func (exp Explorer) loadPartitions(table string) ([]Partition, error) {
+	rows, err := exp.db().Query(exp.dialect.PartitionsQuery(), exp.dbName(table))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	partitions := make([]Partition, 0)
+	for rows.Next() {
+		var p Partition
+		if err := rows.Scan(&p.Name, &p.Method, &p.Expression, &p.Description, &p.Rows); err != nil {
+			return nil, err
+		}
+
+		p.Expression = strings.Trim(p.Expression, "`\"")
+		partitions = append(partitions, p)
+	}
+
+	return partitions, rows.Err()
+}
+
+func (s *TableSchema) Partition(name string) (Partition, bool) {
+	for _, p := range s.Partitions {
+		if p.Name == name {
+			return p, true
+		}
+	}
+
+	return Partition{}, false
+}
+
+// prunePartitions picks the partitions an equality filter on the partition key can match, so the query
+// names them explicitly. Only RANGE partitioning by a plain numeric column is handled, nil means no hint.
+func prunePartitions(schema *TableSchema, filters []Filter) []string {
+	if len(schema.Partitions) == 0 || !strings.HasPrefix(strings.ToUpper(schema.Partitions[0].Method), "RANGE") {
+		return nil
+	}
+
+	key := schema.Partitions[0].Expression
+	for _, f := range filters {
+		if f.Column != key || f.Operator != opEq {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(f.Value, 64)
+		if err != nil {
+			return nil
+		}
+
+		// MySQL lists RANGE partitions in order with their exclusive upper bound as the description
+		for _, p := range schema.Partitions {
+			if p.Description == "MAXVALUE" {
+				return []string{p.Name}
+			}
+
+			bound, err := strconv.ParseFloat(p.Description, 64)
+			if err != nil {
+				return nil
+			}
+			if value < bound {
+				return []string{p.Name}
+			}
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// fromClause is the table of a list query, restricted to the requested or pruned partitions.
+func (exp Explorer) fromClause(table string, listQuery ListQuery) (string, error) {
+	partitions := make([]string, 0)
+	if listQuery.Partition != "" {
+		partitions = append(partitions, listQuery.Partition)
+	} else if schema, err := exp.getTableSchema(table); err == nil {
+		partitions = prunePartitions(schema, listQuery.Filters)
+	}
+
+	source := exp.quote(table)
+	if len(partitions) > 0 {
+		source = exp.dialect.PartitionSource(exp.dbName(table), partitions)
+	}
+
+	if listQuery.IndexHint != "" {
+		hinter, ok := exp.dialect.(indexHinter)
+		if !ok {
+			return "", fmt.Errorf("index hints are not supported by the database")
+		}
+		source += " " + hinter.ForceIndex(exp.dialect.QuoteIdent(listQuery.IndexHint))
+	}
+
+	return source, nil
+}
+
+func (exp Explorer) parsePartition(table string, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ok := schema.Partition(name); !ok {
+		return "", fmt.Errorf("unknown partition %s", name)
+	}
+
+	return name, nil
+}
+
+func (exp Explorer) handlerGetTableSchema(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	schema, err := exp.getTableSchema(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, GetTableSchemaResponse{Table: tableName, Schema: schema})
+}
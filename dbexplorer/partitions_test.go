@@ -0,0 +1,33 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrunePartitions(t *testing.T) {
+	schema := &TableSchema{
+		Partitions: []Partition{
+			{Name: "p2023", Method: "RANGE", Expression: "year", Description: "2024"},
+			{Name: "p2024", Method: "RANGE", Expression: "year", Description: "2025"},
+			{Name: "pmax", Method: "RANGE", Expression: "year", Description: "MAXVALUE"},
+		},
+	}
+
+	cases := []struct {
+		filters  []Filter
+		expected []string
+	}{
+		{[]Filter{{Column: "year", Operator: opEq, Value: "2023"}}, []string{"p2023"}},
+		{[]Filter{{Column: "year", Operator: opEq, Value: "2024"}}, []string{"p2024"}},
+		{[]Filter{{Column: "year", Operator: opEq, Value: "2030"}}, []string{"pmax"}},
+		{[]Filter{{Column: "title", Operator: opEq, Value: "2023"}}, nil},
+		{[]Filter{{Column: "year", Operator: opFuzzy, Value: "2023"}}, nil},
+	}
+
+	for _, c := range cases {
+		if got := prunePartitions(schema, c.filters); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%+v: expected %v, got %v", c.filters, c.expected, got)
+		}
+	}
+}
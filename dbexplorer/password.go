@@ -0,0 +1,118 @@
+package dbexplorer
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	passwordScheme = "pbkdf2-sha256"
+	// passwordIterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	passwordIterations = 600000
+	passwordSaltSize   = 16
+)
+
+// HashPassword hashes a password of a LocalUser with salted PBKDF2-HMAC-SHA256,
+// the result has the form "pbkdf2-sha256$<iterations>$<salt>$<hash>".
+func HashPassword(password string) (string, error) {
+	return hashPassword(password, passwordIterations)
+}
+
+func hashPassword(password string, iterations int) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := pbkdf2SHA256([]byte(password), salt, iterations, sha256.Size)
+	return fmt.Sprintf("%s$%d$%s$%s", passwordScheme, iterations, hex.EncodeToString(salt), hex.EncodeToString(key)), nil
+}
+
+type passwordHash struct {
+	iterations int
+	salt       []byte
+	key        []byte
+}
+
+func parsePasswordHash(stored string) (passwordHash, error) {
+	parts := strings.Split(stored, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return passwordHash{}, fmt.Errorf("password is not a %s hash", passwordScheme)
+	}
+
+	var (
+		hash passwordHash
+		err  error
+	)
+	hash.iterations, err = strconv.Atoi(parts[1])
+	if err != nil || hash.iterations < 1 {
+		return passwordHash{}, fmt.Errorf("invalid iterations of password hash")
+	}
+
+	hash.salt, err = hex.DecodeString(parts[2])
+	if err != nil || len(hash.salt) == 0 {
+		return passwordHash{}, fmt.Errorf("invalid salt of password hash")
+	}
+
+	hash.key, err = hex.DecodeString(parts[3])
+	if err != nil || len(hash.key) == 0 {
+		return passwordHash{}, fmt.Errorf("invalid key of password hash")
+	}
+
+	return hash, nil
+}
+
+// checkUsers rejects local users whose passwords are not hashed with HashPassword.
+func checkUsers(users map[string]LocalUser) error {
+	for name, user := range users {
+		if _, err := parsePasswordHash(user.Password); err != nil {
+			return fmt.Errorf("user %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func checkPassword(stored string, given string) bool {
+	hash, err := parsePasswordHash(stored)
+	if err != nil {
+		return false
+	}
+
+	key := pbkdf2SHA256([]byte(given), hash.salt, hash.iterations, len(hash.key))
+	return subtle.ConstantTimeCompare(key, hash.key) == 1
+}
+
+// pbkdf2SHA256 derives a key of keyLen bytes as specified by RFC 8018.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u := prf.Sum(nil)
+
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
@@ -0,0 +1,125 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const (
+	OpRead  = "read"
+	OpWrite = "write"
+)
+
+// Action is an operation a principal performs on a table.
+type Action struct {
+	Table string
+	Op    string
+	// Method is the HTTP method of requests to the table endpoints, empty for nested checks.
+	Method string
+	// Columns are the columns written, set when a write is checked column by column.
+	Columns []string
+}
+
+// Authorizer decides whether the principal may perform the action, an error denies it and is
+// reported to the client as forbidden.
+type Authorizer func(ctx context.Context, principal *Principal, action Action) error
+
+// WithAuthorizer hands the authorization to an external policy engine, e.g. OPA or Casbin, instead of
+// the scopes and access rules. It is asked for every table a request touches and for every column it writes.
+func WithAuthorizer(authorizer Authorizer) Option {
+	return func(exp *Explorer) error {
+		exp.authorizer = authorizer
+		return nil
+	}
+}
+
+// readPostPaths are POST endpoints that take a query in the body and only read the table.
+var readPostPaths = regexp.MustCompile(`^/\w+/aggregate$`)
+
+// isReadRequest reports whether the request only reads the tables.
+func isReadRequest(r *http.Request) bool {
+	return isSafeMethod(r.Method) || r.Method == http.MethodPost && readPostPaths.MatchString(r.URL.Path)
+}
+
+func actionOp(method string) string {
+	if isSafeMethod(method) {
+		return OpRead
+	}
+
+	return OpWrite
+}
+
+// hasScope reports whether scopes like "read:orders write:users" allow the action.
+// "*" matches any operation or table, e.g. "read:*".
+func hasScope(scopes []string, action Action) bool {
+	for _, scope := range scopes {
+		op, table, ok := strings.Cut(scope, ":")
+		if !ok {
+			continue
+		}
+
+		if (op == "*" || op == action.Op) && (table == "*" || table == action.Table) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// adminScope lets a scoped principal reach the admin endpoints, e.g. /_tokens and /_query, its roles
+// alone are not enough once it is restricted to table operations.
+const adminScope = "admin"
+
+// hasAdminScope reports whether the scopes of the principal, if any, include admin:*.
+func hasAdminScope(principal *Principal) bool {
+	return principal == nil || principal.Scopes == nil || hasScope(principal.Scopes, Action{Op: adminScope, Table: "*"})
+}
+
+func (exp Explorer) authorize(ctx context.Context, principal *Principal, action Action) error {
+	if exp.authorizer != nil {
+		return exp.authorizer(ctx, principal, action)
+	}
+
+	if principal != nil && principal.Scopes != nil && !hasScope(principal.Scopes, action) {
+		return fmt.Errorf("missing scope %s:%s", action.Op, action.Table)
+	}
+
+	if exp.access != nil && len(exp.access.rules) > 0 && !exp.access.allows(principal, action) {
+		return fmt.Errorf("access to %s denied", action.Table)
+	}
+
+	return nil
+}
+
+func (exp Explorer) permissionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, err := exp.resolveRoles(r)
+		if err != nil {
+			writeError(w, http.StatusUnauthorized, err)
+			return
+		}
+		recordPrincipal(r)
+
+		table := strings.Split(r.URL.Path, "/")[1]
+		if exp.isValidTableName(table) {
+			action := Action{
+				Table:  table,
+				Op:     OpWrite,
+				Method: r.Method,
+			}
+			if isReadRequest(r) {
+				action.Op = OpRead
+			}
+
+			if err := exp.authorize(r.Context(), PrincipalFromContext(r.Context()), action); err != nil {
+				exp.writeForbidden(w, r, err)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,75 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScopes(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{"orders": {}, "users": {}})
+	handler := exp.permissionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	cases := []struct {
+		scopes   []string
+		method   string
+		path     string
+		expected int
+	}{
+		{nil, http.MethodDelete, "/orders/1", http.StatusOK},
+		{[]string{"read:orders"}, http.MethodGet, "/orders", http.StatusOK},
+		{[]string{"read:orders"}, http.MethodHead, "/orders/1", http.StatusOK},
+		{[]string{"read:orders"}, http.MethodGet, "/users", http.StatusForbidden},
+		{[]string{"read:orders"}, http.MethodPut, "/orders", http.StatusForbidden},
+		{[]string{"read:orders"}, http.MethodPost, "/orders/aggregate", http.StatusOK},
+		{[]string{"write:orders"}, http.MethodPost, "/orders/1", http.StatusOK},
+		{[]string{"write:orders"}, http.MethodDelete, "/orders/1", http.StatusOK},
+		{[]string{"write:orders"}, http.MethodGet, "/orders", http.StatusForbidden},
+		{[]string{"write:orders"}, http.MethodPut, "/users", http.StatusForbidden},
+		{[]string{"read:*"}, http.MethodGet, "/users", http.StatusOK},
+		{[]string{"read:*"}, http.MethodPost, "/users/1", http.StatusForbidden},
+		{[]string{"*:orders"}, http.MethodDelete, "/orders/1", http.StatusOK},
+		{[]string{"*:orders"}, http.MethodGet, "/users", http.StatusForbidden},
+		{[]string{"read:users", "write:orders"}, http.MethodPost, "/orders/1", http.StatusOK},
+		{[]string{"admin:*"}, http.MethodGet, "/orders", http.StatusForbidden},
+		{[]string{}, http.MethodGet, "/orders", http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(c.method, c.path, nil)
+		r = r.WithContext(ContextWithPrincipal(r.Context(), &Principal{Name: "token:ci", Scopes: c.scopes}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != c.expected {
+			t.Errorf("%s %s with scopes %v: expected %d, got %d", c.method, c.path, c.scopes, c.expected, w.Code)
+		}
+	}
+}
+
+func TestAdminScope(t *testing.T) {
+	exp := Explorer{adminRole: "admin"}
+
+	cases := []struct {
+		name      string
+		principal *Principal
+		expected  bool
+	}{
+		{"admin", &Principal{Name: "root", Roles: []string{"admin"}}, true},
+		{"admin with admin scope", &Principal{Name: "root", Roles: []string{"admin"}, Scopes: []string{"read:users", "admin:*"}}, true},
+		{"admin with wildcard scope", &Principal{Name: "root", Roles: []string{"admin"}, Scopes: []string{"*:*"}}, true},
+		{"admin with table scope", &Principal{Name: "root", Roles: []string{"admin"}, Scopes: []string{"read:users"}}, false},
+		{"admin with read all scope", &Principal{Name: "root", Roles: []string{"admin"}, Scopes: []string{"read:*"}}, false},
+		{"reader", &Principal{Name: "bob", Roles: []string{"reader"}}, false},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/_tokens", nil)
+		r = r.WithContext(ContextWithPrincipal(r.Context(), c.principal))
+
+		w := httptest.NewRecorder()
+		if got := exp.requireAdmin(w, r); got != c.expected {
+			t.Errorf("%s: expected %v, got %v (%d %s)", c.name, c.expected, got, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+func isIntegerDataType(dataType string) bool {
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		return true
+	}
+
+	return false
+}
+
+// WithPrimaryKey makes column the key of the table in the API instead of its primary key, e.g. a public
+// uuid column instead of the surrogate id. Item routes, writes, imports and relations use it; the column
+// should be unique.
+func WithPrimaryKey(table string, column string) Option {
+	return func(exp *Explorer) error {
+		if exp.primaryKeys == nil {
+			exp.primaryKeys = make(map[string]string)
+		}
+
+		exp.primaryKeys[table] = column
+		return nil
+	}
+}
+
+// primaryKeyValue converts the id from the URL to the type of the primary key column,
+// so tables keyed by VARCHAR or UUID columns can be addressed as well as numeric ones.
+func (exp Explorer) primaryKeyValue(table string, id string) (any, error) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	column, ok := schema.Column(schema.PrimaryKey)
+	if !ok {
+		return id, nil
+	}
+
+	switch {
+	case isIntegerDataType(column.DataType):
+		value, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %s", id)
+		}
+		return value, nil
+	case column.DataType == "uuid":
+		if !uuidPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid id %s", id)
+		}
+	}
+
+	return id, nil
+}
+
+// primaryKeyGenerated reports whether the database or an IDGenerator assigns the primary key of new rows,
+// otherwise the key has to be provided on create.
+func (exp Explorer) primaryKeyGenerated(table string) bool {
+	if _, ok := exp.idGenerators[table]; ok {
+		return true
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return true
+	}
+
+	column, ok := schema.Column(schema.PrimaryKey)
+	if !ok {
+		return true
+	}
+
+	autoIncrement := strings.Contains(strings.ToLower(column.Extra), "auto_increment")
+
+	// a default of an overridden key can't be read back with LastInsertId
+	if _, overridden := exp.primaryKeys[table]; overridden && !exp.dialect.InsertReturning() {
+		return autoIncrement
+	}
+
+	return autoIncrement || column.Default != nil
+}
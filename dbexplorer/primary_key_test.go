@@ -0,0 +1,58 @@
+package dbexplorer
+
+import "testing"
+
+func TestPrimaryKeyValue(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items":    {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "int"}}},
+		"sessions": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "uuid"}}},
+		"codes":    {PrimaryKey: "code", Columns: []ColumnInfo{{Name: "code", DataType: "varchar"}}},
+	})
+
+	cases := []struct {
+		table    string
+		id       string
+		expected any
+	}{
+		{"items", "42", int64(42)},
+		{"items", "abc", nil},
+		{"sessions", "9b2f4a9e-6a3c-4f0e-9d43-2a1c6b5e7f80", "9b2f4a9e-6a3c-4f0e-9d43-2a1c6b5e7f80"},
+		{"sessions", "42", nil},
+		{"codes", "RU-MOW", "RU-MOW"},
+	}
+
+	for _, c := range cases {
+		got, err := exp.primaryKeyValue(c.table, c.id)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("%s/%s: expected error", c.table, c.id)
+			}
+			continue
+		}
+
+		if err != nil || got != c.expected {
+			t.Errorf("%s/%s: expected %#v, got %#v (%v)", c.table, c.id, c.expected, got, err)
+		}
+	}
+}
+
+func TestPrimaryKeyOverride(t *testing.T) {
+	def := "uuid()"
+	exp := newTestExplorer(map[string]*TableSchema{
+		"orders": {PrimaryKey: "uuid", Columns: []ColumnInfo{
+			{Name: "id", DataType: "int", Extra: "auto_increment"},
+			{Name: "uuid", DataType: "char", Default: &def},
+		}},
+	})
+	if err := WithPrimaryKey("orders", "uuid")(&exp); err != nil {
+		t.Fatal(err)
+	}
+
+	if pk, err := exp.getPrimaryKey("orders"); err != nil || pk != "uuid" {
+		t.Errorf("expected uuid, got %s, %v", pk, err)
+	}
+
+	if exp.primaryKeyGenerated("orders") {
+		t.Errorf("expected the overridden key to be required without RETURNING")
+	}
+}
@@ -0,0 +1,40 @@
+package dbexplorer
+
+import "context"
+
+// Principal is the authenticated caller of a request.
+// Scopes restrict the principal to the listed table operations, nil means no restriction.
+// Admin endpoints and approvals need the admin:* scope in addition to the role.
+type Principal struct {
+	Name   string
+	Roles  []string
+	Scopes []string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	if p == nil {
+		return false
+	}
+
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal. The authentication middleware sets it
+// for every request, hooks and handlers wrapping the explorer read it with PrincipalFromContext.
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal set by the authentication middleware or nil.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
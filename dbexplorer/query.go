@@ -0,0 +1,246 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	defaultQueryMaxRows = 1000
+	defaultQueryTimeout = 10 * time.Second
+)
+
+var errRowLimit = errors.New("row limit reached")
+
+// forbiddenQueryWords are keywords and functions of SELECT statements that write, lock or stall the database.
+var forbiddenQueryWords = map[string]bool{
+	"INTO":         true,
+	"UPDATE":       true,
+	"SHARE":        true,
+	"LOCK":         true,
+	"GET_LOCK":     true,
+	"RELEASE_LOCK": true,
+	"LOAD_FILE":    true,
+	"SLEEP":        true,
+	"PG_SLEEP":     true,
+	"BENCHMARK":    true,
+}
+
+// QueryOptions limit the ad-hoc queries of POST /_query.
+type QueryOptions struct {
+	// MaxRows is the row limit of a query, 1000 by default. A request may ask for less.
+	MaxRows int
+	// Timeout of a query, 10 seconds by default.
+	Timeout time.Duration
+}
+
+type QueryRequest struct {
+	Query string `json:"query"`
+	Args  []any  `json:"args"`
+	Limit int    `json:"limit"`
+}
+
+type QueryResponse struct {
+	Records []map[string]any `json:"records"`
+	// Truncated is set when the query returned more rows than the limit.
+	Truncated bool `json:"truncated"`
+}
+
+// WithQueryEndpoint enables POST /_query running a single SELECT statement in a read-only transaction.
+// With authentication only the admin role may use it, because queries bypass the table permissions.
+func WithQueryEndpoint(options QueryOptions) Option {
+	return func(exp *Explorer) error {
+		if options.MaxRows <= 0 {
+			options.MaxRows = defaultQueryMaxRows
+		}
+		if options.Timeout <= 0 {
+			options.Timeout = defaultQueryTimeout
+		}
+
+		exp.queryOptions = &options
+		return nil
+	}
+}
+
+// checkSelectStatement accepts a single SELECT statement and rejects everything else, including SELECT ... INTO,
+// locking reads and functions stalling the server. Quoted strings, identifiers and comments are skipped.
+func checkSelectStatement(query string) error {
+	words, err := statementWords(query)
+	if err != nil {
+		return err
+	}
+
+	if len(words) == 0 || words[0] != "SELECT" {
+		return fmt.Errorf("only SELECT statements are allowed")
+	}
+
+	for _, word := range words {
+		if word == ";" {
+			return fmt.Errorf("only a single statement is allowed")
+		}
+		if forbiddenQueryWords[word] {
+			return fmt.Errorf("%s is not allowed in queries", word)
+		}
+	}
+
+	return nil
+}
+
+// statementWords splits the query into upper cased words, a trailing semicolon is dropped.
+func statementWords(query string) ([]string, error) {
+	query = strings.TrimRightFunc(query, unicode.IsSpace)
+	query = strings.TrimSuffix(query, ";")
+
+	var (
+		words []string
+		word  strings.Builder
+	)
+
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, strings.ToUpper(word.String()))
+			word.Reset()
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			flush()
+			end := i + 1
+			for ; end < len(query); end++ {
+				if query[end] == '\\' && c != '`' {
+					end++
+					continue
+				}
+				if query[end] == c {
+					break
+				}
+			}
+			if end >= len(query) {
+				return nil, fmt.Errorf("unterminated quote")
+			}
+			i = end
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "--")):
+			flush()
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return words, nil
+			}
+			i += end
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			flush()
+			// MySQL executes /*! */ and reads hints from /*+ */, they are code rather than comments
+			if strings.HasPrefix(query[i:], "/*!") || strings.HasPrefix(query[i:], "/*+") {
+				return nil, fmt.Errorf("executable comments are not allowed")
+			}
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 3
+		case c == ';':
+			flush()
+			words = append(words, ";")
+		case c == '_' || c == '$' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || c >= 0x80:
+			word.WriteByte(c)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return words, nil
+}
+
+func (exp Explorer) handlerQuery(w http.ResponseWriter, r *http.Request) {
+	if exp.queryOptions == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("queries are not enabled"))
+		return
+	}
+
+	if exp.adminRestricted() && !exp.requireAdmin(w, r) {
+		return
+	}
+
+	var req QueryRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if err := checkSelectStatement(req.Query); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limit := exp.queryOptions.MaxRows
+	if req.Limit > 0 && req.Limit < limit {
+		limit = req.Limit
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), exp.queryOptions.Timeout)
+	defer cancel()
+
+	resp, err := exp.runQuery(ctx, req, limit)
+	if ctx.Err() == context.DeadlineExceeded {
+		writeError(w, http.StatusGatewayTimeout, fmt.Errorf("query timed out"))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	writeResponse(w, resp)
+}
+
+// runQuery reads at most limit rows of the query in a read-only transaction, so even a statement slipping
+// through the check cannot write. The query runs as it is, the rows past the limit are never read.
+func (exp Explorer) runQuery(ctx context.Context, req QueryRequest, limit int) (QueryResponse, error) {
+	resp := QueryResponse{
+		Records: make([]map[string]any, 0),
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tx, err := exp.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return resp, err
+	}
+
+	defer tx.Rollback()
+
+	query := strings.TrimSuffix(strings.TrimRightFunc(req.Query, unicode.IsSpace), ";")
+	rows, err := tx.QueryContext(ctx, query, req.Args...)
+	if err != nil {
+		return resp, wrapQueryError(query, err)
+	}
+
+	defer rows.Close()
+
+	err = exp.eachRow(rows, func(item map[string]any) error {
+		if len(resp.Records) == limit {
+			resp.Truncated = true
+			return errRowLimit
+		}
+
+		resp.Records = append(resp.Records, item)
+		return nil
+	})
+	if err == errRowLimit {
+		// rows.Close would read the rest of the result, canceling drops it
+		cancel()
+		err = nil
+	}
+
+	return resp, err
+}
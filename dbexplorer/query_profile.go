@@ -0,0 +1,153 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// opSearch names the ?q= full text search in QueryProfile.Operators.
+const opSearch = "search"
+
+// QueryProfile restricts the queries of principals having the role, e.g. analysts may aggregate and read
+// big pages while service tokens only look records up by primary key.
+type QueryProfile struct {
+	Role string
+	// MaxLimit is the largest page a list request may ask for, 0 means no cap.
+	MaxLimit int
+	// Operators are the allowed filter operators, e.g. "eq" and "in", plus "search" for ?q=. Nil allows all.
+	Operators []string
+	// Aggregate allows the _aggregate, _timeseries and _minmax endpoints.
+	Aggregate bool
+	// PrimaryKeyOnly allows only list requests filtering the primary key by equality.
+	PrimaryKeyOnly bool
+}
+
+// WithQueryProfiles restricts the queries by the roles of the principal. The first profile matching
+// a role applies, principals without a matching profile are not restricted.
+func WithQueryProfiles(profiles ...QueryProfile) Option {
+	return func(exp *Explorer) error {
+		exp.queryProfiles = append(exp.queryProfiles, profiles...)
+		return nil
+	}
+}
+
+func (exp Explorer) queryProfile(principal *Principal) *QueryProfile {
+	for i := range exp.queryProfiles {
+		if principal.HasRole(exp.queryProfiles[i].Role) {
+			return &exp.queryProfiles[i]
+		}
+	}
+
+	return nil
+}
+
+func queryForbidden(format string, args ...any) error {
+	return statusError{Status: http.StatusForbidden, Err: fmt.Errorf(format, args...)}
+}
+
+func (p *QueryProfile) allowsOperator(operator string) bool {
+	if p.Operators == nil {
+		return true
+	}
+
+	for _, allowed := range p.Operators {
+		if allowed == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+func exprFilters(expr *FilterExpr) []Filter {
+	if expr == nil {
+		return nil
+	}
+	if expr.Filter != nil {
+		return []Filter{*expr.Filter}
+	}
+
+	filters := make([]Filter, 0)
+	for i := range expr.Children {
+		filters = append(filters, exprFilters(&expr.Children[i])...)
+	}
+
+	return filters
+}
+
+// checkListQuery enforces the profile of the principal on a list query before it is built.
+func (exp Explorer) checkListQuery(principal *Principal, table string, listQuery ListQuery) error {
+	profile := exp.queryProfile(principal)
+	if profile == nil {
+		return nil
+	}
+
+	if profile.MaxLimit > 0 && listQuery.Pagination.Limit > profile.MaxLimit {
+		return queryForbidden("limit over %d is not allowed", profile.MaxLimit)
+	}
+
+	if listQuery.Search != "" && !profile.allowsOperator(opSearch) {
+		return queryForbidden("search is not allowed")
+	}
+
+	filters := append(exprFilters(listQuery.Expr), listQuery.Filters...)
+	for _, f := range filters {
+		if !profile.allowsOperator(f.Operator) {
+			return queryForbidden("operator %s is not allowed", f.Operator)
+		}
+	}
+
+	if profile.PrimaryKeyOnly {
+		schema, err := exp.getTableSchema(table)
+		if err != nil {
+			return err
+		}
+		primaryKey := schema.PrimaryKey
+
+		byKey := false
+		for _, f := range listQuery.Filters {
+			byKey = byKey || f.Column == primaryKey && (f.Operator == opEq || f.Operator == opIn)
+		}
+		if !byKey || listQuery.Search != "" || listQuery.Expr != nil {
+			return queryForbidden("only lookups by %s are allowed", primaryKey)
+		}
+	}
+
+	return nil
+}
+
+// planListQuery is buildListQuery for the principal of the request.
+func (exp Explorer) planListQuery(ctx context.Context, table string, listQuery ListQuery) (string, []any, error) {
+	if err := exp.checkListQuery(PrincipalFromContext(ctx), table, listQuery); err != nil {
+		return "", nil, err
+	}
+
+	return exp.buildListQuery(table, listQuery)
+}
+
+// requireAggregate writes 403 for principals whose profile doesn't allow aggregations.
+func (exp Explorer) requireAggregate(w http.ResponseWriter, r *http.Request) bool {
+	if profile := exp.queryProfile(PrincipalFromContext(r.Context())); profile != nil && !profile.Aggregate {
+		exp.writeForbidden(w, r, fmt.Errorf("aggregations are not allowed"))
+		return false
+	}
+
+	return true
+}
+
+// writeQueryError reports errors carrying a status, e.g. of a query profile, and others as internal errors.
+func writeQueryError(w http.ResponseWriter, r *http.Request, err error) {
+	if writeSQLError(w, r, err) {
+		return
+	}
+
+	var statusErr statusError
+	if errors.As(err, &statusErr) {
+		writeError(w, statusErr.Status, err)
+		return
+	}
+
+	writeInternalError(w, r, err)
+}
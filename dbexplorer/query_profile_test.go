@@ -0,0 +1,51 @@
+package dbexplorer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestQueryProfiles(t *testing.T) {
+	exp := newItemsExplorer()
+	exp.queryProfiles = []QueryProfile{
+		{Role: "service", MaxLimit: 10, Operators: []string{opEq, opIn}, PrimaryKeyOnly: true},
+		{Role: "analyst", Aggregate: true},
+	}
+	service := &Principal{Roles: []string{"service"}}
+
+	cases := []struct {
+		principal *Principal
+		query     url.Values
+		allowed   bool
+	}{
+		{service, url.Values{"id": {"1"}}, true},
+		{service, url.Values{"id__in": {"1,2"}}, true},
+		{service, url.Values{"status": {"new"}}, false},
+		{service, url.Values{"id": {"1"}, "limit": {"100"}}, false},
+		{service, url.Values{"id__nin": {"1"}}, false},
+		{service, url.Values{"id": {"1"}, "filter": {"status=a OR status=b"}}, false},
+		{&Principal{Roles: []string{"analyst"}}, url.Values{"limit": {"1000"}, "q": {"x"}}, true},
+		{nil, url.Values{"status__nin": {"a"}}, true},
+	}
+
+	for _, c := range cases {
+		listQuery, err := exp.parseListQuery("items", c.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = exp.checkListQuery(c.principal, "items", listQuery)
+		if c.allowed && err != nil || !c.allowed && !errors.As(err, &statusError{}) {
+			t.Errorf("%v: expected allowed=%v, got %v", c.query, c.allowed, err)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/items/_aggregate", nil)
+	w := httptest.NewRecorder()
+	if exp.requireAggregate(w, r.WithContext(ContextWithPrincipal(r.Context(), service))) || w.Code != http.StatusForbidden {
+		t.Errorf("expected aggregations to be forbidden for service, got %d", w.Code)
+	}
+}
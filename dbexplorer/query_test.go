@@ -0,0 +1,71 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCheckSelectStatement(t *testing.T) {
+	allowed := []string{
+		"SELECT * FROM items",
+		"select id, title from items where title = 'update; into' limit 5;",
+		"SELECT `into`, \"lock\" FROM items -- for update",
+		"SELECT /* sleep(10) */ id FROM items WHERE title = 'it\\'s'",
+	}
+	for _, query := range allowed {
+		if err := checkSelectStatement(query); err != nil {
+			t.Errorf("%s: unexpected error %v", query, err)
+		}
+	}
+
+	rejected := []string{
+		"",
+		"DELETE FROM items",
+		"UPDATE items SET title = 'x'",
+		"SELECT 1; DROP TABLE items",
+		"SELECT * FROM items FOR UPDATE",
+		"SELECT * FROM items LOCK IN SHARE MODE",
+		"SELECT * INTO OUTFILE '/tmp/x' FROM items",
+		"SELECT SLEEP(10)",
+		"WITH x AS (DELETE FROM items RETURNING *) SELECT * FROM x",
+		"SELECT 'unterminated",
+		"/* SELECT */ DELETE FROM items",
+		"SELECT 1 /*! , SLEEP(10) */",
+		"SELECT 1 /*!50000 , BENCHMARK(1000000, MD5(1)) */",
+		"SELECT /*+ MAX_EXECUTION_TIME(1) */ id FROM items",
+	}
+	for _, query := range rejected {
+		if err := checkSelectStatement(query); err == nil {
+			t.Errorf("%s: expected error", query)
+		}
+	}
+}
+
+func TestQueryHandler(t *testing.T) {
+	handler, err := New(openTestDB(t), WithQueryEndpoint(QueryOptions{MaxRows: 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		body     string
+		status   int
+		expected string
+	}{
+		// a self join has duplicate column names, which a derived table wrapping the query would reject
+		{`{"query": "SELECT a.id, b.id FROM items a JOIN items b ON b.id = a.id ORDER BY a.id"}`, http.StatusOK,
+			`{"response":{"records":[{"id":1},{"id":2}],"truncated":false}}`},
+		{`{"query": "SELECT id FROM items ORDER BY id", "limit": 1}`, http.StatusOK,
+			`{"response":{"records":[{"id":1}],"truncated":true}}`},
+		{`{"query": "SELECT a.id FROM items a, items b, items c ORDER BY a.id;"}`, http.StatusOK,
+			`{"response":{"records":[{"id":1},{"id":1},{"id":1}],"truncated":true}}`},
+		{`{"query": `, http.StatusBadRequest, ""},
+	}
+
+	for _, c := range cases {
+		w := serveRequest(handler, http.MethodPost, "/_query", c.body)
+		if w.Code != c.status || (c.expected != "" && w.Body.String() != c.expected) {
+			t.Errorf("%s: expected %d %s, got %d %s", c.body, c.status, c.expected, w.Code, w.Body.String())
+		}
+	}
+}
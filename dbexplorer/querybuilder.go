@@ -0,0 +1,128 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// queryBuilder builds the single-row statements of the CRUD handlers. Every identifier is checked
+// against the cached schema before it is quoted, and values only ever travel as bound parameters.
+// Metadata queries (see Dialect) take the table name as a parameter as well.
+type queryBuilder struct {
+	exp    Explorer
+	table  string
+	schema *TableSchema
+}
+
+func (exp Explorer) queryBuilder(table string) (queryBuilder, error) {
+	schema, ok := exp.tableSchemas()[table]
+	if !ok {
+		return queryBuilder{}, fmt.Errorf("unknown table %q", table)
+	}
+
+	return queryBuilder{exp: exp, table: table, schema: schema}, nil
+}
+
+func (b queryBuilder) column(name string) (string, error) {
+	if _, ok := b.schema.Column(name); !ok {
+		return "", fmt.Errorf("unknown column %q in table %q", name, b.table)
+	}
+
+	return b.exp.quoteColumn(b.table, name), nil
+}
+
+// assignments returns the quoted columns of form in a stable order together with their values.
+func (b queryBuilder) assignments(form map[string]any) ([]string, []any, error) {
+	names := make([]string, 0, len(form))
+	for name := range form {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columns := make([]string, len(names))
+	values := make([]any, len(names))
+	for i, name := range names {
+		column, err := b.column(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		columns[i] = column
+		values[i] = form[name]
+	}
+
+	return columns, values, nil
+}
+
+// selectByKey selects the columns of the row with the key, all of them for none.
+func (b queryBuilder) selectByKey(key string, columns ...string) (string, error) {
+	column, err := b.column(key)
+	if err != nil {
+		return "", err
+	}
+
+	selectList := "*"
+	if len(columns) > 0 {
+		quoted := make([]string, len(columns))
+		for i, name := range columns {
+			if quoted[i], err = b.column(name); err != nil {
+				return "", err
+			}
+		}
+		selectList = strings.Join(quoted, ", ")
+	}
+
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", selectList, b.exp.quote(b.table), column), nil
+}
+
+func (b queryBuilder) existsByKey(key string) (string, error) {
+	column, err := b.column(key)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("SELECT 1 FROM %s WHERE %s = ? LIMIT 1", b.exp.quote(b.table), column), nil
+}
+
+func (b queryBuilder) insert(form map[string]any) (string, []any, error) {
+	columns, values, err := b.assignments(form)
+	if err != nil {
+		return "", nil, err
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		b.exp.quote(b.table), strings.Join(columns, ", "), placeholders)
+
+	return query, values, nil
+}
+
+func (b queryBuilder) update(form map[string]any, key string, keyValue any) (string, []any, error) {
+	columns, values, err := b.assignments(form)
+	if err != nil {
+		return "", nil, err
+	}
+
+	keyColumn, err := b.column(key)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for i, column := range columns {
+		columns[i] = column + " = ?"
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", b.exp.quote(b.table), strings.Join(columns, ", "), keyColumn)
+
+	return query, append(values, keyValue), nil
+}
+
+func (b queryBuilder) deleteByKey(key string) (string, error) {
+	column, err := b.column(key)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("DELETE FROM %s WHERE %s = ?", b.exp.quote(b.table), column), nil
+}
@@ -0,0 +1,49 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {Columns: []ColumnInfo{{Name: "id"}, {Name: "title"}, {Name: "updated"}}},
+	})
+
+	if _, err := exp.queryBuilder("missing"); err == nil {
+		t.Fatalf("expected error for an unknown table")
+	}
+
+	builder, err := exp.queryBuilder("items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, args, err := builder.insert(map[string]any{"updated": "x", "title": "y"})
+	if err != nil || query != "INSERT INTO `items` (`title`, `updated`) VALUES (?, ?)" || !reflect.DeepEqual(args, []any{"y", "x"}) {
+		t.Errorf("unexpected insert %q %v %v", query, args, err)
+	}
+
+	query, args, err = builder.update(map[string]any{"title": "y"}, "id", 1)
+	if err != nil || query != "UPDATE `items` SET `title` = ? WHERE `id` = ?" || !reflect.DeepEqual(args, []any{"y", 1}) {
+		t.Errorf("unexpected update %q %v %v", query, args, err)
+	}
+
+	if _, _, err := builder.update(map[string]any{"title` = 1 --": "y"}, "id", 1); err == nil {
+		t.Errorf("expected error for an unknown column")
+	}
+
+	query, err = builder.selectByKey("id", "id", "title")
+	if err != nil || query != "SELECT `id`, `title` FROM `items` WHERE `id` = ?" {
+		t.Errorf("unexpected select %q %v", query, err)
+	}
+
+	query, err = builder.existsByKey("id")
+	if err != nil || query != "SELECT 1 FROM `items` WHERE `id` = ? LIMIT 1" {
+		t.Errorf("unexpected exists query %q %v", query, err)
+	}
+
+	if _, err := builder.deleteByKey("nope"); err == nil {
+		t.Errorf("expected error for an unknown key column")
+	}
+}
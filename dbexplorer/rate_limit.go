@@ -0,0 +1,180 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxIdleBuckets is the number of tracked clients above which full buckets are dropped.
+const maxIdleBuckets = 10000
+
+// defaultAuthFailureLimit allows a client address 10 failed authentications at once and another one every 6 seconds.
+var defaultAuthFailureLimit = RateLimit{Rate: 1.0 / 6, Burst: 10}
+
+// RateLimit configures a token bucket per client: Burst requests at once, refilled at Rate requests per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+	// ByPrincipal limits authenticated requests per principal, e.g. per API key, instead of per client address.
+	ByPrincipal bool
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type rateLimiter struct {
+	limit RateLimit
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// WithRateLimit answers clients exceeding the limit with 429 and Retry-After.
+func WithRateLimit(limit RateLimit) Option {
+	return func(exp *Explorer) error {
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			return fmt.Errorf("rate limit requires positive rate and burst")
+		}
+
+		exp.rateLimiter = newRateLimiter(limit)
+		return nil
+	}
+}
+
+// WithAuthFailureLimit limits the failed authentications per client address instead of 10 at once and one
+// every 6 seconds. Clients over the limit get 429 before their credentials are checked.
+func WithAuthFailureLimit(limit RateLimit) Option {
+	return func(exp *Explorer) error {
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			return fmt.Errorf("auth failure limit requires positive rate and burst")
+		}
+
+		exp.authFailures = newRateLimiter(limit)
+		return nil
+	}
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// allow takes a token of the client's bucket. Otherwise it returns the time until the next token.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.refill(client)
+	if bucket.tokens < 1 {
+		return false, l.wait(bucket)
+	}
+
+	bucket.tokens--
+	return true, 0
+}
+
+// exhausted reports whether the client's bucket is empty without taking a token, with the time until the next one.
+func (l *rateLimiter) exhausted(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.refill(client)
+	if bucket.tokens < 1 {
+		return true, l.wait(bucket)
+	}
+
+	return false, 0
+}
+
+// refill returns the client's bucket with the tokens added since its last use, l.mu must be held.
+func (l *rateLimiter) refill(client string) *tokenBucket {
+	now := l.now()
+	burst := float64(l.limit.Burst)
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.dropFull(now)
+		}
+
+		bucket = &tokenBucket{tokens: burst, updated: now}
+		l.buckets[client] = bucket
+	}
+
+	bucket.tokens = math.Min(burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*l.limit.Rate)
+	bucket.updated = now
+
+	return bucket
+}
+
+func (l *rateLimiter) wait(bucket *tokenBucket) time.Duration {
+	return time.Duration((1 - bucket.tokens) / l.limit.Rate * float64(time.Second))
+}
+
+// dropFull forgets the clients whose buckets have refilled, they start with a full bucket anyway.
+func (l *rateLimiter) dropFull(now time.Time) {
+	for client, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*l.limit.Rate >= float64(l.limit.Burst) {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+func (exp Explorer) rateLimitClient(r *http.Request, byPrincipal bool) string {
+	if principal := PrincipalFromContext(r.Context()); byPrincipal && principal != nil {
+		return "principal:" + principal.Name
+	}
+
+	allowlist := exp.ipAllowlist
+	if allowlist == nil {
+		allowlist = &ipAllowlist{}
+	}
+
+	return "ip:" + allowlist.clientIP(r).String()
+}
+
+// rateLimitMiddleware runs after authentication, so requests are limited per principal when configured.
+func (exp Explorer) rateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := exp.rateLimiter.allow(exp.rateLimitClient(r, exp.rateLimiter.limit.ByPrincipal))
+		if !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, LocalizedError{Code: MsgTooManyRequests})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkAuthFailures answers 429 when the client address has run out of failed authentications.
+func (exp Explorer) checkAuthFailures(w http.ResponseWriter, r *http.Request) bool {
+	if exp.authFailures == nil {
+		return true
+	}
+
+	if exhausted, wait := exp.authFailures.exhausted(exp.rateLimitClient(r, false)); exhausted {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, LocalizedError{Code: MsgTooManyRequests})
+		return false
+	}
+
+	return true
+}
+
+// recordAuthFailure counts a failed authentication of the client address.
+func (exp Explorer) recordAuthFailure(r *http.Request) {
+	if exp.authFailures != nil {
+		exp.authFailures.allow(exp.rateLimitClient(r, false))
+	}
+}
@@ -0,0 +1,113 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(RateLimit{Rate: 2, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.allow("a"); !ok {
+			t.Fatalf("request %d: expected to be allowed", i)
+		}
+	}
+
+	if ok, wait := limiter.allow("a"); ok || wait != 500*time.Millisecond {
+		t.Errorf("expected to wait 500ms, got %v %v", ok, wait)
+	}
+
+	if ok, _ := limiter.allow("b"); !ok {
+		t.Errorf("expected another client to be allowed")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Errorf("expected a refilled token")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	exp := Explorer{rateLimiter: newRateLimiter(RateLimit{Rate: 0.1, Burst: 1, ByPrincipal: true})}
+	handler := exp.rateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(principal string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/items", nil)
+		if principal != "" {
+			r = r.WithContext(ContextWithPrincipal(r.Context(), &Principal{Name: principal}))
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := request("bob"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	w := request("bob")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "10" {
+		t.Errorf("expected 429 with Retry-After 10, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	if w := request("alice"); w.Code != http.StatusOK {
+		t.Errorf("expected another principal to be allowed, got %d", w.Code)
+	}
+	if w := request(""); w.Code != http.StatusOK {
+		t.Errorf("expected an anonymous client to be limited by address, got %d", w.Code)
+	}
+}
+
+func TestAuthFailureLimit(t *testing.T) {
+	exp := newItemsExplorer()
+	if err := WithAuthFailureLimit(RateLimit{Rate: 0.1, Burst: 2})(&exp); err != nil {
+		t.Fatal(err)
+	}
+	exp.authenticators = []Authenticator{AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		if r.Header.Get(apiKeyHeader) == "good" {
+			return &Principal{Name: "bob"}, nil
+		}
+		return nil, nil
+	})}
+	handler := exp.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(addr string, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/items", nil)
+		r.RemoteAddr = addr + ":1234"
+		r.Header.Set(apiKeyHeader, key)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := request("192.0.2.1", "good"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	for i := 0; i < 2; i++ {
+		if w := request("192.0.2.1", "bad"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("failure %d: expected 401, got %d", i, w.Code)
+		}
+	}
+
+	// the credentials aren't checked once the address is over the limit
+	for _, key := range []string{"bad", "good"} {
+		if w := request("192.0.2.1", key); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "10" {
+			t.Errorf("%s key: expected 429 with Retry-After 10, got %d %q", key, w.Code, w.Header().Get("Retry-After"))
+		}
+	}
+
+	if w := request("192.0.2.2", "good"); w.Code != http.StatusOK {
+		t.Errorf("expected another address to be allowed, got %d", w.Code)
+	}
+
+	if err := WithAuthFailureLimit(RateLimit{})(&exp); err == nil {
+		t.Errorf("expected error for an empty limit")
+	}
+}
@@ -0,0 +1,36 @@
+package dbexplorer
+
+import (
+	"errors"
+	"net/http"
+)
+
+var errReadOnly = errors.New("explorer is read-only")
+
+// readOnlyPaths accept POST in read-only mode, they don't write the tables. GraphQL rejects mutations itself.
+var readOnlyPaths = map[string]bool{
+	"/_login":  true,
+	"/_logout": true,
+	"/_query":  true,
+	"/graphql": true,
+}
+
+// WithReadOnly disables every write, e.g. to browse a production database. Writing requests get 405.
+func WithReadOnly() Option {
+	return func(exp *Explorer) error {
+		exp.readOnly = true
+		return nil
+	}
+}
+
+func (exp Explorer) readOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isReadRequest(r) && !readOnlyPaths[r.URL.Path] {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, errReadOnly)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
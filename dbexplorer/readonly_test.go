@@ -0,0 +1,37 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	exp := Explorer{readOnly: true}
+	handler := exp.readOnlyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	cases := []struct {
+		method   string
+		path     string
+		expected int
+	}{
+		{http.MethodGet, "/items", http.StatusOK},
+		{http.MethodHead, "/items/1", http.StatusOK},
+		{http.MethodPost, "/graphql", http.StatusOK},
+		{http.MethodPut, "/items/", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/items/1", http.StatusMethodNotAllowed},
+		{http.MethodDelete, "/items/1", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/_tx", http.StatusMethodNotAllowed},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(c.method, c.path, nil))
+		if w.Code != c.expected {
+			t.Errorf("%s %s: expected %d, got %d", c.method, c.path, c.expected, w.Code)
+		}
+		if w.Code == http.StatusMethodNotAllowed && w.Header().Get("Allow") != "GET, HEAD" {
+			t.Errorf("%s %s: missing Allow header", c.method, c.path)
+		}
+	}
+}
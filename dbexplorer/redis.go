@@ -0,0 +1,218 @@
+package dbexplorer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultRedisDialTimeout = 5 * time.Second
+
+// RedisBus is a ClusterBus over Redis pub/sub, it speaks the RESP protocol directly.
+type RedisBus struct {
+	Addr     string
+	Channel  string
+	Password string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisBus publishes and receives the events on the channel of the Redis server at addr.
+func NewRedisBus(addr string, channel string) *RedisBus {
+	return &RedisBus{
+		Addr:    addr,
+		Channel: channel,
+	}
+}
+
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func (b *RedisBus) dial(ctx context.Context) (net.Conn, *bufio.Reader, error) {
+	dialer := net.Dialer{Timeout: defaultRedisDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", b.Addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	if b.Password != "" {
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		_, err = redisCall(conn, reader, "AUTH", b.Password)
+		conn.SetDeadline(time.Time{})
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+
+	return conn, reader, nil
+}
+
+func (b *RedisBus) Publish(ctx context.Context, event InvalidationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn == nil {
+		b.conn, b.reader, err = b.dial(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultRedisDialTimeout)
+	}
+	b.conn.SetDeadline(deadline)
+
+	if _, err := redisCall(b.conn, b.reader, "PUBLISH", b.Channel, string(payload)); err != nil {
+		// the connection is in an unknown state, reconnect on the next publish
+		b.conn.Close()
+		b.conn, b.reader = nil, nil
+		return err
+	}
+
+	return nil
+}
+
+func (b *RedisBus) Subscribe(ctx context.Context, handle func(InvalidationEvent)) error {
+	conn, reader, err := b.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// closing the connection unblocks the read below
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := writeRedisCommand(conn, "SUBSCRIBE", b.Channel); err != nil {
+		return err
+	}
+
+	for {
+		reply, err := readRedisReply(reader)
+		if err != nil {
+			return err
+		}
+
+		message, ok := reply.([]any)
+		if !ok || len(message) != 3 || message[0] != "message" {
+			continue
+		}
+
+		payload, _ := message[2].(string)
+
+		var event InvalidationEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue
+		}
+
+		handle(event)
+	}
+}
+
+func redisCall(w io.Writer, r *bufio.Reader, args ...string) (any, error) {
+	if err := writeRedisCommand(w, args...); err != nil {
+		return nil, err
+	}
+
+	return readRedisReply(r)
+}
+
+// writeRedisCommand sends the command as an array of bulk strings.
+func writeRedisCommand(w io.Writer, args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// readRedisReply reads a reply as a string, an int64, nil or a slice of them, error replies are returned as errors.
+func readRedisReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+
+		return string(buf[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+
+		items := make([]any, 0, count)
+		for i := 0; i < count; i++ {
+			item, err := readRedisReply(r)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,46 @@
+package dbexplorer
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteRedisCommand(t *testing.T) {
+	var b strings.Builder
+	if err := writeRedisCommand(&b, "PUBLISH", "explorer", "{}"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "*3\r\n$7\r\nPUBLISH\r\n$8\r\nexplorer\r\n$2\r\n{}\r\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+}
+
+func TestReadRedisReply(t *testing.T) {
+	cases := map[string]any{
+		"+OK\r\n":          "OK",
+		":3\r\n":           int64(3),
+		"$-1\r\n":          nil,
+		"$4\r\na\r\nb\r\n": "a\r\nb",
+		"*3\r\n$7\r\nmessage\r\n$8\r\nexplorer\r\n$2\r\n{}\r\n": []any{"message", "explorer", "{}"},
+	}
+
+	for input, expected := range cases {
+		got, err := readRedisReply(bufio.NewReader(strings.NewReader(input)))
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%q: expected %#v, got %#v", input, expected, got)
+		}
+	}
+
+	_, err := readRedisReply(bufio.NewReader(strings.NewReader("-ERR unknown command\r\n")))
+	if err == nil || err.Error() != "redis: ERR unknown command" {
+		t.Errorf("expected redis error, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ItemReference counts the rows of a table referencing a record through one foreign key.
+// Link lists them, see handlerGetRelated.
+type ItemReference struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Count  int64  `json:"count"`
+	Link   string `json:"link"`
+}
+
+// itemReferences counts the rows of the exposed tables referencing the item, GET /{table}/{id}?include_refs=true.
+// Tables the principal can't read are left out.
+func (exp Explorer) itemReferences(ctx context.Context, principal *Principal, table string, id string, item map[string]any) ([]ItemReference, error) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	references := make([]ItemReference, 0)
+	for _, column := range schema.Columns {
+		for _, ref := range exp.referencingKeys(table, column.Name) {
+			if exp.authorize(ctx, principal, Action{Table: ref.Table, Op: OpRead}) != nil {
+				continue
+			}
+
+			reference := ItemReference{
+				Table:  ref.Table,
+				Column: ref.ForeignKey.Column,
+				Link: fmt.Sprintf("%s/%s/%s/%s?via=%s",
+					exp.prefix, table, url.PathEscape(id), ref.Table, url.QueryEscape(ref.ForeignKey.Column)),
+			}
+
+			if value := item[column.Name]; value != nil {
+				query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", exp.quote(ref.Table), exp.quoteColumn(ref.Table, ref.ForeignKey.Column))
+				if err := exp.db().QueryRow(query, value).Scan(&reference.Count); err != nil {
+					return nil, err
+				}
+			}
+
+			references = append(references, reference)
+		}
+	}
+
+	return references, nil
+}
@@ -0,0 +1,29 @@
+package dbexplorer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestItemReferences(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"users": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id"}}},
+		"orders": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id"}, {Name: "user_id"}}, ForeignKeys: []ForeignKey{
+			{Column: "user_id", RefTable: "users", RefColumn: "id"},
+		}},
+		"secrets": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id"}, {Name: "owner_id"}}, ForeignKeys: []ForeignKey{
+			{Column: "owner_id", RefTable: "users", RefColumn: "id"},
+		}},
+	})
+	exp.prefix = "/api"
+
+	principal := &Principal{Scopes: []string{"read:users", "read:orders"}}
+	refs, err := exp.itemReferences(context.Background(), principal, "users", "7", map[string]any{"id": nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(refs) != 1 || refs[0].Table != "orders" || refs[0].Column != "user_id" || refs[0].Link != "/api/users/7/orders?via=user_id" {
+		t.Errorf("unexpected references %+v", refs)
+	}
+}
@@ -0,0 +1,124 @@
+package dbexplorer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+const maxJournalEntrySize = 32 << 20
+
+// ConflictPolicy decides what Replay does with an entry the target rejects, e.g. an insert of a
+// record that already exists there.
+type ConflictPolicy int
+
+const (
+	// ConflictAbort stops at the first rejected entry.
+	ConflictAbort ConflictPolicy = iota
+	// ConflictSkip leaves the rejected entries out and goes on.
+	ConflictSkip
+)
+
+// ParseConflictPolicy parses "abort" or "skip".
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch name {
+	case "abort":
+		return ConflictAbort, nil
+	case "skip":
+		return ConflictSkip, nil
+	}
+
+	return 0, fmt.Errorf("unknown conflict policy %q", name)
+}
+
+// ReplayResult counts the entries of a replay.
+type ReplayResult struct {
+	Applied int
+	Skipped int
+}
+
+// ReplayError is a journal entry the target rejected.
+type ReplayError struct {
+	Line   int
+	Entry  JournalEntry
+	Status int
+	Body   string
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("journal line %d: %s %s: status %d: %s", e.Line, e.Entry.Method, e.Entry.Path, e.Status, e.Body)
+}
+
+// Replay sends the entries of a file journal to handler in order, usually an Explorer of another
+// database, e.g. to keep a staging copy up to date. The requests run as the journaled principal.
+// Entries answered with a status of 400 or above are handled by policy; onSkip, if set, is called
+// for the skipped ones.
+func Replay(ctx context.Context, journal io.Reader, handler http.Handler, policy ConflictPolicy, onSkip func(err *ReplayError)) (ReplayResult, error) {
+	var result ReplayResult
+
+	scanner := bufio.NewScanner(journal)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJournalEntrySize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return result, fmt.Errorf("journal line %d: %w", line, err)
+		}
+
+		status, body := replayEntry(ctx, handler, entry)
+		if status < http.StatusBadRequest {
+			result.Applied++
+			continue
+		}
+
+		err := &ReplayError{Line: line, Entry: entry, Status: status, Body: body}
+		if policy == ConflictAbort {
+			return result, err
+		}
+
+		result.Skipped++
+		if onSkip != nil {
+			onSkip(err)
+		}
+	}
+
+	return result, scanner.Err()
+}
+
+func replayEntry(ctx context.Context, handler http.Handler, entry JournalEntry) (int, string) {
+	body := []byte(entry.Body)
+	if entry.RawBody != nil {
+		body = entry.RawBody
+	}
+
+	target := entry.Path
+	if entry.Query != "" {
+		target += "?" + entry.Query
+	}
+
+	r := httptest.NewRequest(entry.Method, target, bytes.NewReader(body)).WithContext(ctx)
+	if entry.ContentType != "" {
+		r.Header.Set("Content-Type", entry.ContentType)
+	}
+	if entry.RequestID != "" {
+		r.Header.Set(requestIDHeader, entry.RequestID)
+	}
+	if entry.Principal != "" {
+		r = r.WithContext(ContextWithPrincipal(r.Context(), &Principal{Name: entry.Principal}))
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	return w.Code, w.Body.String()
+}
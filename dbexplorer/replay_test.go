@@ -0,0 +1,57 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	journal := `{"method":"PUT","path":"/items/","principal":"bob","content_type":"application/json","body":{"title":"a"}}
+{"method":"PUT","path":"/items/","query":"fail=1","body":{"title":"b"}}
+
+{"method":"DELETE","path":"/items/1","raw_body":"eA=="}
+`
+
+	var seen []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		principal := ""
+		if p := PrincipalFromContext(r.Context()); p != nil {
+			principal = p.Name
+		}
+		seen = append(seen, r.Method+" "+r.URL.String()+" "+string(body)+" "+principal)
+
+		if r.URL.Query().Has("fail") {
+			writeError(w, http.StatusConflict, errors.New("exists"))
+		}
+	})
+
+	if _, err := Replay(context.Background(), strings.NewReader(journal), handler, ConflictAbort, nil); err == nil {
+		t.Fatalf("expected abort on the rejected entry")
+	} else if replayErr, ok := err.(*ReplayError); !ok || replayErr.Line != 2 || replayErr.Status != http.StatusConflict {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	seen = nil
+	skipped := 0
+	result, err := Replay(context.Background(), strings.NewReader(journal), handler, ConflictSkip, func(*ReplayError) { skipped++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Applied != 2 || result.Skipped != 1 || skipped != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	expected := []string{`PUT /items/ {"title":"a"} bob`, `PUT /items/?fail=1 {"title":"b"} `, "DELETE /items/1 x "}
+	if strings.Join(seen, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected requests %q", seen)
+	}
+
+	if _, err := ParseConflictPolicy("merge"); err == nil {
+		t.Errorf("expected error for an unknown policy")
+	}
+}
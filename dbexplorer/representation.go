@@ -0,0 +1,49 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+)
+
+const preferRepresentation = "return=representation"
+
+// wantsRepresentation reports whether a write should answer with the written record, asked for
+// by ?return=representation or a Prefer: return=representation header.
+func wantsRepresentation(r *http.Request) bool {
+	if r.URL.Query().Get("return") == "representation" {
+		return true
+	}
+
+	for _, prefer := range r.Header.Values("Prefer") {
+		for _, preference := range strings.Split(prefer, ",") {
+			if strings.TrimSpace(preference) == preferRepresentation {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// writtenRecord returns the record after the write with the values generated by the database,
+// the row read for the audit log saves the SELECT. It is nil when the record is gone.
+func (exp Explorer) writtenRecord(ctx context.Context, w http.ResponseWriter, op writeOp, written writeResult) (map[string]any, error) {
+	w.Header().Set("Preference-Applied", preferRepresentation)
+
+	if written.Row != nil {
+		return written.Row, nil
+	}
+
+	if exp.backend != nil {
+		return exp.backendItem(ctx, op.Table, op.PrimaryKey, written.ID, nil)
+	}
+
+	record, err := exp.getItem(exp.db(), op.Table, op.PrimaryKey, written.ID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return record, err
+}
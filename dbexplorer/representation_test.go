@@ -0,0 +1,32 @@
+package dbexplorer
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWantsRepresentation(t *testing.T) {
+	cases := []struct {
+		target   string
+		prefer   string
+		expected bool
+	}{
+		{"/items/", "", false},
+		{"/items/?return=representation", "", true},
+		{"/items/?return=minimal", "", false},
+		{"/items/", "return=representation", true},
+		{"/items/", "handling=strict, return=representation", true},
+		{"/items/", "return=minimal", false},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest("PUT", c.target, nil)
+		if c.prefer != "" {
+			r.Header.Set("Prefer", c.prefer)
+		}
+
+		if got := wantsRepresentation(r); got != c.expected {
+			t.Errorf("%s, Prefer: %s: expected %v, got %v", c.target, c.prefer, c.expected, got)
+		}
+	}
+}
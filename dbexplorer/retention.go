@@ -0,0 +1,177 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetentionInterval   = time.Hour
+	defaultRetentionBatchSize  = 1000
+	defaultRetentionBatchDelay = 100 * time.Millisecond
+)
+
+// retentionPrincipal is recorded in the audit log for rows deleted by retention rules.
+var retentionPrincipal = &Principal{Name: "retention"}
+
+// RetentionRule deletes the rows of Table with Column older than MaxAge, e.g. logs older than 90 days.
+type RetentionRule struct {
+	Table  string
+	Column string
+	MaxAge time.Duration
+	// Interval between purges, an hour by default.
+	Interval time.Duration
+	// BatchSize rows are deleted per transaction with BatchDelay pauses in between to limit the load.
+	BatchSize  int
+	BatchDelay time.Duration
+}
+
+// WithRetentionRule purges old rows of the table periodically.
+func WithRetentionRule(rule RetentionRule) Option {
+	return func(exp *Explorer) error {
+		if rule.MaxAge <= 0 {
+			return fmt.Errorf("retention of %s: max age must be positive", rule.Table)
+		}
+		if rule.Interval == 0 {
+			rule.Interval = defaultRetentionInterval
+		}
+		if rule.BatchSize == 0 {
+			rule.BatchSize = defaultRetentionBatchSize
+		}
+		if rule.BatchDelay == 0 {
+			rule.BatchDelay = defaultRetentionBatchDelay
+		}
+
+		exp.retentionRules = append(exp.retentionRules, rule)
+		return nil
+	}
+}
+
+// initRetention checks the rules against the schema and schedules them.
+func (exp Explorer) initRetention() error {
+	if exp.readOnly && len(exp.retentionRules) > 0 {
+		return fmt.Errorf("retention rules are not available in read-only mode")
+	}
+
+	exp.metrics.describe("db_explorer_purged_rows_total", "Rows deleted by retention rules.")
+	exp.metrics.describe("db_explorer_purge_runs_total", "Runs of retention rules by status.")
+
+	for _, rule := range exp.retentionRules {
+		schema, err := exp.getTableSchema(rule.Table)
+		if err != nil {
+			return fmt.Errorf("retention of %s: unknown table", rule.Table)
+		}
+
+		column, ok := schema.Column(rule.Column)
+		if !ok || !isTimeDataType(column.DataType) {
+			return fmt.Errorf("retention of %s: %s must be a time column", rule.Table, rule.Column)
+		}
+
+		rule := rule
+		exp.scheduler.every("retention:"+rule.Table, rule.Interval, func() {
+			status := "succeeded"
+			if _, err := exp.purge(rule); err != nil {
+				status = "failed"
+			}
+			exp.metrics.add("db_explorer_purge_runs_total", 1, "table", rule.Table, "status", status)
+		})
+	}
+
+	return nil
+}
+
+// purge deletes the expired rows batch by batch and returns their number.
+func (exp Explorer) purge(rule RetentionRule) (int64, error) {
+	cutoff := time.Now().UTC().Add(-rule.MaxAge)
+
+	var total int64
+	for {
+		deleted, err := exp.purgeBatch(rule, cutoff)
+		total += deleted
+		exp.metrics.add("db_explorer_purged_rows_total", float64(deleted), "table", rule.Table)
+		if err != nil || deleted < int64(rule.BatchSize) {
+			return total, err
+		}
+
+		select {
+		case <-exp.scheduler.stopped():
+			return total, nil
+		case <-time.After(rule.BatchDelay):
+		}
+	}
+}
+
+func (exp Explorer) purgeBatch(rule RetentionRule, cutoff time.Time) (int64, error) {
+	schema, err := exp.getTableSchema(rule.Table)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	primaryKey := exp.quoteColumn(rule.Table, schema.PrimaryKey)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s < ? ORDER BY %s LIMIT %d FOR UPDATE",
+		primaryKey, exp.quote(rule.Table), exp.quoteColumn(rule.Table, rule.Column), primaryKey, rule.BatchSize)
+	rows, err := tx.Query(query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]any, 0, rule.BatchSize)
+	for rows.Next() {
+		var id any
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var deleted int64
+	if exp.auditLog {
+		// row by row, so every deleted row gets its audit entry
+		for _, id := range ids {
+			op := writeOp{
+				Op:         writeDelete,
+				Table:      rule.Table,
+				PrimaryKey: schema.PrimaryKey,
+				ID:         normalizeValue(id),
+			}
+
+			written, err := exp.executeWrite(context.Background(), tx, op, auditMeta{Principal: retentionPrincipal})
+			if err != nil {
+				return 0, err
+			}
+			deleted += written.Affected
+		}
+	} else {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+		result, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s)", exp.quote(rule.Table), primaryKey, placeholders), ids...)
+		if err != nil {
+			return 0, err
+		}
+
+		deleted, err = result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		exp.countAffected(rule.Table, writeDelete, deleted)
+	}
+
+	return deleted, tx.Commit()
+}
@@ -0,0 +1,92 @@
+package dbexplorer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var mariaDBVersionRe = regexp.MustCompile(`^(\d+)\.(\d+)`)
+
+// detectReturning reports whether the server takes INSERT/DELETE ... RETURNING *: PostgreSQL does,
+// MariaDB does from 10.5 on, MySQL 8 does not.
+func (exp Explorer) detectReturning() (bool, error) {
+	if exp.dialect.InsertReturning() {
+		return true, nil
+	}
+
+	var version string
+	if err := exp.db().QueryRow("SELECT VERSION()").Scan(&version); err != nil {
+		return false, err
+	}
+
+	return mariaDBSupportsReturning(version), nil
+}
+
+func mariaDBSupportsReturning(version string) bool {
+	if !strings.Contains(version, "MariaDB") {
+		return false
+	}
+
+	m := mariaDBVersionRe.FindStringSubmatch(version)
+	if m == nil {
+		return false
+	}
+
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+
+	return major > 10 || major == 10 && minor >= 5
+}
+
+// queryReturning runs a statement with RETURNING * and returns the row, nil when it matched none.
+func (exp Explorer) queryReturning(q queryer, query string, args ...any) (map[string]any, error) {
+	rows, err := q.Query(query+" RETURNING *", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	items, err := exp.scanRows(rows)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+
+	return items[0], nil
+}
+
+// createItemReturning inserts form and returns the created row in the same round-trip.
+func (exp Explorer) createItemReturning(q queryer, table string, form map[string]any) (map[string]any, error) {
+	builder, err := exp.queryBuilder(table)
+	if err != nil {
+		return nil, err
+	}
+
+	form, err = exp.generateID(table, form)
+	if err != nil {
+		return nil, err
+	}
+
+	query, values, err := builder.insert(form)
+	if err != nil {
+		return nil, err
+	}
+
+	return exp.queryReturning(q, query, values...)
+}
+
+// deleteItemReturning deletes the record and returns it, nil when there was none.
+func (exp Explorer) deleteItemReturning(q queryer, table string, pkName string, pkValue any) (map[string]any, error) {
+	builder, err := exp.queryBuilder(table)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := builder.deleteByKey(pkName)
+	if err != nil {
+		return nil, err
+	}
+
+	return exp.queryReturning(q, query, pkValue)
+}
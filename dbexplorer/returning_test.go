@@ -0,0 +1,18 @@
+package dbexplorer
+
+import "testing"
+
+func TestMariaDBSupportsReturning(t *testing.T) {
+	for version, expected := range map[string]bool{
+		"10.6.12-MariaDB-1:10.6.12+maria~ubu2004": true,
+		"10.5.0-MariaDB":  true,
+		"11.0.2-MariaDB":  true,
+		"10.4.28-MariaDB": false,
+		"8.0.33":          false,
+		"5.7.42-log":      false,
+	} {
+		if got := mariaDBSupportsReturning(version); got != expected {
+			t.Errorf("%s: expected %v, got %v", version, expected, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package dbexplorer
+
+import (
+	"sync"
+	"time"
+)
+
+type scheduledTask struct {
+	Name     string
+	Interval time.Duration
+	Run      func()
+	// Loop is run once instead of Run for long running tasks, it returns when stop is closed.
+	Loop func(stop <-chan struct{})
+}
+
+// scheduler runs tasks periodically in background goroutines until it is stopped.
+type scheduler struct {
+	mu      sync.Mutex
+	tasks   []scheduledTask
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+	// gate skips the periodic tasks while it returns false, e.g. on instances that are not the leader.
+	gate func() bool
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		stop: make(chan struct{}),
+	}
+}
+
+func (s *scheduler) every(name string, interval time.Duration, run func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tasks = append(s.tasks, scheduledTask{Name: name, Interval: interval, Run: run})
+}
+
+// background runs the loop in its own goroutine from the start to the shutdown of the scheduler.
+func (s *scheduler) background(name string, loop func(stop <-chan struct{})) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tasks = append(s.tasks, scheduledTask{Name: name, Loop: loop})
+}
+
+func (s *scheduler) start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, task := range s.tasks {
+		task := task
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+
+			if task.Loop != nil {
+				task.Loop(s.stop)
+				return
+			}
+
+			ticker := time.NewTicker(task.Interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-s.stop:
+					return
+				case <-ticker.C:
+					if s.gate == nil || s.gate() {
+						task.Run()
+					}
+				}
+			}
+		}()
+	}
+}
+
+// shutdown stops the tasks and waits for the running ones to finish.
+func (s *scheduler) shutdown() {
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// stopped is closed when the scheduler is shut down, long tasks check it between steps.
+func (s *scheduler) stopped() <-chan struct{} {
+	return s.stop
+}
+
+// Close stops the background tasks of the explorer.
+func (exp Explorer) Close() error {
+	exp.scheduler.shutdown()
+	return nil
+}
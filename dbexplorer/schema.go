@@ -0,0 +1,244 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ColumnInfo is the column metadata from INFORMATION_SCHEMA that sql.ColumnType doesn't provide.
+type ColumnInfo struct {
+	Name       string   `json:"name"`
+	DataType   string   `json:"data_type"`
+	ColumnType string   `json:"column_type"`
+	MaxLength  *int64   `json:"max_length"`
+	Nullable   bool     `json:"nullable"`
+	Default    *string  `json:"default"`
+	Key        string   `json:"key"`
+	Extra      string   `json:"extra"`
+	Comment    string   `json:"comment"`
+	EnumValues []string `json:"enum_values,omitempty"`
+}
+
+type ForeignKey struct {
+	Name      string `json:"name"`
+	Column    string `json:"column"`
+	RefTable  string `json:"ref_table"`
+	RefColumn string `json:"ref_column"`
+}
+
+type TableSchema struct {
+	Comment     string       `json:"comment"`
+	Columns     []ColumnInfo `json:"columns"`
+	PrimaryKey  string       `json:"primary_key"`
+	ForeignKeys []ForeignKey `json:"foreign_keys"`
+	Partitions  []Partition  `json:"partitions,omitempty"`
+}
+
+func (s *TableSchema) Column(name string) (ColumnInfo, bool) {
+	for _, c := range s.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+
+	return ColumnInfo{}, false
+}
+
+func (s *TableSchema) ForeignKey(column string) (ForeignKey, bool) {
+	for _, fk := range s.ForeignKeys {
+		if fk.Column == column {
+			return fk, true
+		}
+	}
+
+	return ForeignKey{}, false
+}
+
+// parseEnumValues parses a column type like enum('a','b”c') into its values.
+func parseEnumValues(columnType string) []string {
+	lower := strings.ToLower(columnType)
+	if !strings.HasPrefix(lower, "enum(") && !strings.HasPrefix(lower, "set(") {
+		return nil
+	}
+
+	body := columnType[strings.Index(columnType, "(")+1 : strings.LastIndex(columnType, ")")]
+
+	values := make([]string, 0)
+	var (
+		current strings.Builder
+		quoted  bool
+	)
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+		switch {
+		case ch == '\'' && quoted && i+1 < len(body) && body[i+1] == '\'':
+			current.WriteByte('\'')
+			i++
+		case ch == '\'':
+			quoted = !quoted
+			if !quoted {
+				values = append(values, current.String())
+				current.Reset()
+			}
+		case quoted:
+			current.WriteByte(ch)
+		}
+	}
+
+	return values
+}
+
+func (exp Explorer) loadTableSchema(table string) (*TableSchema, error) {
+	schema := &TableSchema{
+		Columns:     make([]ColumnInfo, 0),
+		ForeignKeys: make([]ForeignKey, 0),
+	}
+
+	rows, err := exp.db().Query(exp.dialect.ColumnsQuery(), exp.dbName(table))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			column    ColumnInfo
+			maxLength sql.NullInt64
+			nullable  string
+			def       sql.NullString
+		)
+
+		err := rows.Scan(&column.Name, &column.DataType, &column.ColumnType, &maxLength, &nullable,
+			&def, &column.Key, &column.Extra, &column.Comment)
+		if err != nil {
+			return nil, err
+		}
+
+		column.DataType = strings.ToLower(column.DataType)
+		column.Nullable = nullable == "YES"
+		if maxLength.Valid {
+			column.MaxLength = &maxLength.Int64
+		}
+		if def.Valid {
+			column.Default = &def.String
+		}
+		column.EnumValues = parseEnumValues(column.ColumnType)
+
+		if column.Key == "PRI" && schema.PrimaryKey == "" {
+			schema.PrimaryKey = column.Name
+		}
+
+		schema.Columns = append(schema.Columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fkRows, err := exp.db().Query(exp.dialect.ForeignKeysQuery(), exp.dbName(table))
+	if err != nil {
+		return nil, err
+	}
+
+	defer fkRows.Close()
+
+	for fkRows.Next() {
+		var fk ForeignKey
+		if err := fkRows.Scan(&fk.Name, &fk.Column, &fk.RefTable, &fk.RefColumn); err != nil {
+			return nil, err
+		}
+
+		schema.ForeignKeys = append(schema.ForeignKeys, fk)
+	}
+
+	if err := fkRows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := exp.applySchemaRules(table, schema); err != nil {
+		return nil, err
+	}
+
+	if column, ok := exp.primaryKeys[table]; ok {
+		if _, ok := schema.Column(column); !ok {
+			return nil, fmt.Errorf("primary key override: table %s has no column %s", table, column)
+		}
+		schema.PrimaryKey = column
+	}
+
+	schema.Partitions, err = exp.loadPartitions(table)
+	if err != nil {
+		return nil, err
+	}
+
+	err = exp.db().QueryRow(exp.dialect.TableCommentQuery(), exp.dbName(table)).Scan(&schema.Comment)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+func (exp Explorer) getTableSchema(table string) (*TableSchema, error) {
+	schema, ok := exp.tableSchemas()[table]
+	if !ok {
+		return nil, fmt.Errorf("table=%s doesnt have schema", table)
+	}
+
+	return schema, nil
+}
+
+// isNullable asks the driver first and falls back to INFORMATION_SCHEMA for drivers like lib/pq
+// that don't report nullability of result columns.
+func (exp Explorer) isNullable(table string, c *sql.ColumnType) (bool, error) {
+	if nullable, ok := c.Nullable(); ok {
+		return nullable, nil
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return false, err
+	}
+
+	info, ok := schema.Column(exp.apiColumn(c.Name()))
+	if !ok {
+		return false, fmt.Errorf("db driver does not support nullable")
+	}
+
+	return info.Nullable, nil
+}
+
+type TableDictionary struct {
+	Name string `json:"name"`
+	*TableSchema
+}
+
+type GetSchemaResponse struct {
+	Tables []TableDictionary `json:"tables"`
+}
+
+// handlerGetSchema returns the schemas of every table the principal can read in one document.
+func (exp Explorer) handlerGetSchema(w http.ResponseWriter, r *http.Request) {
+	principal := PrincipalFromContext(r.Context())
+
+	tableNames := exp.tableNames()
+	tables := make([]TableDictionary, 0, len(tableNames))
+	for _, table := range tableNames {
+		if exp.authorize(r.Context(), principal, Action{Table: table, Op: OpRead}) != nil {
+			continue
+		}
+
+		schema, err := exp.getTableSchema(table)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		tables = append(tables, TableDictionary{Name: table, TableSchema: schema})
+	}
+
+	writeResponse(w, GetSchemaResponse{Tables: tables})
+}
@@ -0,0 +1,239 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// schemaCache holds the tables the explorer serves. It is shared by all copies of the explorer and its
+// maps are replaced as a whole on refresh, so readers may keep what they got without holding the lock.
+type schemaCache struct {
+	mu      sync.RWMutex
+	names   []string
+	columns map[string][]*sql.ColumnType
+	schemas map[string]*TableSchema
+	graphql *graphqlSchema
+
+	identifiers *identifiers
+}
+
+// newSchemaCache returns a cache of the given schemas, names defaults to the sorted tables of schemas.
+func newSchemaCache(names []string, schemas map[string]*TableSchema) *schemaCache {
+	if schemas == nil {
+		schemas = make(map[string]*TableSchema)
+	}
+
+	if names == nil {
+		names = make([]string, 0, len(schemas))
+		for name := range schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	return &schemaCache{
+		names:   names,
+		columns: make(map[string][]*sql.ColumnType),
+		schemas: schemas,
+	}
+}
+
+type RefreshSchemaResponse struct {
+	Tables []string `json:"tables"`
+}
+
+// WithSchemaRefresh reloads the tables every interval on every instance, so tables created or altered
+// after the start are served without a restart.
+func WithSchemaRefresh(interval time.Duration) Option {
+	return func(exp *Explorer) error {
+		if interval <= 0 {
+			return fmt.Errorf("schema refresh: interval must be positive")
+		}
+
+		exp.schemaRefresh = interval
+		return nil
+	}
+}
+
+func (exp Explorer) tableNames() []string {
+	exp.schema.mu.RLock()
+	defer exp.schema.mu.RUnlock()
+
+	return exp.schema.names
+}
+
+func (exp Explorer) tableSchemas() map[string]*TableSchema {
+	exp.schema.mu.RLock()
+	defer exp.schema.mu.RUnlock()
+
+	return exp.schema.schemas
+}
+
+func (exp Explorer) graphqlSchema() *graphqlSchema {
+	exp.schema.mu.RLock()
+	defer exp.schema.mu.RUnlock()
+
+	return exp.schema.graphql
+}
+
+// RefreshSchema reloads the tables, their columns and schemas from the database, or the backend of
+// WithBackend, and swaps them in at once.
+func (exp Explorer) RefreshSchema() error {
+	load := exp.loadSchemaCache
+	if exp.backend != nil {
+		load = exp.loadBackendSchema
+	}
+
+	next, err := load()
+	if err != nil {
+		return err
+	}
+
+	exp.schema.mu.Lock()
+	defer exp.schema.mu.Unlock()
+
+	exp.schema.names = next.names
+	exp.schema.columns = next.columns
+	exp.schema.schemas = next.schemas
+	exp.schema.graphql = next.graphql
+	exp.schema.identifiers = next.identifiers
+
+	return nil
+}
+
+// loadSchemaCache reads the tables, their columns and schemas from the database under their API names.
+func (exp Explorer) loadSchemaCache() (*schemaCache, error) {
+	tables, err := exp.getTableNames()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := newIdentifiers()
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = exp.schemaRules.tableName(table)
+		if err := ids.tables.add(table, names[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	next := newSchemaCache(names, nil)
+	next.identifiers = ids
+
+	// the tables are loaded through the new names, which the current cache may not know yet
+	view := exp
+	view.schema = next
+
+	for _, table := range names {
+		columns, err := view.getColumnTypes(table)
+		if err != nil {
+			return nil, err
+		}
+
+		visible := make([]*sql.ColumnType, 0, len(columns))
+		for _, column := range columns {
+			if exp.schemaRules.columnExcluded(column.Name()) {
+				continue
+			}
+
+			if err := ids.addColumn(table, column.Name(), exp.schemaRules.columnName(column.Name())); err != nil {
+				return nil, err
+			}
+			visible = append(visible, column)
+		}
+		next.columns[table] = visible
+
+		schema, err := view.loadTableSchema(table)
+		if err != nil {
+			return nil, err
+		}
+		next.schemas[table] = schema
+	}
+
+	next.graphql = view.buildGraphQLSchema()
+
+	return next, nil
+}
+
+// loadBackendSchema reads the tables and their schemas from the backend of WithBackend. The backend
+// names the tables and columns as the API does, so schema rules don't apply.
+func (exp Explorer) loadBackendSchema() (*schemaCache, error) {
+	ctx := context.Background()
+
+	tables, err := exp.backend.ListTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]*TableSchema, len(tables))
+	for _, table := range tables {
+		schema, err := exp.backend.Schema(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		schemas[table] = schema
+	}
+
+	next := newSchemaCache(tables, schemas)
+
+	view := exp
+	view.schema = next
+	next.graphql = view.buildGraphQLSchema()
+
+	return next, nil
+}
+
+// initSchemaRefresh refreshes the cache on schema invalidation events, e.g. from the schema changelog
+// or another instance, and every WithSchemaRefresh interval.
+func (exp Explorer) initSchemaRefresh() {
+	exp.metrics.describe("db_explorer_schema_refreshes_failed_total", "Failed reloads of the schema cache.")
+
+	refresh := func() {
+		if err := exp.RefreshSchema(); err != nil {
+			exp.metrics.add("db_explorer_schema_refreshes_failed_total", 1)
+		}
+	}
+
+	exp.OnInvalidate(func(event InvalidationEvent) {
+		if event.Type == InvalidateSchema {
+			refresh()
+		}
+	})
+
+	if exp.schemaRefresh == 0 {
+		return
+	}
+
+	// the cache is local to the instance, so unlike the periodic tasks this runs on followers too
+	exp.scheduler.background("schema-refresh", func(stop <-chan struct{}) {
+		ticker := time.NewTicker(exp.schemaRefresh)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	})
+}
+
+func (exp Explorer) handlerRefreshSchema(w http.ResponseWriter, r *http.Request) {
+	if exp.adminRestricted() && !exp.requireAdmin(w, r) {
+		return
+	}
+
+	if err := exp.RefreshSchema(); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, RefreshSchemaResponse{Tables: exp.tableNames()})
+}
@@ -0,0 +1,22 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaCache(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{"users": {}, "items": {}})
+
+	if names := exp.tableNames(); !reflect.DeepEqual(names, []string{"items", "users"}) {
+		t.Errorf("unexpected table names %v", names)
+	}
+
+	if !exp.isValidTableName("users") || exp.isValidTableName("orders") {
+		t.Errorf("unexpected table validation")
+	}
+
+	if _, err := exp.getTableSchema("orders"); err == nil {
+		t.Errorf("expected error for an unknown table")
+	}
+}
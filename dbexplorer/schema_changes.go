@@ -0,0 +1,372 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+)
+
+const (
+	schemaChangesTable  = metaTablePrefix + "schema_changes"
+	schemaSnapshotTable = metaTablePrefix + "schema_snapshot"
+)
+
+const (
+	schemaTableAdded    = "table_added"
+	schemaTableRemoved  = "table_removed"
+	schemaColumnAdded   = "column_added"
+	schemaColumnRemoved = "column_removed"
+	schemaColumnAltered = "column_altered"
+)
+
+const (
+	schemaSourceAdmin    = "admin"
+	schemaSourceExternal = "external"
+)
+
+// SchemaChange is an entry of the schema changelog, Details holds the column definitions before and after.
+type SchemaChange struct {
+	ID        int64           `json:"id"`
+	Table     string          `json:"table"`
+	Column    *string         `json:"column"`
+	Change    string          `json:"change"`
+	Source    string          `json:"source"`
+	Principal *string         `json:"principal"`
+	Details   json.RawMessage `json:"details"`
+	CreatedAt string          `json:"created_at"`
+}
+
+type GetSchemaChangesResponse struct {
+	Changes []SchemaChange `json:"changes"`
+}
+
+// WithSchemaChangelog records the DDL run by the explorer and the schema changes made by others into the
+// _explorer_schema_changes table, queryable at GET /_schema/changes. External changes are detected at startup
+// and every interval by comparing the schema with the snapshot taken at the previous check.
+func WithSchemaChangelog(interval time.Duration) Option {
+	return func(exp *Explorer) error {
+		if interval <= 0 {
+			return fmt.Errorf("schema changelog: interval must be positive")
+		}
+
+		exp.metaTables = append(exp.metaTables, metaTable{
+			Name: schemaChangesTable,
+			Columns: []metaColumn{
+				{Name: "id", Kind: "serial"},
+				{Name: "table_name", Kind: "string"},
+				{Name: "column_name", Kind: "string", Nullable: true},
+				{Name: "change_type", Kind: "string"},
+				{Name: "source", Kind: "string"},
+				{Name: "principal", Kind: "string", Nullable: true},
+				{Name: "details", Kind: "text", Nullable: true},
+				{Name: "created_at", Kind: "time"},
+			},
+			PrimaryKey: "id",
+			Indexes:    [][]string{{"table_name", "id"}},
+		}, metaTable{
+			Name: schemaSnapshotTable,
+			Columns: []metaColumn{
+				{Name: "table_name", Kind: "string"},
+				{Name: "definition", Kind: "text"},
+			},
+			PrimaryKey: "table_name",
+		})
+		exp.schemaChangelog = interval
+		return nil
+	}
+}
+
+// initSchemaChangelog checks the schema against the last snapshot and schedules the periodic checks.
+func (exp Explorer) initSchemaChangelog() error {
+	exp.metrics.describe("db_explorer_schema_checks_failed_total", "Failed checks for external schema changes.")
+
+	if exp.schemaChangelog == 0 {
+		return nil
+	}
+
+	if err := exp.detectSchemaChanges(exp.tableSchemas()); err != nil {
+		return fmt.Errorf("schema changelog: %w", err)
+	}
+
+	exp.scheduler.every("schema-changelog", exp.schemaChangelog, func() {
+		current, err := exp.readSchemas()
+		if err == nil {
+			err = exp.detectSchemaChanges(current)
+		}
+		if err != nil {
+			exp.metrics.add("db_explorer_schema_checks_failed_total", 1)
+		}
+	})
+
+	return nil
+}
+
+// readSchemas loads the current schema of every table from the database without touching the cached one.
+func (exp Explorer) readSchemas() (map[string]*TableSchema, error) {
+	next, err := exp.loadSchemaCache()
+	if err != nil {
+		return nil, err
+	}
+
+	return next.schemas, nil
+}
+
+func (exp Explorer) loadSchemaSnapshot() (map[string][]ColumnInfo, error) {
+	rows, err := exp.db().Query(`SELECT table_name, definition FROM ` + schemaSnapshotTable)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	snapshot := make(map[string][]ColumnInfo)
+	for rows.Next() {
+		var table, definition string
+		if err := rows.Scan(&table, &definition); err != nil {
+			return nil, err
+		}
+
+		var columns []ColumnInfo
+		if err := json.Unmarshal([]byte(definition), &columns); err != nil {
+			return nil, err
+		}
+		snapshot[table] = columns
+	}
+
+	return snapshot, rows.Err()
+}
+
+// diffSchemas lists the changes from the snapshot to the current schema, tables and columns in name order.
+func diffSchemas(snapshot map[string][]ColumnInfo, current map[string]*TableSchema) []SchemaChange {
+	tables := make([]string, 0, len(snapshot)+len(current))
+	for table := range snapshot {
+		tables = append(tables, table)
+	}
+	for table := range current {
+		if _, ok := snapshot[table]; !ok {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+
+	changes := make([]SchemaChange, 0)
+	for _, table := range tables {
+		before, existed := snapshot[table]
+		schema, exists := current[table]
+		switch {
+		case !existed:
+			changes = append(changes, SchemaChange{Table: table, Change: schemaTableAdded})
+			continue
+		case !exists:
+			changes = append(changes, SchemaChange{Table: table, Change: schemaTableRemoved})
+			continue
+		}
+
+		beforeColumns := make(map[string]ColumnInfo, len(before))
+		for _, c := range before {
+			beforeColumns[c.Name] = c
+		}
+		for _, c := range schema.Columns {
+			column := c.Name
+			old, ok := beforeColumns[c.Name]
+			delete(beforeColumns, c.Name)
+			switch {
+			case !ok:
+				changes = append(changes, SchemaChange{Table: table, Column: &column, Change: schemaColumnAdded, Details: columnDetails(nil, &c)})
+			case !reflect.DeepEqual(old, c):
+				changes = append(changes, SchemaChange{Table: table, Column: &column, Change: schemaColumnAltered, Details: columnDetails(&old, &c)})
+			}
+		}
+
+		removed := make([]string, 0, len(beforeColumns))
+		for name := range beforeColumns {
+			removed = append(removed, name)
+		}
+		sort.Strings(removed)
+		for _, name := range removed {
+			column, old := name, beforeColumns[name]
+			changes = append(changes, SchemaChange{Table: table, Column: &column, Change: schemaColumnRemoved, Details: columnDetails(&old, nil)})
+		}
+	}
+
+	return changes
+}
+
+func columnDetails(before *ColumnInfo, after *ColumnInfo) json.RawMessage {
+	details, _ := json.Marshal(map[string]*ColumnInfo{"before": before, "after": after})
+	return details
+}
+
+// detectSchemaChanges records the differences to the snapshot as external changes and takes a new snapshot.
+// The first check only takes the snapshot.
+func (exp Explorer) detectSchemaChanges(current map[string]*TableSchema) error {
+	snapshot, err := exp.loadSchemaSnapshot()
+	if err != nil {
+		return err
+	}
+
+	changes := diffSchemas(snapshot, current)
+	if len(snapshot) == 0 {
+		changes = nil
+	} else if len(changes) == 0 {
+		return nil
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, change := range changes {
+		change.Source = schemaSourceExternal
+		if err := exp.writeSchemaChange(tx, change); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM ` + schemaSnapshotTable); err != nil {
+		return err
+	}
+	for table, schema := range current {
+		if err := exp.writeSchemaSnapshot(tx, table, schema); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	invalidated := make(map[string]bool)
+	for _, change := range changes {
+		if !invalidated[change.Table] {
+			invalidated[change.Table] = true
+			exp.Invalidate(InvalidationEvent{Type: InvalidateSchema, Table: change.Table})
+		}
+	}
+
+	return nil
+}
+
+func (exp Explorer) writeSchemaChange(q queryer, change SchemaChange) error {
+	var details any
+	if change.Details != nil {
+		details = string(change.Details)
+	}
+
+	_, err := q.Exec(`INSERT INTO `+schemaChangesTable+` (table_name, column_name, change_type, source, principal, details, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		change.Table, change.Column, change.Change, change.Source, change.Principal, details, time.Now().UTC())
+
+	return err
+}
+
+func (exp Explorer) writeSchemaSnapshot(q queryer, table string, schema *TableSchema) error {
+	definition, err := json.Marshal(schema.Columns)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(`INSERT INTO `+schemaSnapshotTable+` (table_name, definition) VALUES (?, ?)`, table, string(definition))
+	return err
+}
+
+// recordDDL logs a table created by the explorer itself on behalf of the principal and adds it to the snapshot,
+// so that the next check does not report it as an external change.
+func (exp Explorer) recordDDL(principal *Principal, table string) error {
+	if exp.schemaChangelog == 0 {
+		return nil
+	}
+
+	schema, err := exp.loadTableSchema(table)
+	if err != nil {
+		return err
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	change := SchemaChange{Table: table, Change: schemaTableAdded, Source: schemaSourceAdmin}
+	if principal != nil {
+		change.Principal = &principal.Name
+	}
+	if err := exp.writeSchemaChange(tx, change); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM `+schemaSnapshotTable+` WHERE table_name = ?`, table); err != nil {
+		return err
+	}
+	if err := exp.writeSchemaSnapshot(tx, table, schema); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (exp Explorer) handlerGetSchemaChanges(w http.ResponseWriter, r *http.Request) {
+	if exp.schemaChangelog == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("schema changelog is not enabled"))
+		return
+	}
+
+	if exp.adminRestricted() && !exp.requireAdmin(w, r) {
+		return
+	}
+
+	query := `SELECT id, table_name, column_name, change_type, source, principal, details, created_at FROM ` + schemaChangesTable
+	args := make([]any, 0)
+	if table := r.URL.Query().Get("table"); table != "" {
+		query += ` WHERE table_name = ?`
+		args = append(args, table)
+	}
+
+	pagination, err := exp.getPagination(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	query += ` ORDER BY id DESC LIMIT ? OFFSET ?`
+	args = append(args, pagination.Limit, pagination.Offset)
+
+	rows, err := exp.db().Query(query, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer rows.Close()
+
+	changes := make([]SchemaChange, 0)
+	for rows.Next() {
+		var (
+			change  SchemaChange
+			details sql.NullString
+		)
+		err := rows.Scan(&change.ID, &change.Table, &change.Column, &change.Change, &change.Source, &change.Principal,
+			&details, &change.CreatedAt)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		change.Details = json.RawMessage("null")
+		if details.Valid {
+			change.Details = json.RawMessage(details.String)
+		}
+
+		changes = append(changes, change)
+	}
+
+	writeResponse(w, GetSchemaChangesResponse{Changes: changes})
+}
@@ -0,0 +1,51 @@
+package dbexplorer
+
+import "testing"
+
+func TestDiffSchemas(t *testing.T) {
+	snapshot := map[string][]ColumnInfo{
+		"items": {
+			{Name: "id", DataType: "int"},
+			{Name: "title", DataType: "varchar"},
+			{Name: "legacy", DataType: "text"},
+		},
+		"old": {{Name: "id", DataType: "int"}},
+	}
+	current := map[string]*TableSchema{
+		"items": {Columns: []ColumnInfo{
+			{Name: "id", DataType: "int"},
+			{Name: "title", DataType: "text"},
+			{Name: "updated", DataType: "datetime", Nullable: true},
+		}},
+		"users": {Columns: []ColumnInfo{{Name: "id", DataType: "int"}}},
+	}
+
+	changes := diffSchemas(snapshot, current)
+
+	expected := []string{
+		"items.title column_altered",
+		"items.updated column_added",
+		"items.legacy column_removed",
+		"old table_removed",
+		"users table_added",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changes)
+	}
+
+	for i, change := range changes {
+		got := change.Table
+		if change.Column != nil {
+			got += "." + *change.Column
+		}
+		got += " " + change.Change
+
+		if got != expected[i] {
+			t.Errorf("change %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+
+	if string(changes[0].Details) == "" || string(changes[0].Details) == "null" {
+		t.Errorf("expected details of the altered column")
+	}
+}
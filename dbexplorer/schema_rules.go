@@ -0,0 +1,271 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RenameRule renames the tables or columns whose database name matches Pattern, replacing the match
+// with Replacement as regexp.ReplaceAllString does, e.g. {`^tbl_`, ""} strips the prefix.
+type RenameRule struct {
+	Pattern     string
+	Replacement string
+}
+
+// SchemaRules shape the API over a legacy schema without touching the database. Patterns are regular
+// expressions matched against the database names, column rules apply to the columns of every table.
+type SchemaRules struct {
+	ExcludeTables  []string
+	ExcludeColumns []string
+	RenameTables   []RenameRule
+	RenameColumns  []RenameRule
+}
+
+type renameRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+type schemaRules struct {
+	excludeTables  []*regexp.Regexp
+	excludeColumns []*regexp.Regexp
+	renameTables   []renameRule
+	renameColumns  []renameRule
+}
+
+// WithSchemaRules hides and renames tables and columns at the API layer, e.g. to strip a tbl_ prefix
+// or hide *_tmp tables. Requests use the new names only, the raw SQL of /_query keeps the database ones.
+// Rules renaming two tables, or two columns of one table, to the same name fail the schema load.
+func WithSchemaRules(rules SchemaRules) Option {
+	return func(exp *Explorer) error {
+		compiled := &schemaRules{}
+
+		var err error
+		if compiled.excludeTables, err = compilePatterns(rules.ExcludeTables); err != nil {
+			return err
+		}
+		if compiled.excludeColumns, err = compilePatterns(rules.ExcludeColumns); err != nil {
+			return err
+		}
+		if compiled.renameTables, err = compileRenames(rules.RenameTables); err != nil {
+			return err
+		}
+		if compiled.renameColumns, err = compileRenames(rules.RenameColumns); err != nil {
+			return err
+		}
+
+		exp.schemaRules = compiled
+		return nil
+	}
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("schema rules: %w", err)
+		}
+		res = append(res, re)
+	}
+
+	return res, nil
+}
+
+func compileRenames(rules []RenameRule) ([]renameRule, error) {
+	res := make([]renameRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("schema rules: %w", err)
+		}
+		res = append(res, renameRule{pattern: re, replacement: rule.Replacement})
+	}
+
+	return res, nil
+}
+
+func matchAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func rename(rules []renameRule, name string) string {
+	for _, rule := range rules {
+		name = rule.pattern.ReplaceAllString(name, rule.replacement)
+	}
+
+	return name
+}
+
+func (r *schemaRules) tableExcluded(table string) bool {
+	return r != nil && matchAny(r.excludeTables, table)
+}
+
+func (r *schemaRules) columnExcluded(column string) bool {
+	return r != nil && matchAny(r.excludeColumns, column)
+}
+
+func (r *schemaRules) tableName(table string) string {
+	if r == nil {
+		return table
+	}
+
+	return rename(r.renameTables, table)
+}
+
+func (r *schemaRules) columnName(column string) string {
+	if r == nil {
+		return column
+	}
+
+	return rename(r.renameColumns, column)
+}
+
+// nameMap maps database names to API names and back.
+type nameMap struct {
+	toAPI map[string]string
+	toDB  map[string]string
+}
+
+func newNameMap() *nameMap {
+	return &nameMap{toAPI: make(map[string]string), toDB: make(map[string]string)}
+}
+
+func (m *nameMap) add(dbName, apiName string) error {
+	if other, ok := m.toDB[apiName]; ok && other != dbName {
+		return fmt.Errorf("schema rules: %s and %s are both renamed to %s", other, dbName, apiName)
+	}
+	if other, ok := m.toAPI[dbName]; ok && other != apiName {
+		return fmt.Errorf("schema rules: %s is renamed to both %s and %s", dbName, other, apiName)
+	}
+
+	m.toAPI[dbName] = apiName
+	m.toDB[apiName] = dbName
+	return nil
+}
+
+func (m *nameMap) lookup(apiName string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+
+	dbName, ok := m.toDB[apiName]
+	return dbName, ok
+}
+
+// identifiers maps the names of the served tables, and the names of the columns of each table keyed by
+// its API name, so a column may share its name with a table or with a renamed column of another table.
+type identifiers struct {
+	tables  *nameMap
+	columns map[string]*nameMap
+}
+
+func newIdentifiers() *identifiers {
+	return &identifiers{tables: newNameMap(), columns: make(map[string]*nameMap)}
+}
+
+func (ids *identifiers) addColumn(table, dbName, apiName string) error {
+	columns, ok := ids.columns[table]
+	if !ok {
+		columns = newNameMap()
+		ids.columns[table] = columns
+	}
+
+	if err := columns.add(dbName, apiName); err != nil {
+		return fmt.Errorf("table %s: %w", table, err)
+	}
+	return nil
+}
+
+func (exp Explorer) identifiers() *identifiers {
+	exp.schema.mu.RLock()
+	defer exp.schema.mu.RUnlock()
+
+	return exp.schema.identifiers
+}
+
+// dbName returns the database name of a table, names the rules don't know pass as they are.
+func (exp Explorer) dbName(table string) string {
+	if exp.schemaRules == nil {
+		return table
+	}
+
+	if ids := exp.identifiers(); ids != nil {
+		if dbName, ok := ids.tables.toDB[table]; ok {
+			return dbName
+		}
+	}
+
+	return table
+}
+
+// dbColumn returns the database name of a column of table, names the rules don't know pass as they are.
+func (exp Explorer) dbColumn(table, column string) string {
+	if exp.schemaRules == nil {
+		return column
+	}
+
+	if ids := exp.identifiers(); ids != nil {
+		if dbName, ok := ids.columns[table].lookup(column); ok {
+			return dbName
+		}
+	}
+
+	return column
+}
+
+// apiColumn returns the API name of a database column. The rename rules apply to the columns of every
+// table alike, so unlike the way back it doesn't depend on the table.
+func (exp Explorer) apiColumn(column string) string {
+	return exp.schemaRules.columnName(column)
+}
+
+// hiddenColumn reports a database column excluded by the rules, which SELECT * still returns.
+func (exp Explorer) hiddenColumn(column string) bool {
+	return exp.schemaRules.columnExcluded(column)
+}
+
+// applySchemaRules drops the excluded columns and foreign keys of a schema loaded from the database
+// and renames the rest.
+func (exp Explorer) applySchemaRules(table string, schema *TableSchema) error {
+	rules := exp.schemaRules
+	if rules == nil {
+		return nil
+	}
+
+	columns := make([]ColumnInfo, 0, len(schema.Columns))
+	for _, column := range schema.Columns {
+		if rules.columnExcluded(column.Name) {
+			if column.Name == schema.PrimaryKey {
+				return fmt.Errorf("schema rules: primary key %s of table %s is excluded", column.Name, table)
+			}
+			continue
+		}
+
+		column.Name = rules.columnName(column.Name)
+		columns = append(columns, column)
+	}
+	schema.Columns = columns
+	schema.PrimaryKey = rules.columnName(schema.PrimaryKey)
+
+	foreignKeys := make([]ForeignKey, 0, len(schema.ForeignKeys))
+	for _, fk := range schema.ForeignKeys {
+		if rules.columnExcluded(fk.Column) || rules.tableExcluded(fk.RefTable) || rules.columnExcluded(fk.RefColumn) {
+			continue
+		}
+
+		fk.Column = rules.columnName(fk.Column)
+		fk.RefTable = rules.tableName(fk.RefTable)
+		fk.RefColumn = rules.columnName(fk.RefColumn)
+		foreignKeys = append(foreignKeys, fk)
+	}
+	schema.ForeignKeys = foreignKeys
+
+	return nil
+}
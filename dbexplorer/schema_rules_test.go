@@ -0,0 +1,126 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaRules(t *testing.T) {
+	var exp Explorer
+	err := WithSchemaRules(SchemaRules{
+		ExcludeTables:  []string{`_tmp$`},
+		ExcludeColumns: []string{`^legacy_`},
+		RenameTables:   []RenameRule{{Pattern: `^tbl_`, Replacement: ""}},
+		RenameColumns:  []RenameRule{{Pattern: `^usr_`, Replacement: "user_"}},
+	})(&exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exp.schemaRules.tableExcluded("import_tmp") || exp.schemaRules.tableExcluded("tbl_users") {
+		t.Errorf("unexpected table exclusion")
+	}
+
+	if got := exp.schemaRules.tableName("tbl_users"); got != "users" {
+		t.Errorf("expected users, got %s", got)
+	}
+
+	schema := &TableSchema{
+		PrimaryKey: "id",
+		Columns: []ColumnInfo{
+			{Name: "id"},
+			{Name: "usr_name"},
+			{Name: "legacy_flags"},
+		},
+		ForeignKeys: []ForeignKey{
+			{Name: "fk_owner", Column: "usr_owner", RefTable: "tbl_users", RefColumn: "id"},
+			{Name: "fk_batch", Column: "batch_id", RefTable: "batch_tmp", RefColumn: "id"},
+		},
+	}
+	if err := exp.applySchemaRules("tbl_posts", schema); err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+	for _, column := range schema.Columns {
+		names = append(names, column.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"id", "user_name"}) {
+		t.Errorf("unexpected columns %v", names)
+	}
+
+	expected := []ForeignKey{{Name: "fk_owner", Column: "user_owner", RefTable: "users", RefColumn: "id"}}
+	if !reflect.DeepEqual(schema.ForeignKeys, expected) {
+		t.Errorf("unexpected foreign keys %+v", schema.ForeignKeys)
+	}
+
+	excludedKey := &TableSchema{PrimaryKey: "legacy_id", Columns: []ColumnInfo{{Name: "legacy_id"}}}
+	if err := exp.applySchemaRules("tbl_old", excludedKey); err == nil {
+		t.Errorf("expected error for an excluded primary key")
+	}
+}
+
+func TestSchemaRulesNames(t *testing.T) {
+	exp := newTestExplorer(nil)
+	err := WithSchemaRules(SchemaRules{
+		ExcludeColumns: []string{`^legacy_`},
+		RenameTables:   []RenameRule{{`^tbl_`, ""}},
+		RenameColumns:  []RenameRule{{`^(usr|ord)_`, ""}},
+	})(&exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := newIdentifiers()
+	if err := ids.tables.add("tbl_users", "users"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ids.tables.add("orders", "orders"); err != nil {
+		t.Fatal(err)
+	}
+
+	// a column may be named like a table, and columns of different tables may rename to the same name
+	columns := map[string]map[string]string{
+		"users":  {"id": "id", "usr_name": "name"},
+		"orders": {"id": "id", "ord_name": "name", "users": "users"},
+	}
+	for table, names := range columns {
+		for db, api := range names {
+			if err := ids.addColumn(table, db, api); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := ids.tables.add("users", "users"); err == nil {
+		t.Errorf("expected error for two tables named users")
+	}
+	if err := ids.addColumn("users", "name", "name"); err == nil {
+		t.Errorf("expected error for two columns of a table named name")
+	}
+
+	exp.schema.identifiers = ids
+
+	if got := exp.quote("users"); got != "`tbl_users`" {
+		t.Errorf("expected the database name, got %s", got)
+	}
+
+	if got := exp.quote("_explorer_views"); got != "`_explorer_views`" {
+		t.Errorf("expected unknown names to pass, got %s", got)
+	}
+
+	for _, c := range []struct{ table, column, expected string }{
+		{"users", "name", "`usr_name`"},
+		{"orders", "name", "`ord_name`"},
+		{"orders", "users", "`users`"},
+		{"unknown", "name", "`name`"},
+	} {
+		if got := exp.quoteColumn(c.table, c.column); got != c.expected {
+			t.Errorf("%s.%s: expected %s, got %s", c.table, c.column, c.expected, got)
+		}
+	}
+
+	if exp.apiColumn("usr_name") != "name" || !exp.hiddenColumn("legacy_flags") || exp.hiddenColumn("id") {
+		t.Errorf("unexpected column mapping")
+	}
+}
@@ -0,0 +1,21 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEnumValues(t *testing.T) {
+	cases := map[string][]string{
+		"enum('new','paid')":     {"new", "paid"},
+		"ENUM('it''s','a,b','')": {"it's", "a,b", ""},
+		"set('read','write')":    {"read", "write"},
+		"varchar(255)":           nil,
+	}
+
+	for columnType, expected := range cases {
+		if got := parseEnumValues(columnType); !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: expected %#v, got %#v", columnType, expected, got)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const sentrySendTimeout = 5 * time.Second
+
+// SentryReporter is an ErrorReporter sending the reports to Sentry through its HTTP store API.
+type SentryReporter struct {
+	// Environment and Release are attached to every event when set.
+	Environment string
+	Release     string
+	Client      *http.Client
+
+	endpoint   string
+	authHeader string
+}
+
+// NewSentryReporter reports to the project of the DSN, e.g. https://key@o1.ingest.sentry.io/42.
+func NewSentryReporter(dsn string) (*SentryReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry dsn: %w", err)
+	}
+
+	// a DSN may carry a path prefix before the project id
+	key := u.User.Username()
+	prefix, project := "", ""
+	if i := strings.LastIndex(u.Path, "/"); i >= 0 {
+		prefix, project = u.Path[:i], u.Path[i+1:]
+	}
+
+	if key == "" || u.Host == "" || project == "" {
+		return nil, fmt.Errorf("invalid sentry dsn: key, host and project id are required")
+	}
+
+	return &SentryReporter{
+		Client:     &http.Client{Timeout: sentrySendTimeout},
+		endpoint:   fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project),
+		authHeader: fmt.Sprintf("Sentry sentry_version=7, sentry_client=db_explorer/1.0, sentry_key=%s", key),
+	}, nil
+}
+
+// Report sends the event in the background, failures to deliver it are dropped.
+func (s *SentryReporter) Report(ctx context.Context, report ErrorReport) {
+	event, err := s.event(report)
+	if err != nil {
+		return
+	}
+
+	go s.send(event)
+}
+
+func (s *SentryReporter) event(report ErrorReport) ([]byte, error) {
+	id, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	level, exceptionType := "error", "error"
+	if report.Panic != nil {
+		level, exceptionType = "fatal", "panic"
+	} else if report.Err != nil {
+		exceptionType = fmt.Sprintf("%T", report.Err)
+	}
+
+	// database errors quote the offending values, e.g. Duplicate entry 'alice@example.com' for key 'email'
+	message := http.StatusText(report.Status)
+	if report.Err != nil {
+		message = sanitizeSQL(report.Err.Error())
+	}
+
+	extra := map[string]any{}
+	if report.SQL != "" {
+		extra["sql"] = report.SQL
+	}
+	if len(report.Stack) > 0 {
+		extra["stack"] = string(report.Stack)
+	}
+
+	event := map[string]any{
+		"event_id":    id[:32],
+		"timestamp":   report.Time.UTC().Format(time.RFC3339),
+		"level":       level,
+		"platform":    "go",
+		"logger":      "db_explorer",
+		"transaction": report.Method + " " + report.Path,
+		"exception": map[string]any{
+			"values": []map[string]any{{"type": exceptionType, "value": message}},
+		},
+		"request": map[string]any{
+			"method": report.Method,
+			"url":    report.Path,
+		},
+		"tags": map[string]string{
+			"status": strconv.Itoa(report.Status),
+		},
+		"extra": extra,
+	}
+
+	if report.Principal != "" {
+		event["user"] = map[string]string{"username": report.Principal}
+	}
+	if s.Environment != "" {
+		event["environment"] = s.Environment
+	}
+	if s.Release != "" {
+		event["release"] = s.Release
+	}
+
+	return json.Marshal(event)
+}
+
+func (s *SentryReporter) send(event []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), sentrySendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(event))
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", s.authHeader)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,232 @@
+package dbexplorer
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookieName = "db_explorer_session"
+	csrfHeader        = "X-CSRF-Token"
+)
+
+// LocalUser is a user allowed to log in with a password.
+// Password is the hash of the password created by HashPassword, plain text passwords are rejected.
+type LocalUser struct {
+	Password string
+	Roles    []string
+}
+
+type session struct {
+	principal *Principal
+	csrfToken string
+	expires   time.Time
+}
+
+type sessionStore struct {
+	users map[string]LocalUser
+	ttl   time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type LoginResponse struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
+// WithSessions enables cookie sessions for browser clients: POST /_login with local user credentials
+// sets a session cookie and returns a CSRF token that has to be sent in X-CSRF-Token on mutating requests.
+func WithSessions(users map[string]LocalUser, ttl time.Duration) Option {
+	return func(exp *Explorer) error {
+		if err := checkUsers(users); err != nil {
+			return err
+		}
+
+		exp.sessions = &sessionStore{
+			users:    users,
+			ttl:      ttl,
+			sessions: make(map[string]*session),
+		}
+		exp.authExemptPaths["/_login"] = true
+
+		return WithAuthenticator(exp.sessions)(exp)
+	}
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func (s *sessionStore) create(principal *Principal) (id string, sess *session, err error) {
+	id, err = randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	csrfToken, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	sess = &session{
+		principal: principal,
+		csrfToken: csrfToken,
+		expires:   time.Now().Add(s.ttl),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, v := range s.sessions {
+		if now.After(v.expires) {
+			delete(s.sessions, key)
+		}
+	}
+	s.sessions[id] = sess
+
+	return id, sess, nil
+}
+
+func (s *sessionStore) get(id string) *session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil
+	}
+
+	if time.Now().After(sess.expires) {
+		delete(s.sessions, id)
+		return nil
+	}
+
+	return sess
+}
+
+func (s *sessionStore) delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+}
+
+func (s *sessionStore) Authenticate(r *http.Request) (*Principal, error) {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return nil, nil
+	}
+
+	// an expired session leaves the request anonymous, like a request without a cookie
+	sess := s.get(cookie.Value)
+	if sess == nil {
+		return nil, nil
+	}
+
+	if !isSafeMethod(r.Method) {
+		token := r.Header.Get(csrfHeader)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(sess.csrfToken)) != 1 {
+			return nil, statusError{Status: http.StatusForbidden, Err: fmt.Errorf("invalid csrf token")}
+		}
+	}
+
+	return sess.principal, nil
+}
+
+// cookiePath limits the session cookie to the paths of the API, see WithPrefix.
+func (exp Explorer) cookiePath() string {
+	if exp.prefix == "" {
+		return "/"
+	}
+
+	return exp.prefix
+}
+
+func (exp Explorer) handlerLogin(w http.ResponseWriter, r *http.Request) {
+	if !exp.checkAuthFailures(w, r) {
+		return
+	}
+
+	req := LoginRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	user, ok := exp.sessions.users[req.Username]
+	if !ok || !checkPassword(user.Password, req.Password) {
+		exp.recordAuthFailure(r)
+		writeError(w, http.StatusUnauthorized, LocalizedError{Code: MsgInvalidCredentials})
+		return
+	}
+
+	id, sess, err := exp.sessions.create(&Principal{Name: req.Username, Roles: user.Roles})
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    id,
+		Path:     exp.cookiePath(),
+		Expires:  sess.expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	response := Response{
+		Response: LoginResponse{
+			CSRFToken: sess.csrfToken,
+		},
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	w.Write(data)
+}
+
+func (exp Explorer) handlerLogout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		exp.sessions.delete(cookie.Value)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    "",
+		Path:     exp.cookiePath(),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
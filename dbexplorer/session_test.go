@@ -0,0 +1,184 @@
+package dbexplorer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// test vectors of RFC 7914, section 11
+	key := pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)
+	expected := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("unexpected key %x", key)
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	stored, err := hashPassword("love", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := hashPassword("love", 1000)
+	if err != nil || other == stored {
+		t.Errorf("expected a different salt for every hash, got %s %v", other, err)
+	}
+
+	cases := []struct {
+		stored   string
+		given    string
+		expected bool
+	}{
+		{stored, "love", true},
+		{stored, "hate", false},
+		{"love", "love", false},
+		{"sha256:686f746a95b6f836d7d70567c302c3f9ebb5ee0def3d1220ee9d4e9f34f5e131", "love", false},
+		{"pbkdf2-sha256$0$00$00", "love", false},
+	}
+
+	for _, c := range cases {
+		if got := checkPassword(c.stored, c.given); got != c.expected {
+			t.Errorf("checkPassword(%q, %q) = %v, want %v", c.stored, c.given, got, c.expected)
+		}
+	}
+}
+
+func newSessionExplorer(t *testing.T, opts ...Option) Explorer {
+	password, err := hashPassword("love", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Explorer{authExemptPaths: make(map[string]bool)}
+	opts = append(opts, WithSessions(map[string]LocalUser{"alice": {Password: password, Roles: []string{"editor"}}}, time.Hour))
+	for _, opt := range opts {
+		if err := opt(&exp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return exp
+}
+
+func login(t *testing.T, exp Explorer, password string) (*httptest.ResponseRecorder, *http.Cookie, string) {
+	r := httptest.NewRequest(http.MethodPost, "/_login", strings.NewReader(`{"username": "alice", "password": "`+password+`"}`))
+	w := httptest.NewRecorder()
+	exp.handlerLogin(w, r)
+
+	var response struct {
+		Response LoginResponse `json:"response"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == sessionCookieName {
+			return w, cookie, response.Response.CSRFToken
+		}
+	}
+
+	return w, nil, ""
+}
+
+func TestSessions(t *testing.T) {
+	exp := newSessionExplorer(t)
+	handler := exp.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(PrincipalFromContext(r.Context()).Name))
+	}))
+
+	if w, cookie, _ := login(t, exp, "hate"); w.Code != http.StatusUnauthorized || cookie != nil {
+		t.Errorf("wrong password: expected 401 without a cookie, got %d %v", w.Code, cookie)
+	}
+
+	w, cookie, csrfToken := login(t, exp, "love")
+	if w.Code != http.StatusOK || cookie == nil || csrfToken == "" {
+		t.Fatalf("login: unexpected %d %s", w.Code, w.Body.String())
+	}
+	if !cookie.HttpOnly || cookie.Path != "/" {
+		t.Errorf("unexpected cookie %+v", cookie)
+	}
+
+	serve := func(method string, cookie *http.Cookie, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/items/1", nil)
+		r.AddCookie(cookie)
+		if token != "" {
+			r.Header.Set(csrfHeader, token)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := serve(http.MethodGet, cookie, ""); w.Code != http.StatusOK || w.Body.String() != "alice" {
+		t.Errorf("read: unexpected %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPost, cookie, csrfToken); w.Code != http.StatusOK {
+		t.Errorf("write with csrf token: unexpected %d %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPost, cookie, ""); w.Code != http.StatusForbidden {
+		t.Errorf("write without csrf token: expected 403, got %d", w.Code)
+	}
+	if w := serve(http.MethodDelete, cookie, "wrong"); w.Code != http.StatusForbidden {
+		t.Errorf("write with wrong csrf token: expected 403, got %d", w.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/_logout", nil)
+	r.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	exp.handlerLogout(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("logout: expected 204, got %d", w.Code)
+	}
+	if cleared := w.Result().Cookies(); len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("logout: expected the cookie to be cleared, got %v", cleared)
+	}
+
+	if w := serve(http.MethodGet, cookie, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("after logout: expected 401, got %d", w.Code)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	exp := newSessionExplorer(t)
+
+	_, cookie, _ := login(t, exp, "love")
+	if cookie == nil {
+		t.Fatal("expected a session cookie")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/items", nil)
+	r.AddCookie(cookie)
+	if principal, err := exp.sessions.Authenticate(r); principal == nil || err != nil {
+		t.Fatalf("unexpected %v %v", principal, err)
+	}
+
+	exp.sessions.sessions[cookie.Value].expires = time.Now().Add(-time.Second)
+	if principal, err := exp.sessions.Authenticate(r); principal != nil || err != nil {
+		t.Errorf("expired session: expected nil, nil, got %v %v", principal, err)
+	}
+	if _, ok := exp.sessions.sessions[cookie.Value]; ok {
+		t.Errorf("expected the expired session to be removed")
+	}
+
+	unknown := httptest.NewRequest(http.MethodGet, "/items", nil)
+	unknown.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "unknown"})
+	if principal, err := exp.sessions.Authenticate(unknown); principal != nil || err != nil {
+		t.Errorf("unknown session: expected nil, nil, got %v %v", principal, err)
+	}
+}
+
+func TestSessionCookiePath(t *testing.T) {
+	exp := newSessionExplorer(t, WithPrefix("/api/"))
+
+	_, cookie, _ := login(t, exp, "love")
+	if cookie == nil || cookie.Path != "/api" {
+		t.Errorf("expected the cookie path /api, got %+v", cookie)
+	}
+}
@@ -0,0 +1,61 @@
+package dbexplorer
+
+import (
+	"errors"
+	"net/http"
+)
+
+// sqlErrorStatuses maps the codes of database errors, MySQL error numbers and SQLSTATEs, to the status
+// and message telling the client what went wrong.
+var sqlErrorStatuses = map[string]statusError{
+	"1062":  {Status: http.StatusConflict, Err: errDuplicateKey},
+	"23505": {Status: http.StatusConflict, Err: errDuplicateKey},
+	"1451":  {Status: http.StatusConflict, Err: errForeignKey},
+	"1452":  {Status: http.StatusConflict, Err: errForeignKey},
+	"23503": {Status: http.StatusConflict, Err: errForeignKey},
+	"1406":  {Status: http.StatusUnprocessableEntity, Err: errValueTooLong},
+	"22001": {Status: http.StatusUnprocessableEntity, Err: errValueTooLong},
+	"1213":  {Status: http.StatusServiceUnavailable, Err: errDatabaseBusy},
+	"1205":  {Status: http.StatusServiceUnavailable, Err: errDatabaseBusy},
+	"40001": {Status: http.StatusServiceUnavailable, Err: errDatabaseBusy},
+	"40P01": {Status: http.StatusServiceUnavailable, Err: errDatabaseBusy},
+}
+
+// retryAfterBusy is the Retry-After of a deadlock or lock wait timeout, the transaction can be retried right away.
+const retryAfterBusy = "1"
+
+// translateSQLError returns the status error of a known database error, or the error itself when
+// the write was refused with a status, e.g. 423 for a locked record.
+func translateSQLError(err error) (statusError, bool) {
+	var statusErr statusError
+	if errors.As(err, &statusErr) {
+		return statusErr, true
+	}
+
+	statusErr, ok := sqlErrorStatuses[sqlErrorCode(err)]
+	return statusErr, ok
+}
+
+// writeSQLError reports a known database error with its status and the message of its cause.
+func writeSQLError(w http.ResponseWriter, r *http.Request, err error) bool {
+	statusErr, ok := translateSQLError(err)
+	if !ok {
+		return false
+	}
+
+	if statusErr.Status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", retryAfterBusy)
+	}
+
+	writeError(w, statusErr.Status, statusErr.Err)
+	return true
+}
+
+// writeWriteError reports a failed create or update, writes the database refused for other reasons are bad requests.
+func writeWriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if writeSQLError(w, r, err) {
+		return
+	}
+
+	w.WriteHeader(http.StatusBadRequest)
+}
@@ -0,0 +1,47 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "pq: " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestWriteSQLErrors(t *testing.T) {
+	cases := []struct {
+		err        error
+		status     int
+		body       string
+		retryAfter string
+	}{
+		{&mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"}, http.StatusConflict, `{"error":"duplicate key"}`, ""},
+		{fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1452}), http.StatusConflict, `{"error":"referenced record missing or still referenced"}`, ""},
+		{&mysql.MySQLError{Number: 1406, Message: "Data too long for column 'title'"}, http.StatusUnprocessableEntity, `{"error":"value too long"}`, ""},
+		{&mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, http.StatusServiceUnavailable, `{"error":"database busy, retry the request"}`, "1"},
+		{sqlStateError("23505"), http.StatusConflict, `{"error":"duplicate key"}`, ""},
+		{&mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"}, http.StatusBadRequest, "", ""},
+		{fmt.Errorf("boom"), http.StatusBadRequest, "", ""},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		writeWriteError(w, httptest.NewRequest("PUT", "/items/", nil), c.err)
+
+		if w.Code != c.status || w.Body.String() != c.body || w.Header().Get("Retry-After") != c.retryAfter {
+			t.Errorf("%v: expected %d %s, got %d %s (Retry-After %q)", c.err, c.status, c.body, w.Code, w.Body.String(), w.Header().Get("Retry-After"))
+		}
+	}
+
+	w := httptest.NewRecorder()
+	writeQueryError(w, httptest.NewRequest("GET", "/items", nil), &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected a lock wait timeout of a read to be 503, got %d", w.Code)
+	}
+}
@@ -0,0 +1,184 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// WithSQLiteExport enables GET /_export/sqlite. The SQLite driver isn't a dependency of the explorer,
+// import one (e.g. modernc.org/sqlite registers "sqlite", github.com/mattn/go-sqlite3 registers "sqlite3")
+// and pass its driver name.
+func WithSQLiteExport(driverName string) Option {
+	return func(exp *Explorer) error {
+		exp.sqliteDriver = driverName
+		return nil
+	}
+}
+
+func sqliteColumnType(dataType string) string {
+	switch {
+	case isIntegerDataType(dataType):
+		return "INTEGER"
+	case dataType == "decimal" || dataType == "numeric":
+		return "NUMERIC"
+	case isNumericDataType(dataType):
+		return "REAL"
+	case strings.Contains(dataType, "blob") || strings.Contains(dataType, "binary") || dataType == "bytea":
+		return "BLOB"
+	}
+
+	return "TEXT"
+}
+
+func sqliteQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// exportTable copies schema and rows of the table into the SQLite database.
+func (exp Explorer) exportTable(ctx context.Context, target *sql.Tx, table string) error {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return err
+	}
+
+	definitions := make([]string, len(schema.Columns))
+	names := make([]string, len(schema.Columns))
+	blobs := make([]bool, len(schema.Columns))
+	for i, c := range schema.Columns {
+		names[i] = c.Name
+		columnType := sqliteColumnType(c.DataType)
+		blobs[i] = columnType == "BLOB"
+
+		definitions[i] = sqliteQuote(c.Name) + " " + columnType
+		if c.Name == schema.PrimaryKey {
+			definitions[i] += " PRIMARY KEY"
+		} else if !c.Nullable {
+			definitions[i] += " NOT NULL"
+		}
+	}
+
+	if _, err := target.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", sqliteQuote(table), strings.Join(definitions, ", "))); err != nil {
+		return err
+	}
+
+	quotedNames := make([]string, len(names))
+	for i, name := range names {
+		quotedNames[i] = sqliteQuote(name)
+	}
+	insert, err := target.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		sqliteQuote(table), strings.Join(quotedNames, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")))
+	if err != nil {
+		return err
+	}
+
+	defer insert.Close()
+
+	rows, release, err := exp.queryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", exp.quoteColumns(table, names), exp.quote(table)))
+	if err != nil {
+		return err
+	}
+
+	defer release()
+	defer rows.Close()
+
+	values := make([]any, len(names))
+	pointers := make([]any, len(names))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+
+		args := make([]any, len(values))
+		for i, v := range values {
+			if blobs[i] {
+				args[i] = v
+			} else {
+				args[i] = normalizeValue(v)
+			}
+		}
+
+		if _, err := insert.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// buildSQLiteExport writes the tables readable by the principal into a new SQLite file at path.
+func (exp Explorer) buildSQLiteExport(ctx context.Context, path string, principal *Principal) error {
+	target, err := sql.Open(exp.sqliteDriver, path)
+	if err != nil {
+		return err
+	}
+
+	defer target.Close()
+
+	tx, err := target.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, table := range exp.tableNames() {
+		if exp.authorize(ctx, principal, Action{Table: table, Op: OpRead}) != nil {
+			continue
+		}
+
+		if err := exp.exportTable(ctx, tx, table); err != nil {
+			return fmt.Errorf("export %s: %w", table, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (exp Explorer) handlerExportSQLite(w http.ResponseWriter, r *http.Request) {
+	if exp.sqliteDriver == "" {
+		writeError(w, http.StatusNotImplemented, fmt.Errorf("sqlite export is not configured"))
+		return
+	}
+
+	file, err := os.CreateTemp("", "db_explorer_*.sqlite")
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	if err := exp.buildSQLiteExport(r.Context(), path, PrincipalFromContext(r.Context())); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	file, err = os.Open(path)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer file.Close()
+
+	filename := fmt.Sprintf("export_%s.sqlite", time.Now().UTC().Format("20060102_150405"))
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if info, err := file.Stat(); err == nil {
+		w.Header().Set("Content-Length", fmt.Sprint(info.Size()))
+	}
+
+	io.Copy(w, file)
+}
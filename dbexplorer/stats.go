@@ -0,0 +1,247 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const statsTable = metaTablePrefix + "stats"
+
+type ColumnStats struct {
+	Name     string `json:"name"`
+	Nulls    int64  `json:"nulls"`
+	Distinct *int64 `json:"distinct"`
+	Min      any    `json:"min"`
+	Max      any    `json:"max"`
+}
+
+// TableStats are the row count of a table and the statistics of its columns. Distinct, Min and Max
+// are left out for columns that can't be compared, e.g. JSON or spatial ones.
+type TableStats struct {
+	Rows       int64         `json:"rows"`
+	Columns    []ColumnStats `json:"columns"`
+	ComputedAt time.Time     `json:"computed_at"`
+}
+
+// WithStatsCache serves GET /{table}/_stats from the _explorer_stats table, recomputed for every table
+// by the scheduler at the interval instead of scanning the table on every request. ?refresh=true
+// recomputes the statistics of a table on demand, it needs the admin role once callers are told apart.
+func WithStatsCache(interval time.Duration) Option {
+	return func(exp *Explorer) error {
+		if interval <= 0 {
+			return fmt.Errorf("stats cache interval must be positive")
+		}
+
+		exp.metaTables = append(exp.metaTables, metaTable{
+			Name: statsTable,
+			Columns: []metaColumn{
+				{Name: "table_name", Kind: "string"},
+				{Name: "stats", Kind: "text"},
+				{Name: "computed_at", Kind: "time"},
+			},
+			PrimaryKey: "table_name",
+		})
+		exp.statsRefresh = interval
+
+		return nil
+	}
+}
+
+func isComparableDataType(dataType string) bool {
+	switch {
+	case isJSONDataType(dataType), isSpatialDataType(dataType):
+		return false
+	case strings.Contains(dataType, "blob"), strings.Contains(dataType, "binary"), dataType == "bytea":
+		return false
+	}
+
+	return true
+}
+
+// buildStatsQuery computes the statistics of every column in a single scan of the table.
+func (exp Explorer) buildStatsQuery(table string) (string, []ColumnInfo, error) {
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return "", nil, err
+	}
+
+	selectList := []string{"COUNT(*)"}
+	for _, column := range schema.Columns {
+		quoted := exp.quoteColumn(table, column.Name)
+		selectList = append(selectList, "COUNT("+quoted+")")
+		if isComparableDataType(column.DataType) {
+			selectList = append(selectList, "COUNT(DISTINCT "+quoted+")", "MIN("+quoted+")", "MAX("+quoted+")")
+		}
+	}
+
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(selectList, ", "), exp.quote(table)), schema.Columns, nil
+}
+
+func (exp Explorer) computeStats(ctx context.Context, table string) (TableStats, error) {
+	stats := TableStats{Columns: make([]ColumnStats, 0)}
+
+	query, columns, err := exp.buildStatsQuery(table)
+	if err != nil {
+		return stats, err
+	}
+
+	values := []any{&stats.Rows}
+	counts := make([]int64, len(columns))
+	for i, column := range columns {
+		stats.Columns = append(stats.Columns, ColumnStats{Name: column.Name})
+		values = append(values, &counts[i])
+		if isComparableDataType(column.DataType) {
+			stats.Columns[i].Distinct = new(int64)
+			values = append(values, stats.Columns[i].Distinct, &stats.Columns[i].Min, &stats.Columns[i].Max)
+		}
+	}
+
+	rows, release, err := exp.queryContext(ctx, query)
+	if err != nil {
+		return stats, err
+	}
+
+	defer release()
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return stats, err
+		}
+		return stats, sql.ErrNoRows
+	}
+	if err := rows.Scan(values...); err != nil {
+		return stats, err
+	}
+
+	for i := range stats.Columns {
+		stats.Columns[i].Nulls = stats.Rows - counts[i]
+		stats.Columns[i].Min = normalizeValue(stats.Columns[i].Min)
+		stats.Columns[i].Max = normalizeValue(stats.Columns[i].Max)
+	}
+	stats.ComputedAt = time.Now().UTC()
+
+	return stats, nil
+}
+
+func (exp Explorer) cachedStats(table string) (TableStats, bool, error) {
+	var (
+		stats TableStats
+		data  string
+	)
+
+	err := exp.db().QueryRow(`SELECT stats FROM `+statsTable+` WHERE table_name = ?`, table).Scan(&data)
+	if err == sql.ErrNoRows {
+		return stats, false, nil
+	}
+	if err != nil {
+		return stats, false, err
+	}
+
+	return stats, true, json.Unmarshal([]byte(data), &stats)
+}
+
+func (exp Explorer) storeStats(table string, stats TableStats) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM `+statsTable+` WHERE table_name = ?`, table); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO `+statsTable+` (table_name, stats, computed_at) VALUES (?, ?, ?)`, table, string(data), stats.ComputedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (exp Explorer) refreshStats(ctx context.Context, table string) (TableStats, error) {
+	stats, err := exp.computeStats(ctx, table)
+	if err != nil {
+		return stats, err
+	}
+
+	return stats, exp.storeStats(table, stats)
+}
+
+// initStatsCache schedules the refresh of the cached statistics.
+func (exp Explorer) initStatsCache() {
+	exp.metrics.describe("db_explorer_stats_refresh_failed_total", "Failed refreshes of cached column statistics.")
+
+	if exp.statsRefresh == 0 {
+		return
+	}
+
+	exp.scheduler.every("stats", exp.statsRefresh, func() {
+		for _, table := range exp.tableNames() {
+			if _, err := exp.refreshStats(context.Background(), table); err != nil {
+				exp.metrics.add("db_explorer_stats_refresh_failed_total", 1, "table", table)
+			}
+		}
+	})
+}
+
+func (exp Explorer) handlerGetStats(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if !exp.requireAggregate(w, r) {
+		return
+	}
+
+	if exp.statsRefresh == 0 {
+		stats, err := exp.computeStats(r.Context(), tableName)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		writeResponse(w, stats)
+		return
+	}
+
+	refresh := r.URL.Query().Get("refresh") == "true"
+	if refresh && exp.adminRestricted() && !exp.requireAdmin(w, r) {
+		return
+	}
+
+	if !refresh {
+		stats, ok, err := exp.cachedStats(tableName)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		if ok {
+			if meta := responseMeta(w); meta != nil {
+				meta.CacheHit = true
+			}
+			writeResponse(w, stats)
+			return
+		}
+	}
+
+	stats, err := exp.refreshStats(r.Context(), tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, stats)
+}
@@ -0,0 +1,45 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBuildStatsQuery(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "int"}, {Name: "meta", DataType: "json"}}},
+	})
+
+	query, columns, err := exp.buildStatsQuery("items")
+	if err != nil || len(columns) != 2 ||
+		query != "SELECT COUNT(*), COUNT(`id`), COUNT(DISTINCT `id`), MIN(`id`), MAX(`id`), COUNT(`meta`) FROM `items`" {
+		t.Errorf("unexpected query %q %v", query, err)
+	}
+
+	if _, _, err := exp.buildStatsQuery("missing"); err == nil {
+		t.Errorf("expected error for an unknown table")
+	}
+
+	if err := WithStatsCache(0)(&exp); err == nil {
+		t.Errorf("expected error for a zero interval")
+	}
+}
+
+func TestStatsRefreshRequiresAdmin(t *testing.T) {
+	exp := newItemsExplorer()
+	if err := WithStatsCache(time.Hour)(&exp); err != nil {
+		t.Fatal(err)
+	}
+	exp.adminRole = "admin"
+	exp.authenticators = []Authenticator{AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		return &Principal{Name: "bob", Roles: []string{"reader"}}, nil
+	})}
+	exp.initRoutes()
+	handler := exp.authMiddleware(exp.router)
+
+	w := serveRequest(handler, http.MethodGet, "/items/_stats?refresh=true", "")
+	if w.Code != http.StatusForbidden || w.Body.String() != `{"error":"forbidden"}` {
+		t.Errorf("expected 403 refreshing as a reader, got %d %s", w.Code, w.Body.String())
+	}
+}
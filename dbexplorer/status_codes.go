@@ -0,0 +1,10 @@
+package dbexplorer
+
+// WithStrictStatusCodes answers the single record writes with the status of their outcome instead of
+// 200 and 400: 404 for an update or delete of a missing record and 422 for a form failing validation.
+func WithStrictStatusCodes() Option {
+	return func(exp *Explorer) error {
+		exp.strictStatus = true
+		return nil
+	}
+}
@@ -0,0 +1,34 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStrictStatusCodes(t *testing.T) {
+	invalid := ValidationErrors{{Field: "title", Reason: reasonInvalidType}}
+
+	var exp Explorer
+	if err := WithStrictStatusCodes()(&exp); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		exp      Explorer
+		expected int
+	}{
+		{"validation", exp, http.StatusUnprocessableEntity},
+		{"validation by default", Explorer{}, http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		c.exp.writeFormError(w, httptest.NewRequest("POST", "/items/1", nil), invalid)
+
+		if w.Code != c.expected {
+			t.Errorf("%s: expected %d, got %d", c.name, c.expected, w.Code)
+		}
+	}
+}
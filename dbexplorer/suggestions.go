@@ -0,0 +1,225 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSlowQueryLogSize = 1000
+	defaultMinSlowQueries   = 2
+	maxIndexNameLength      = 64
+)
+
+// slowQuery is the shape of a slow list query: the columns it filtered and sorted by.
+type slowQuery struct {
+	Table    string
+	Equality []string
+	Range    []string
+	Sort     []string
+	Duration time.Duration
+}
+
+// slowQueryLog keeps the last list queries that took longer than the threshold.
+type slowQueryLog struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	entries   []slowQuery
+	next      int
+	size      int
+}
+
+func (l *slowQueryLog) record(q slowQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) < l.size {
+		l.entries = append(l.entries, q)
+		return
+	}
+
+	l.entries[l.next] = q
+	l.next = (l.next + 1) % l.size
+}
+
+func (l *slowQueryLog) snapshot() []slowQuery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]slowQuery(nil), l.entries...)
+}
+
+type IndexSuggestion struct {
+	Table   string   `json:"table"`
+	Columns []string `json:"columns"`
+	// Queries is the number of logged slow queries the index would serve, TotalMs their total duration.
+	Queries int     `json:"queries"`
+	TotalMs float64 `json:"total_ms"`
+	DDL     string  `json:"ddl"`
+}
+
+type GetIndexSuggestionsResponse struct {
+	Suggestions []IndexSuggestion `json:"suggestions"`
+}
+
+// WithSlowQueryLog keeps the shape of the last list queries slower than threshold in memory and serves
+// GET /_suggestions/indexes, composite indexes for the filter and sort combinations seen most. NDJSON
+// streams are not logged, their duration depends on the client.
+func WithSlowQueryLog(threshold time.Duration) Option {
+	return func(exp *Explorer) error {
+		exp.slowQueries = &slowQueryLog{threshold: threshold, size: defaultSlowQueryLogSize}
+		return nil
+	}
+}
+
+// recordSlowQuery logs the list query if it ran for longer than the threshold since start.
+func (exp Explorer) recordSlowQuery(table string, listQuery ListQuery, start time.Time) {
+	if exp.slowQueries == nil {
+		return
+	}
+
+	duration := time.Since(start)
+	if duration < exp.slowQueries.threshold {
+		return
+	}
+
+	q := slowQuery{Table: table, Duration: duration}
+	for _, f := range listQuery.Filters {
+		if f.Operator == opEq || f.Operator == opIn || f.Operator == opIsNull {
+			q.Equality = append(q.Equality, f.Column)
+		} else {
+			q.Range = append(q.Range, f.Column)
+		}
+	}
+	for _, field := range listQuery.Sort {
+		q.Sort = append(q.Sort, field.Column)
+	}
+
+	exp.slowQueries.record(q)
+}
+
+// indexColumns orders the columns of an index serving the query: equality filters first, so the
+// rest of the index stays ordered, then the sort columns or else the first range filter.
+func indexColumns(q slowQuery) []string {
+	equality := append([]string(nil), q.Equality...)
+	sort.Strings(equality)
+
+	columns := make([]string, 0)
+	add := func(names ...string) {
+		for _, name := range names {
+			if !containsString(columns, name) {
+				columns = append(columns, name)
+			}
+		}
+	}
+
+	add(equality...)
+	if len(q.Sort) > 0 {
+		add(q.Sort...)
+	} else if len(q.Range) > 0 {
+		add(q.Range[0])
+	}
+
+	return columns
+}
+
+// coveredBy reports whether an existing index starts with the columns.
+func coveredBy(indexes map[string][]string, columns []string) bool {
+	for _, index := range indexes {
+		if len(index) >= len(columns) && strings.Join(index[:len(columns)], ",") == strings.Join(columns, ",") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (exp Explorer) indexDDL(table string, columns []string) string {
+	name := "idx_" + table + "_" + strings.Join(columns, "_")
+	if len(name) > maxIndexNameLength {
+		name = name[:maxIndexNameLength]
+	}
+
+	return fmt.Sprintf("CREATE INDEX %s ON %s (%s)", exp.dialect.QuoteIdent(name), exp.quote(table), exp.quoteColumns(table, columns))
+}
+
+// suggestIndexes groups the slow queries by the index that would serve them. Suggestions below
+// minQueries or covered by an existing index are left out.
+func (exp Explorer) suggestIndexes(queries []slowQuery, existing map[string]map[string][]string, minQueries int) []IndexSuggestion {
+	byKey := make(map[string]*IndexSuggestion)
+	for _, q := range queries {
+		columns := indexColumns(q)
+		if len(columns) == 0 || coveredBy(existing[q.Table], columns) {
+			continue
+		}
+
+		key := q.Table + "\x00" + strings.Join(columns, "\x00")
+		suggestion, ok := byKey[key]
+		if !ok {
+			suggestion = &IndexSuggestion{Table: q.Table, Columns: columns, DDL: exp.indexDDL(q.Table, columns)}
+			byKey[key] = suggestion
+		}
+
+		suggestion.Queries++
+		suggestion.TotalMs += float64(q.Duration.Microseconds()) / 1000
+	}
+
+	suggestions := make([]IndexSuggestion, 0)
+	for _, suggestion := range byKey {
+		if suggestion.Queries >= minQueries {
+			suggestions = append(suggestions, *suggestion)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].TotalMs != suggestions[j].TotalMs {
+			return suggestions[i].TotalMs > suggestions[j].TotalMs
+		}
+		return suggestions[i].DDL < suggestions[j].DDL
+	})
+
+	return suggestions
+}
+
+// handlerGetIndexSuggestions serves GET /_suggestions/indexes?min_queries=2. Indexes that exist are
+// only recognized on dialects listing them, see indexHinter.
+func (exp Explorer) handlerGetIndexSuggestions(w http.ResponseWriter, r *http.Request) {
+	if exp.adminRestricted() && !exp.requireAdmin(w, r) {
+		return
+	}
+
+	minQueries := defaultMinSlowQueries
+	if value := r.URL.Query().Get("min_queries"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("min_queries must be a positive integer"))
+			return
+		}
+		minQueries = n
+	}
+
+	queries := exp.slowQueries.snapshot()
+
+	existing := make(map[string]map[string][]string)
+	if hinter, ok := exp.dialect.(indexHinter); ok {
+		for _, q := range queries {
+			if _, ok := existing[q.Table]; ok {
+				continue
+			}
+
+			indexes, err := exp.tableIndexes(hinter, q.Table)
+			if err != nil {
+				writeInternalError(w, r, err)
+				return
+			}
+			existing[q.Table] = indexes
+		}
+	}
+
+	writeResponse(w, GetIndexSuggestionsResponse{Suggestions: exp.suggestIndexes(queries, existing, minQueries)})
+}
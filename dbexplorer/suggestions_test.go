@@ -0,0 +1,56 @@
+package dbexplorer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	log := &slowQueryLog{size: 2}
+	for _, table := range []string{"a", "b", "c"} {
+		log.record(slowQuery{Table: table})
+	}
+
+	entries := log.snapshot()
+	if len(entries) != 2 || entries[0].Table != "c" || entries[1].Table != "b" {
+		t.Errorf("expected the last two entries, got %+v", entries)
+	}
+
+	exp := Explorer{slowQueries: &slowQueryLog{threshold: time.Hour, size: 2}}
+	exp.recordSlowQuery("items", ListQuery{}, time.Now())
+	if len(exp.slowQueries.snapshot()) != 0 {
+		t.Errorf("fast query logged")
+	}
+}
+
+func TestSuggestIndexes(t *testing.T) {
+	exp := Explorer{dialect: MySQLDialect{}}
+
+	queries := []slowQuery{
+		{Table: "orders", Equality: []string{"status", "customer"}, Sort: []string{"created"}, Duration: 300 * time.Millisecond},
+		{Table: "orders", Equality: []string{"customer", "status"}, Sort: []string{"created"}, Duration: 200 * time.Millisecond},
+		{Table: "orders", Equality: []string{"customer"}, Range: []string{"total"}, Duration: time.Second},
+		{Table: "orders", Range: []string{"created"}, Duration: time.Second},
+		{Table: "orders", Range: []string{"created"}, Duration: time.Second},
+		{Table: "orders", Duration: time.Second},
+	}
+	existing := map[string]map[string][]string{
+		"orders": {"created_index": {"created", "id"}},
+	}
+
+	suggestions := exp.suggestIndexes(queries, existing, 1)
+	expected := []IndexSuggestion{
+		{Table: "orders", Columns: []string{"customer", "total"}, Queries: 1, TotalMs: 1000,
+			DDL: "CREATE INDEX `idx_orders_customer_total` ON `orders` (`customer`, `total`)"},
+		{Table: "orders", Columns: []string{"customer", "status", "created"}, Queries: 2, TotalMs: 500,
+			DDL: "CREATE INDEX `idx_orders_customer_status_created` ON `orders` (`customer`, `status`, `created`)"},
+	}
+	if !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("unexpected suggestions %+v", suggestions)
+	}
+
+	if suggestions := exp.suggestIndexes(queries, existing, 2); len(suggestions) != 1 {
+		t.Errorf("expected only the frequent suggestion, got %+v", suggestions)
+	}
+}
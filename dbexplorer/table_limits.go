@@ -0,0 +1,125 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TableLimits override the limits of the requests to one table, e.g. a billion-row events table.
+// Zero fields leave the table unrestricted.
+type TableLimits struct {
+	// RateLimit is a token bucket per client for the table, applied in addition to WithRateLimit.
+	RateLimit *RateLimit
+	// MaxConcurrent is the number of requests to the table running at once, others get 503.
+	MaxConcurrent int
+	// MaxPageSize caps the page size of list requests, larger ?limit= values are rejected with 400.
+	// It also caps NDJSON and CSV streams.
+	MaxPageSize int
+	// Timeout cancels the queries of requests running longer.
+	Timeout time.Duration
+}
+
+type tableLimiter struct {
+	limits TableLimits
+	rate   *rateLimiter
+	slots  chan struct{}
+}
+
+// WithTableLimits sets the limits of the table.
+func WithTableLimits(table string, limits TableLimits) Option {
+	return func(exp *Explorer) error {
+		limiter := &tableLimiter{limits: limits}
+
+		if limits.RateLimit != nil {
+			if limits.RateLimit.Rate <= 0 || limits.RateLimit.Burst <= 0 {
+				return fmt.Errorf("rate limit of %s requires positive rate and burst", table)
+			}
+			limiter.rate = newRateLimiter(*limits.RateLimit)
+		}
+
+		if limits.MaxConcurrent > 0 {
+			limiter.slots = make(chan struct{}, limits.MaxConcurrent)
+		}
+
+		if exp.tableLimits == nil {
+			exp.tableLimits = make(map[string]*tableLimiter)
+		}
+
+		exp.tableLimits[table] = limiter
+		return nil
+	}
+}
+
+// maxPageSize returns the max page size of the table, 0 for none.
+func (exp Explorer) maxPageSize(table string) int {
+	if limiter, ok := exp.tableLimits[table]; ok {
+		return limiter.limits.MaxPageSize
+	}
+
+	return 0
+}
+
+// tablePagination is getPagination within the max page size of the table. A larger default page
+// shrinks to it, a larger ?limit= is an error.
+func (exp Explorer) tablePagination(table string, query url.Values) (Pagination, error) {
+	pagination, err := exp.getPagination(query)
+	if err != nil {
+		return pagination, err
+	}
+
+	maxPageSize := exp.maxPageSize(table)
+	if maxPageSize == 0 || pagination.Limit <= maxPageSize {
+		return pagination, nil
+	}
+
+	if query.Has("limit") {
+		return pagination, fmt.Errorf("limit must not exceed %d for table %s", maxPageSize, table)
+	}
+
+	pagination.Limit = maxPageSize
+	return pagination, nil
+}
+
+func (exp Explorer) tableLimitsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter, ok := exp.tableLimits[strings.Split(r.URL.Path, "/")[1]]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if limiter.rate != nil {
+			ok, wait := limiter.rate.allow(exp.rateLimitClient(r, limiter.limits.RateLimit.ByPrincipal))
+			if !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				writeError(w, http.StatusTooManyRequests, LocalizedError{Code: MsgTooManyRequests})
+				return
+			}
+		}
+
+		if limiter.slots != nil {
+			select {
+			case limiter.slots <- struct{}{}:
+				defer func() { <-limiter.slots }()
+			default:
+				w.Header().Set("Retry-After", "1")
+				writeError(w, http.StatusServiceUnavailable, LocalizedError{Code: MsgTooManyRequests})
+				return
+			}
+		}
+
+		if limiter.limits.Timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), limiter.limits.Timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
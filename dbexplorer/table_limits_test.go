@@ -0,0 +1,76 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTableLimits(t *testing.T) {
+	exp := Explorer{}
+	if err := WithTableLimits("events", TableLimits{MaxConcurrent: 1, MaxPageSize: 100, Timeout: time.Second})(&exp); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := exp.tablePagination("events", url.Values{"limit": {"1000"}}); err == nil {
+		t.Errorf("expected error for a page over the max page size")
+	}
+	if p, _ := exp.tablePagination("events", url.Values{"limit": {"100"}}); p.Limit != 100 {
+		t.Errorf("expected a page at the max page size to pass, got %d", p.Limit)
+	}
+	large := exp
+	large.defaultLimit = 500
+	if p, _ := large.tablePagination("events", url.Values{}); p.Limit != 100 {
+		t.Errorf("expected the default page to be capped at 100, got %d", p.Limit)
+	}
+	if p, _ := exp.tablePagination("users", url.Values{"limit": {"1000"}}); p.Limit != 1000 {
+		t.Errorf("expected other tables to keep the limit, got %d", p.Limit)
+	}
+
+	entered := make(chan struct{})
+	finish := make(chan struct{})
+	handler := exp.tableLimitsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Errorf("expected a deadline")
+		}
+		if r.URL.Path == "/events/slow" {
+			close(entered)
+			<-finish
+		}
+	}))
+
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/events/slow", nil))
+	<-entered
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 over the concurrency limit, got %d", w.Code)
+	}
+	close(finish)
+
+	if err := WithTableLimits("events", TableLimits{RateLimit: &RateLimit{}})(&exp); err == nil {
+		t.Errorf("expected error for an empty rate limit")
+	}
+}
+
+func TestTableLimitsHandler(t *testing.T) {
+	exp := newItemsExplorer()
+	if err := WithTableLimits("items", TableLimits{MaxPageSize: 10})(&exp); err != nil {
+		t.Fatal(err)
+	}
+	exp.initRoutes()
+
+	for _, accept := range []string{"application/json", ndjsonContentType, csvContentType} {
+		r := httptest.NewRequest(http.MethodGet, "/items?limit=11", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		exp.router.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 over the max page size, got %d", accept, w.Code)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxTimeseriesPoints = 10000
+
+var timeseriesAggregates = map[string]string{
+	"avg":   "AVG",
+	"sum":   "SUM",
+	"min":   "MIN",
+	"max":   "MAX",
+	"count": "COUNT",
+}
+
+type TimeseriesPoint struct {
+	Bucket any `json:"bucket"`
+	Value  any `json:"value"`
+}
+
+type GetTimeseriesResponse struct {
+	Points []TimeseriesPoint `json:"points"`
+}
+
+// TimeseriesQuery is a parsed ?ts=created_at&value=amount&bucket=1h&agg=avg request.
+type TimeseriesQuery struct {
+	TimeColumn  string
+	ValueColumn string
+	Bucket      time.Duration
+	Aggregate   string
+	From        string
+	To          string
+}
+
+func isTimeDataType(dataType string) bool {
+	switch dataType {
+	case "date", "datetime", "timestamp", "timestamp without time zone", "timestamp with time zone":
+		return true
+	}
+
+	return false
+}
+
+func isNumericDataType(dataType string) bool {
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "decimal", "numeric", "float", "double",
+		"real", "double precision":
+		return true
+	}
+
+	return false
+}
+
+// parseBucket parses a bucket size like "15m", "1h" or "1d". Days and weeks are supported in addition
+// to the time.ParseDuration units.
+func parseBucket(value string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	var (
+		bucket time.Duration
+		err    error
+	)
+	if unit, ok := units[value[len(value)-1:]]; ok && len(value) > 1 {
+		var n int
+		n, err = strconv.Atoi(value[:len(value)-1])
+		bucket = time.Duration(n) * unit
+	} else {
+		bucket, err = time.ParseDuration(value)
+	}
+
+	if err != nil || bucket < time.Second || bucket%time.Second != 0 {
+		return 0, fmt.Errorf("invalid bucket %s", value)
+	}
+
+	return bucket, nil
+}
+
+func (exp Explorer) parseTimeseriesQuery(table string, query url.Values) (TimeseriesQuery, error) {
+	tsQuery := TimeseriesQuery{
+		TimeColumn:  query.Get("ts"),
+		ValueColumn: query.Get("value"),
+		Aggregate:   strings.ToLower(query.Get("agg")),
+		From:        query.Get("from"),
+		To:          query.Get("to"),
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return tsQuery, err
+	}
+
+	tsColumn, ok := schema.Column(tsQuery.TimeColumn)
+	if !ok || !isTimeDataType(tsColumn.DataType) {
+		return tsQuery, fmt.Errorf("ts must be a time column")
+	}
+
+	if tsQuery.Aggregate == "" {
+		tsQuery.Aggregate = "avg"
+	}
+	if _, ok := timeseriesAggregates[tsQuery.Aggregate]; !ok {
+		return tsQuery, fmt.Errorf("unknown agg %s", tsQuery.Aggregate)
+	}
+
+	if tsQuery.ValueColumn != "" || tsQuery.Aggregate != "count" {
+		valueColumn, ok := schema.Column(tsQuery.ValueColumn)
+		if !ok || !isNumericDataType(valueColumn.DataType) {
+			return tsQuery, fmt.Errorf("value must be a numeric column")
+		}
+	}
+
+	bucket := query.Get("bucket")
+	if bucket == "" {
+		bucket = "1h"
+	}
+	tsQuery.Bucket, err = parseBucket(bucket)
+	if err != nil {
+		return tsQuery, err
+	}
+
+	return tsQuery, nil
+}
+
+func (exp Explorer) buildTimeseriesQuery(table string, tsQuery TimeseriesQuery) (string, []any) {
+	timeColumn := exp.quoteColumn(table, tsQuery.TimeColumn)
+	bucket := exp.dialect.TimeBucket(timeColumn, int64(tsQuery.Bucket/time.Second))
+
+	value := "*"
+	if tsQuery.ValueColumn != "" {
+		value = exp.quoteColumn(table, tsQuery.ValueColumn)
+	}
+
+	where := []string{timeColumn + " IS NOT NULL"}
+	args := make([]any, 0)
+	if tsQuery.From != "" {
+		where = append(where, timeColumn+" >= ?")
+		args = append(args, tsQuery.From)
+	}
+	if tsQuery.To != "" {
+		where = append(where, timeColumn+" < ?")
+		args = append(args, tsQuery.To)
+	}
+
+	query := fmt.Sprintf("SELECT %s AS bucket, %s(%s) AS value FROM %s WHERE %s GROUP BY 1 ORDER BY 1 LIMIT %d",
+		bucket, timeseriesAggregates[tsQuery.Aggregate], value, exp.quote(table), strings.Join(where, " AND "), maxTimeseriesPoints)
+
+	return query, args
+}
+
+func (exp Explorer) handlerGetTimeseries(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if !exp.requireAggregate(w, r) {
+		return
+	}
+
+	tsQuery, err := exp.parseTimeseriesQuery(tableName, r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	query, args := exp.buildTimeseriesQuery(tableName, tsQuery)
+	rows, release, err := exp.queryContext(r.Context(), query, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer release()
+	defer rows.Close()
+
+	points := make([]TimeseriesPoint, 0)
+	for rows.Next() {
+		var point TimeseriesPoint
+		if err := rows.Scan(&point.Bucket, &point.Value); err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		point.Bucket = normalizeValue(point.Bucket)
+		point.Value = normalizeValue(point.Value)
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, GetTimeseriesResponse{Points: points})
+}
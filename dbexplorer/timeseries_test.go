@@ -0,0 +1,29 @@
+package dbexplorer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBucket(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30s": 30 * time.Second,
+		"15m": 15 * time.Minute,
+		"1h":  time.Hour,
+		"1d":  24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	}
+
+	for value, expected := range cases {
+		got, err := parseBucket(value)
+		if err != nil || got != expected {
+			t.Errorf("%s: expected %v, got %v (%v)", value, expected, got, err)
+		}
+	}
+
+	for _, value := range []string{"d", "0h", "500ms", "1x"} {
+		if _, err := parseBucket(value); err == nil {
+			t.Errorf("%s: expected error", value)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package dbexplorer
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	metaTablePrefix = "_explorer_"
+	tokensTable     = metaTablePrefix + "tokens"
+	tokenPrefix     = "dbx_"
+)
+
+type APIToken struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles"`
+	Scopes    []string `json:"scopes"`
+	CreatedAt string   `json:"created_at"`
+	RevokedAt *string  `json:"revoked_at"`
+}
+
+type TokenForm struct {
+	Name   *string  `json:"name"`
+	Roles  []string `json:"roles"`
+	Scopes []string `json:"scopes"`
+}
+
+type CreateTokenResponse struct {
+	ID    any    `json:"id"`
+	Token string `json:"token"`
+}
+
+type GetTokensResponse struct {
+	Tokens []APIToken `json:"tokens"`
+}
+
+type GetTokenResponse struct {
+	Token APIToken `json:"token"`
+}
+
+type RevokeTokenResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// WithTokenAuth enables API tokens sent as "Authorization: Bearer <token>".
+// Tokens are issued and revoked by admins through /_tokens and stored hashed in the _explorer_tokens table.
+func WithTokenAuth() Option {
+	return func(exp *Explorer) error {
+		exp.metaTables = append(exp.metaTables, metaTable{
+			Name: tokensTable,
+			Columns: []metaColumn{
+				{Name: "id", Kind: "serial"},
+				{Name: "name", Kind: "string"},
+				{Name: "token_hash", Kind: "string"},
+				{Name: "roles", Kind: "text"},
+				{Name: "scopes", Kind: "text"},
+				{Name: "created_at", Kind: "time"},
+				{Name: "revoked_at", Kind: "time", Nullable: true},
+			},
+			PrimaryKey: "id",
+			Unique:     [][]string{{"token_hash"}},
+		})
+		exp.tokenAuth = true
+
+		return WithAuthenticator(AuthenticatorFunc(exp.authenticateToken))(exp)
+	}
+}
+
+// WithAdminRole sets the role allowed to use administrative endpoints, "admin" by default.
+func WithAdminRole(role string) Option {
+	return func(exp *Explorer) error {
+		exp.adminRole = role
+		return nil
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (exp Explorer) authenticateToken(r *http.Request) (*Principal, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || !strings.HasPrefix(token, tokenPrefix) {
+		return nil, nil
+	}
+
+	var (
+		name   string
+		roles  string
+		scopes string
+	)
+
+	row := exp.db().QueryRow(`SELECT name, roles, scopes FROM `+tokensTable+` WHERE token_hash = ? AND revoked_at IS NULL`, hashToken(token))
+	if err := row.Scan(&name, &roles, &scopes); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid token")
+		}
+		return nil, err
+	}
+
+	principal := &Principal{
+		Name: "token:" + name,
+	}
+	json.Unmarshal([]byte(roles), &principal.Roles)
+
+	// an empty scope list means the token is limited by its roles only
+	tokenScopes := make([]string, 0)
+	json.Unmarshal([]byte(scopes), &tokenScopes)
+	if len(tokenScopes) > 0 {
+		principal.Scopes = tokenScopes
+	}
+
+	return principal, nil
+}
+
+func (exp Explorer) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	principal := PrincipalFromContext(r.Context())
+	if !principal.HasRole(exp.adminRole) {
+		exp.writeForbidden(w, r, fmt.Errorf("forbidden"))
+		return false
+	}
+
+	if !hasAdminScope(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("missing scope %s:*", adminScope))
+		return false
+	}
+
+	return true
+}
+
+// adminRestricted reports whether the admin endpoints of the explorer, e.g. /_query, need the admin role.
+// They are open while neither authenticators nor access rules or an authorizer tell callers apart.
+func (exp Explorer) adminRestricted() bool {
+	return len(exp.authenticators) > 0 || exp.access != nil || exp.authorizer != nil
+}
+
+func scanToken(scanner interface{ Scan(...any) error }) (APIToken, error) {
+	var (
+		token     APIToken
+		roles     string
+		scopes    string
+		revokedAt sql.NullString
+	)
+
+	if err := scanner.Scan(&token.ID, &token.Name, &roles, &scopes, &token.CreatedAt, &revokedAt); err != nil {
+		return token, err
+	}
+
+	token.Roles = make([]string, 0)
+	token.Scopes = make([]string, 0)
+	json.Unmarshal([]byte(roles), &token.Roles)
+	json.Unmarshal([]byte(scopes), &token.Scopes)
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.String
+	}
+
+	return token, nil
+}
+
+func (exp Explorer) handlerGetTokens(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireAdmin(w, r) {
+		return
+	}
+
+	rows, err := exp.db().Query(`SELECT id, name, roles, scopes, created_at, revoked_at FROM ` + tokensTable + ` ORDER BY id`)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer rows.Close()
+
+	tokens := make([]APIToken, 0)
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	writeResponse(w, GetTokensResponse{Tokens: tokens})
+}
+
+func (exp Explorer) handlerGetToken(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireAdmin(w, r) {
+		return
+	}
+
+	row := exp.db().QueryRow(`SELECT id, name, roles, scopes, created_at, revoked_at FROM `+tokensTable+` WHERE id = ?`, exp.getId(r.URL.Path))
+	token, err := scanToken(row)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("token not found"))
+		return
+	}
+
+	writeResponse(w, GetTokenResponse{Token: token})
+}
+
+func (exp Explorer) handlerCreateToken(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireAdmin(w, r) {
+		return
+	}
+
+	form := TokenForm{}
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if form.Name == nil || *form.Name == "" {
+		writeError(w, http.StatusBadRequest, NewValidationError("name"))
+		return
+	}
+
+	if form.Roles == nil {
+		form.Roles = make([]string, 0)
+	}
+	if form.Scopes == nil {
+		form.Scopes = make([]string, 0)
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	token = tokenPrefix + token
+
+	roles, _ := json.Marshal(form.Roles)
+	scopes, _ := json.Marshal(form.Scopes)
+
+	id, err := exp.insertReturningID(exp.db(), `INSERT INTO `+tokensTable+` (name, token_hash, roles, scopes, created_at) VALUES (?, ?, ?, ?, ?)`, "id",
+		*form.Name, hashToken(token), string(roles), string(scopes), time.Now().UTC())
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, CreateTokenResponse{ID: id, Token: token})
+}
+
+func (exp Explorer) handlerUpdateToken(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireAdmin(w, r) {
+		return
+	}
+
+	form := TokenForm{}
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	setColumnsQuery := make([]string, 0)
+	args := make([]any, 0)
+	if form.Name != nil {
+		setColumnsQuery = append(setColumnsQuery, "name = ?")
+		args = append(args, *form.Name)
+	}
+	if form.Roles != nil {
+		roles, _ := json.Marshal(form.Roles)
+		setColumnsQuery = append(setColumnsQuery, "roles = ?")
+		args = append(args, string(roles))
+	}
+	if form.Scopes != nil {
+		scopes, _ := json.Marshal(form.Scopes)
+		setColumnsQuery = append(setColumnsQuery, "scopes = ?")
+		args = append(args, string(scopes))
+	}
+
+	if len(setColumnsQuery) == 0 {
+		writeResponse(w, UpdateTableItemResponse{Updated: 0})
+		return
+	}
+
+	args = append(args, exp.getId(r.URL.Path))
+	result, err := exp.db().Exec(`UPDATE `+tokensTable+` SET `+strings.Join(setColumnsQuery, ", ")+` WHERE id = ?`, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	updated := 0
+	if affected > 0 {
+		updated = 1
+	}
+
+	// tokens aren't looked up before, so a token left as it was counts as not matched
+	writeResponse(w, UpdateTableItemResponse{Updated: updated, Matched: updated, Changed: updated})
+}
+
+func (exp Explorer) handlerRevokeToken(w http.ResponseWriter, r *http.Request) {
+	if !exp.requireAdmin(w, r) {
+		return
+	}
+
+	result, err := exp.db().Exec(`UPDATE `+tokensTable+` SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, time.Now().UTC(), exp.getId(r.URL.Path))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	revoked := 0
+	if affected > 0 {
+		revoked = 1
+	}
+
+	writeResponse(w, RevokeTokenResponse{Revoked: revoked})
+}
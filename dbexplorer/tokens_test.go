@@ -0,0 +1,111 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTokenHandlersRequireAdmin(t *testing.T) {
+	exp := newTestExplorer(nil)
+	exp.adminRole = "admin"
+	exp.tokenAuth = true
+	exp.authenticators = []Authenticator{AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		return &Principal{Name: "bob", Roles: []string{r.Header.Get("X-Role")}}, nil
+	})}
+	exp.initRoutes()
+	handler := exp.authMiddleware(exp.router)
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/_tokens", ""},
+		{http.MethodGet, "/_tokens/1", ""},
+		{http.MethodPut, "/_tokens/", `{"name": "ci"}`},
+		{http.MethodPost, "/_tokens/1", `{"name": "ci"}`},
+		{http.MethodDelete, "/_tokens/1", ""},
+	}
+
+	for _, req := range requests {
+		r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+		r.Header.Set("X-Role", "reader")
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s %s: expected 403, got %d", req.method, req.path, w.Code)
+		}
+	}
+}
+
+func TestTokenHandlers(t *testing.T) {
+	handler, err := New(openTestDB(t, tokensTable),
+		WithTokenAuth(),
+		WithAPIKeys(map[string]APIKey{
+			"admin-key":  {Name: "admin", Roles: []string{"admin"}},
+			"reader-key": {Name: "reader", Roles: []string{"reader"}},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method string, path string, body string, header string, value string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		r.Header.Set(header, value)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := do(http.MethodPut, "/_tokens/", `{"name": "ci"}`, apiKeyHeader, "reader-key"); w.Code != http.StatusForbidden {
+		t.Errorf("create as reader: expected 403, got %d", w.Code)
+	}
+
+	w := do(http.MethodPut, "/_tokens/", `{"name": "ci", "roles": ["reader"]}`, apiKeyHeader, "admin-key")
+	var created struct {
+		Response CreateTokenResponse `json:"response"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || !strings.HasPrefix(created.Response.Token, tokenPrefix) {
+		t.Fatalf("create: unexpected %d %s", w.Code, w.Body.String())
+	}
+	id := fmt.Sprint(created.Response.ID)
+	bearer := "Bearer " + created.Response.Token
+
+	if w := do(http.MethodGet, "/items/1", "", "Authorization", bearer); w.Code != http.StatusOK {
+		t.Errorf("read with token: expected 200, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = do(http.MethodGet, "/_tokens", "", apiKeyHeader, "admin-key")
+	var listed struct {
+		Response GetTokensResponse `json:"response"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listed); err != nil || len(listed.Response.Tokens) != 1 ||
+		listed.Response.Tokens[0].Name != "ci" || listed.Response.Tokens[0].RevokedAt != nil {
+		t.Errorf("list: unexpected %d %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), created.Response.Token) {
+		t.Errorf("list: the token must not be returned")
+	}
+
+	if w := do(http.MethodGet, "/_tokens", "", "Authorization", bearer); w.Code != http.StatusForbidden {
+		t.Errorf("list with reader token: expected 403, got %d", w.Code)
+	}
+
+	if w := do(http.MethodDelete, "/_tokens/"+id, "", apiKeyHeader, "admin-key"); w.Code != http.StatusOK || w.Body.String() != `{"response":{"revoked":1}}` {
+		t.Errorf("revoke: unexpected %d %s", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodDelete, "/_tokens/"+id, "", apiKeyHeader, "admin-key"); w.Body.String() != `{"response":{"revoked":0}}` {
+		t.Errorf("revoke twice: unexpected %d %s", w.Code, w.Body.String())
+	}
+
+	if w := do(http.MethodGet, "/items/1", "", "Authorization", bearer); w.Code != http.StatusUnauthorized {
+		t.Errorf("read with revoked token: expected 401, got %d", w.Code)
+	}
+}
@@ -0,0 +1,204 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const maxTxOperations = 1000
+
+// TxOperation is a single write of POST /_tx. Values of the record and the id may refer to the id
+// of an earlier operation of the same request with {"$ref": index}.
+type TxOperation struct {
+	Op     string         `json:"op"`
+	Table  string         `json:"table"`
+	ID     any            `json:"id"`
+	Record map[string]any `json:"record"`
+}
+
+type TxRequest struct {
+	Operations []TxOperation `json:"operations"`
+}
+
+type TxOperationResult struct {
+	Index    int   `json:"index"`
+	ID       any   `json:"id"`
+	Affected int64 `json:"affected"`
+}
+
+type TxResponse struct {
+	Results []TxOperationResult `json:"results"`
+}
+
+// resolveRef replaces {"$ref": index} with the id of the earlier operation.
+func resolveRef(value any, results []TxOperationResult) (any, error) {
+	ref, ok := value.(map[string]any)
+	if !ok || len(ref) != 1 {
+		return value, nil
+	}
+
+	index, ok := ref["$ref"].(float64)
+	if !ok {
+		return value, nil
+	}
+
+	if index < 0 || int(index) >= len(results) || float64(int(index)) != index {
+		return nil, fmt.Errorf("invalid $ref %v", index)
+	}
+
+	return results[int(index)].ID, nil
+}
+
+// txWriteOp validates the operation like the single record handlers do and turns it into a writeOp.
+func (exp Explorer) txWriteOp(r *http.Request, operation TxOperation, primaryKey string, results []TxOperationResult) (writeOp, error) {
+	op := writeOp{
+		Op:         operation.Op,
+		Table:      operation.Table,
+		PrimaryKey: primaryKey,
+		LockToken:  r.Header.Get(lockTokenHeader),
+	}
+
+	record := make(map[string]any, len(operation.Record))
+	for column, value := range operation.Record {
+		resolved, err := resolveRef(value, results)
+		if err != nil {
+			return op, err
+		}
+		record[column] = resolved
+	}
+
+	if operation.Op != writeCreate {
+		id, err := resolveRef(operation.ID, results)
+		if err != nil {
+			return op, err
+		}
+		if id == nil {
+			return op, fmt.Errorf("id is required")
+		}
+
+		op.ID, err = exp.primaryKeyValue(operation.Table, formatParam(id))
+		if err != nil {
+			return op, err
+		}
+	}
+
+	var (
+		form map[string]any
+		err  error
+	)
+	columnWritable := exp.columnWritable(r.Context(), operation.Table, PrincipalFromContext(r.Context()))
+	switch operation.Op {
+	case writeCreate:
+		form, err = exp.processForm(operation.Table, record, primaryKey, ValidationOptions{
+			IgnorePk:          true,
+			IncludePk:         !exp.primaryKeyGenerated(operation.Table),
+			WithDefaultValues: true,
+			ColumnWritable:    columnWritable,
+		})
+	case writeUpdate:
+		form, err = exp.processForm(operation.Table, record, primaryKey, ValidationOptions{
+			IgnoreNotProvidedField: true,
+			ColumnWritable:         columnWritable,
+		})
+	}
+	op.Form = form
+
+	return op, err
+}
+
+// handlerTx runs the operations in one transaction, any failure rolls all of them back.
+func (exp Explorer) handlerTx(w http.ResponseWriter, r *http.Request) {
+	principal := PrincipalFromContext(r.Context())
+	if exp.requiresApproval(principal) {
+		exp.writeForbidden(w, r, fmt.Errorf("transactions are not allowed for writes requiring approval"))
+		return
+	}
+
+	var req TxRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	if len(req.Operations) == 0 || len(req.Operations) > maxTxOperations {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("expected 1 to %d operations", maxTxOperations))
+		return
+	}
+
+	primaryKeys := make(map[string]string)
+	for i, operation := range req.Operations {
+		if !exp.isValidTableName(operation.Table) {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("operation %d: unknown table %s", i, operation.Table))
+			return
+		}
+
+		if operation.Op != writeCreate && operation.Op != writeUpdate && operation.Op != writeDelete {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("operation %d: unknown op %s", i, operation.Op))
+			return
+		}
+
+		if err := exp.authorize(r.Context(), principal, Action{Table: operation.Table, Op: OpWrite}); err != nil {
+			exp.writeForbidden(w, r, err)
+			return
+		}
+
+		if _, ok := primaryKeys[operation.Table]; ok {
+			continue
+		}
+
+		primaryKey, err := exp.getPrimaryKey(operation.Table)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+		primaryKeys[operation.Table] = primaryKey
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer tx.Rollback()
+
+	results := make([]TxOperationResult, 0, len(req.Operations))
+	for i, operation := range req.Operations {
+		op, err := exp.txWriteOp(r, operation, primaryKeys[operation.Table], results)
+		if err != nil {
+			if formErrorStatus(err) == http.StatusForbidden {
+				exp.writeForbidden(w, r, err)
+				return
+			}
+
+			writeError(w, http.StatusBadRequest, fmt.Errorf("operation %d: %w", i, err))
+			return
+		}
+
+		written, err := exp.executeWrite(r.Context(), tx, op, auditMeta{Principal: principal})
+		if statusErr, ok := translateSQLError(err); ok {
+			if statusErr.Status == http.StatusServiceUnavailable {
+				w.Header().Set("Retry-After", retryAfterBusy)
+			}
+			writeError(w, statusErr.Status, fmt.Errorf("operation %d: %w", i, statusErr.Err))
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusConflict, fmt.Errorf("operation %d failed", i))
+			return
+		}
+
+		results = append(results, TxOperationResult{Index: i, ID: written.ID, Affected: written.Affected})
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	for _, result := range results {
+		addRowsAffected(r.Context(), result.Affected)
+	}
+
+	writeResponse(w, TxResponse{Results: results})
+}
@@ -0,0 +1,39 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveRef(t *testing.T) {
+	results := []TxOperationResult{{Index: 0, ID: int64(7), Affected: 1}}
+
+	value, err := resolveRef(map[string]any{"$ref": float64(0)}, results)
+	if err != nil || value != int64(7) {
+		t.Fatalf("resolveRef() = %v, %v, want 7", value, err)
+	}
+
+	value, err = resolveRef("plain", results)
+	if err != nil || value != "plain" {
+		t.Fatalf("resolveRef() = %v, %v, want plain", value, err)
+	}
+
+	for _, ref := range []float64{-1, 1, 0.5} {
+		if _, err := resolveRef(map[string]any{"$ref": ref}, results); err == nil {
+			t.Errorf("resolveRef(%v) expected error", ref)
+		}
+	}
+}
+
+func TestTxWriteOpLargeID(t *testing.T) {
+	exp := newTestExplorer(map[string]*TableSchema{
+		"items": {PrimaryKey: "id", Columns: []ColumnInfo{{Name: "id", DataType: "int"}}},
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/_tx", nil)
+	op, err := exp.txWriteOp(r, TxOperation{Op: writeDelete, Table: "items", ID: float64(1000000)}, "id", nil)
+	if err != nil || op.ID != int64(1000000) {
+		t.Errorf("txWriteOp() id = %v, %v, want 1000000", op.ID, err)
+	}
+}
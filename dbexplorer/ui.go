@@ -0,0 +1,26 @@
+package dbexplorer
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed ui/index.html
+var uiPage []byte
+
+// WithUI serves a single page admin UI at /_ui. It lists the tables, browses and filters their rows
+// and edits records with forms generated from /_schema, all through the JSON API. Its SQL console
+// runs statements on /_query, see WithQueryEndpoint, and the filters of a table can be saved as views,
+// see WithSavedViews.
+func WithUI() Option {
+	return func(exp *Explorer) error {
+		exp.ui = true
+		return nil
+	}
+}
+
+func (exp Explorer) handlerUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+	w.Write(uiPage)
+}
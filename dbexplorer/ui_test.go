@@ -0,0 +1,22 @@
+package dbexplorer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUI(t *testing.T) {
+	exp := Explorer{router: NewRouter(), ui: true}
+	exp.router.Handle(http.MethodGet, "/_ui/?", exp.handlerUI)
+
+	for _, path := range []string{"/_ui", "/_ui/"} {
+		w := httptest.NewRecorder()
+		exp.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") || !strings.Contains(w.Body.String(), "/_schema") ||
+			!strings.Contains(w.Body.String(), "/_query") {
+			t.Errorf("%s: unexpected response %d %v", path, w.Code, w.Header())
+		}
+	}
+}
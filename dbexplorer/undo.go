@@ -0,0 +1,195 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+var errUndoWithoutAudit = errors.New("undo requires the audit log")
+
+type UndoResponse struct {
+	// Undone is the operation of the reverted change.
+	Undone string         `json:"undone"`
+	Record map[string]any `json:"record,omitempty"`
+}
+
+type auditedChange struct {
+	Operation string
+	Before    map[string]any
+	After     map[string]any
+}
+
+// decodeAuditData keeps numbers as json.Number, so ids and amounts are written back unchanged.
+func decodeAuditData(data sql.NullString) (map[string]any, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data.String)))
+	decoder.UseNumber()
+
+	record := make(map[string]any)
+	if err := decoder.Decode(&record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// lastChange returns the most recent audited change of the record or nil.
+func (exp Explorer) lastChange(q queryer, table string, id any) (*auditedChange, error) {
+	var (
+		change        auditedChange
+		before, after sql.NullString
+	)
+
+	err := q.QueryRow(`SELECT operation, before_data, after_data FROM `+auditTable+` WHERE table_name = ? AND record_id = ? ORDER BY id DESC LIMIT 1`,
+		table, fmt.Sprint(id)).Scan(&change.Operation, &before, &after)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if change.Before, err = decodeAuditData(before); err != nil {
+		return nil, err
+	}
+	if change.After, err = decodeAuditData(after); err != nil {
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+// sameRecord compares a row with its audited state through their JSON form.
+func sameRecord(row map[string]any, audited map[string]any) bool {
+	encoded, err := json.Marshal(row)
+	if err != nil {
+		return false
+	}
+
+	decoded, err := decodeAuditData(sql.NullString{String: string(encoded), Valid: true})
+	if err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(decoded, audited)
+}
+
+// undoOp returns the write reverting the change. Updates restore only the columns the change modified.
+func undoOp(table string, primaryKey string, id any, change auditedChange) (writeOp, error) {
+	op := writeOp{Table: table, PrimaryKey: primaryKey, ID: id}
+
+	switch change.Operation {
+	case writeCreate:
+		op.Op = writeDelete
+	case writeUpdate:
+		op.Op = writeUpdate
+		op.Form = make(map[string]any)
+		for column, value := range change.Before {
+			if column != primaryKey && !reflect.DeepEqual(value, change.After[column]) {
+				op.Form[column] = value
+			}
+		}
+	case writeDelete:
+		op.Op = writeCreate
+		op.Form = change.Before
+	default:
+		return op, fmt.Errorf("unknown audited operation %s", change.Operation)
+	}
+
+	return op, nil
+}
+
+// handlerUndo reverts the most recent audited change of the record, POST /{table}/{id}/_undo.
+// The revert is audited like any write, so undoing twice redoes the change.
+func (exp Explorer) handlerUndo(w http.ResponseWriter, r *http.Request) {
+	if !exp.auditLog {
+		writeError(w, http.StatusBadRequest, errUndoWithoutAudit)
+		return
+	}
+
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	pkName, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	id, err := exp.primaryKeyValue(tableName, exp.getId(r.URL.Path))
+	if err != nil {
+		writeError(w, http.StatusNotFound, errRecordNotFound)
+		return
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	defer tx.Rollback()
+
+	change, err := exp.lastChange(tx, tableName, id)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	if change == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no change to undo"))
+		return
+	}
+
+	current, err := exp.getItem(tx, tableName, pkName, id)
+	exists := err == nil
+	if change.Operation == writeDelete && exists || change.Operation != writeDelete && (!exists || !sameRecord(current, change.After)) {
+		writeError(w, http.StatusConflict, fmt.Errorf("record changed since the last audited change"))
+		return
+	}
+
+	op, err := undoOp(tableName, pkName, id, *change)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	principal := PrincipalFromContext(r.Context())
+	if writable := exp.columnWritable(r.Context(), tableName, principal); writable != nil {
+		for column := range op.Form {
+			if !writable(column) {
+				exp.writeForbidden(w, r, NewColumnPermissionError(column))
+				return
+			}
+		}
+	}
+
+	if exp.requiresApproval(principal) {
+		exp.submitChange(w, r, principal, op)
+		return
+	}
+
+	op.LockToken = r.Header.Get(lockTokenHeader)
+	written, err := exp.executeWrite(r.Context(), tx, op, auditMeta{Principal: principal})
+	if err != nil {
+		writeQueryError(w, r, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+	addRowsAffected(r.Context(), written.Affected)
+
+	writeResponse(w, UndoResponse{Undone: change.Operation, Record: written.Row})
+}
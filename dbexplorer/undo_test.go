@@ -0,0 +1,41 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUndoOp(t *testing.T) {
+	change := auditedChange{
+		Operation: writeUpdate,
+		Before:    map[string]any{"id": json.Number("1"), "title": "old", "price": json.Number("10")},
+		After:     map[string]any{"id": json.Number("1"), "title": "new", "price": json.Number("10")},
+	}
+
+	op, err := undoOp("items", "id", 1, change)
+	if err != nil || op.Op != writeUpdate || !reflect.DeepEqual(op.Form, map[string]any{"title": "old"}) {
+		t.Errorf("unexpected undo of update %+v %v", op, err)
+	}
+
+	op, err = undoOp("items", "id", 1, auditedChange{Operation: writeDelete, Before: change.Before})
+	if err != nil || op.Op != writeCreate || !reflect.DeepEqual(op.Form, change.Before) {
+		t.Errorf("unexpected undo of delete %+v %v", op, err)
+	}
+
+	op, err = undoOp("items", "id", 1, auditedChange{Operation: writeCreate, After: change.After})
+	if err != nil || op.Op != writeDelete || op.ID != 1 {
+		t.Errorf("unexpected undo of create %+v %v", op, err)
+	}
+}
+
+func TestSameRecord(t *testing.T) {
+	audited := map[string]any{"id": json.Number("1"), "title": "new", "updated": nil}
+
+	if !sameRecord(map[string]any{"id": int64(1), "title": "new", "updated": nil}, audited) {
+		t.Errorf("expected the row to match its audited state")
+	}
+	if sameRecord(map[string]any{"id": int64(1), "title": "other", "updated": nil}, audited) {
+		t.Errorf("expected a changed row not to match")
+	}
+}
@@ -0,0 +1,170 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	reasonInvalidType      = "invalid type"
+	reasonRequired         = "required value"
+	reasonTooLong          = "too long value"
+	reasonInvalidEnumValue = "invalid enum value"
+	reasonMissingReference = "missing reference"
+	reasonUnknownField     = "unknown field"
+)
+
+// WithStrictFields rejects write bodies with keys that are no column of the table instead of dropping them,
+// so typos of clients are caught early.
+func WithStrictFields() Option {
+	return func(exp *Explorer) error {
+		exp.strictFields = true
+		return nil
+	}
+}
+
+// unknownFields reports the keys of the form that are none of the columns, in order.
+func unknownFields(columns []string, form map[string]any) ValidationErrors {
+	errs := make(ValidationErrors, 0)
+	for field := range form {
+		if !containsString(columns, field) {
+			errs = append(errs, ValidationError{Field: field, Reason: reasonUnknownField})
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+
+	return errs
+}
+
+type ValidateResponse struct {
+	Valid  bool              `json:"valid"`
+	Errors []ValidationError `json:"errors"`
+}
+
+// validateForm runs every validation on the form and collects all violations instead of stopping at the first one.
+// References are only looked up in the tables the principal of ctx may read, so the check doesn't reveal
+// which records exist elsewhere, the write itself still fails on a missing one.
+func (exp Explorer) validateForm(ctx context.Context, q queryer, table string, form map[string]any, primaryKey string, validationOptions ValidationOptions) ([]ValidationError, error) {
+	errs := make([]ValidationError, 0)
+
+	columns, err := exp.getColumnTypesFromCache(table)
+	if err != nil {
+		return errs, err
+	}
+
+	schema, err := exp.getTableSchema(table)
+	if err != nil {
+		return errs, err
+	}
+
+	_, err = exp.processForm(table, form, primaryKey, validationOptions)
+	var formErrors ValidationErrors
+	if errors.As(err, &formErrors) {
+		errs = append(errs, formErrors...)
+	} else if err != nil {
+		return errs, err
+	}
+
+	invalid := make(map[string]bool)
+	for _, e := range errs {
+		invalid[e.Field] = true
+	}
+
+	for _, c := range columns {
+		name := exp.apiColumn(c.Name())
+		value, has := form[name]
+		if !has || value == nil || name == primaryKey || invalid[name] {
+			continue
+		}
+
+		info, ok := schema.Column(name)
+		if !ok {
+			continue
+		}
+
+		if str, ok := value.(string); ok {
+			if info.MaxLength != nil && int64(utf8.RuneCountInString(str)) > *info.MaxLength {
+				errs = append(errs, ValidationError{Field: name, Reason: reasonTooLong})
+				continue
+			}
+
+			if info.DataType == "enum" && !containsString(info.EnumValues, str) {
+				errs = append(errs, ValidationError{Field: name, Reason: reasonInvalidEnumValue})
+				continue
+			}
+		}
+
+		if fk, ok := schema.ForeignKey(name); ok && exp.authorize(ctx, PrincipalFromContext(ctx), Action{Table: fk.RefTable, Op: OpRead}) == nil {
+			var exists int
+			err := q.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", exp.quote(fk.RefTable), exp.quoteColumn(fk.RefTable, fk.RefColumn)), value).Scan(&exists)
+			if err != nil {
+				return errs, err
+			}
+
+			if exists == 0 {
+				errs = append(errs, ValidationError{Field: name, Reason: reasonMissingReference})
+			}
+		}
+	}
+
+	return errs, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (exp Explorer) handlerValidateItem(w http.ResponseWriter, r *http.Request) {
+	tableName, err := exp.getTableName(r.URL.Path)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	primaryKey, err := exp.getPrimaryKey(tableName)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	form := make(map[string]any)
+	err = decodeJSONBody(r, &form)
+	if err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	validationOptions := ValidationOptions{
+		IgnorePk:          true,
+		IncludePk:         !exp.primaryKeyGenerated(tableName),
+		WithDefaultValues: true,
+	}
+	if r.URL.Query().Get("mode") == "update" {
+		validationOptions = ValidationOptions{
+			IgnoreNotProvidedField: true,
+		}
+	}
+
+	errs, err := exp.validateForm(r.Context(), exp.db(), tableName, form, primaryKey, validationOptions)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, ValidateResponse{
+		Valid:  len(errs) == 0,
+		Errors: errs,
+	})
+}
@@ -0,0 +1,79 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnknownFields(t *testing.T) {
+	columns := []string{"id", "title", "description"}
+
+	if errs := unknownFields(columns, map[string]any{"title": "a", "description": nil}); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	errs := unknownFields(columns, map[string]any{"titel": "a", "id": 1, "descr": "b"})
+	expected := ValidationErrors{
+		{Field: "descr", Reason: reasonUnknownField},
+		{Field: "titel", Reason: reasonUnknownField},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Errorf("expected %v, got %v", expected, errs)
+	}
+}
+
+func TestValidateReferences(t *testing.T) {
+	db := openTestDB(t, "validation_children", "validation_parents")
+	for _, query := range []string{
+		"CREATE TABLE validation_parents (id int NOT NULL, PRIMARY KEY (id))",
+		`CREATE TABLE validation_children (id int NOT NULL AUTO_INCREMENT, parent_id int NOT NULL, PRIMARY KEY (id),
+  FOREIGN KEY (parent_id) REFERENCES validation_parents (id))`,
+		"INSERT INTO validation_parents (id) VALUES (1)",
+	} {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler, err := New(db,
+		WithAuthenticator(AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+			return &Principal{Name: "bob", Roles: []string{r.Header.Get("X-Role")}}, nil
+		})),
+		WithAuthorizer(func(ctx context.Context, principal *Principal, action Action) error {
+			if action.Table == "validation_parents" && !principal.HasRole("reader") {
+				return fmt.Errorf("access to %s denied", action.Table)
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		role     string
+		body     string
+		expected string
+	}{
+		{"reader", `{"parent_id": 1}`, `{"response":{"valid":true,"errors":[]}}`},
+		{"reader", `{"parent_id": 2}`, `{"response":{"valid":false,"errors":[{"field":"parent_id","reason":"missing reference"}]}}`},
+		// without read access the existence of parents is not revealed
+		{"writer", `{"parent_id": 2}`, `{"response":{"valid":true,"errors":[]}}`},
+	}
+
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodPost, "/validation_children/_validate", strings.NewReader(c.body))
+		r.Header.Set("X-Role", c.role)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Body.String() != c.expected {
+			t.Errorf("%s %s: expected %s, got %d %s", c.role, c.body, c.expected, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package dbexplorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const viewsTable = metaTablePrefix + "views"
+
+// SavedView is a named slice of a table: the query string of the list endpoint and the columns to show.
+type SavedView struct {
+	ID        int64    `json:"id"`
+	Name      string   `json:"name"`
+	Table     string   `json:"table"`
+	Filters   string   `json:"filters"`
+	Sort      string   `json:"sort"`
+	Columns   []string `json:"columns"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type ViewForm struct {
+	Name    *string  `json:"name"`
+	Table   *string  `json:"table"`
+	Filters *string  `json:"filters"`
+	Sort    *string  `json:"sort"`
+	Columns []string `json:"columns"`
+}
+
+type CreateViewResponse struct {
+	ID any `json:"id"`
+}
+
+type GetViewsResponse struct {
+	Views []SavedView `json:"views"`
+}
+
+type GetViewResponse struct {
+	View SavedView `json:"view"`
+}
+
+// WithSavedViews enables /_views, where every user keeps named views of the tables they check often,
+// e.g. GET /items?status=new&sort=-updated showing id and title only. The views are stored in the
+// _explorer_views table and only visible to the principal who saved them.
+func WithSavedViews() Option {
+	return func(exp *Explorer) error {
+		exp.metaTables = append(exp.metaTables, metaTable{
+			Name: viewsTable,
+			Columns: []metaColumn{
+				{Name: "id", Kind: "serial"},
+				{Name: "owner", Kind: "string"},
+				{Name: "name", Kind: "string"},
+				{Name: "table_name", Kind: "string"},
+				{Name: "filters", Kind: "text"},
+				{Name: "sort_by", Kind: "string"},
+				{Name: "column_list", Kind: "text"},
+				{Name: "created_at", Kind: "time"},
+				{Name: "updated_at", Kind: "time"},
+			},
+			PrimaryKey: "id",
+			Indexes:    [][]string{{"owner"}},
+		})
+		exp.savedViews = true
+
+		return nil
+	}
+}
+
+// viewOwner is the principal the views of the request belong to, "" without authentication.
+func viewOwner(r *http.Request) string {
+	if principal := PrincipalFromContext(r.Context()); principal != nil {
+		return principal.Name
+	}
+
+	return ""
+}
+
+// checkView validates a view against the schema, so that opening it runs a valid list request.
+func (exp Explorer) checkView(view SavedView) error {
+	if view.Name == "" {
+		return NewValidationError("name")
+	}
+
+	if !exp.isValidTableName(view.Table) {
+		return NewValidationError("table")
+	}
+
+	query, err := url.ParseQuery(view.Filters)
+	if err != nil {
+		return NewValidationError("filters")
+	}
+	if view.Sort != "" {
+		query.Set("sort", view.Sort)
+	}
+	if _, err := exp.parseListQuery(view.Table, query); err != nil {
+		return err
+	}
+
+	for _, column := range view.Columns {
+		if !exp.isValidColumnName(view.Table, column) {
+			return NewValidationError("columns")
+		}
+	}
+
+	return nil
+}
+
+func scanView(scanner interface{ Scan(...any) error }) (SavedView, error) {
+	var (
+		view    SavedView
+		columns string
+	)
+
+	if err := scanner.Scan(&view.ID, &view.Name, &view.Table, &view.Filters, &view.Sort, &columns, &view.CreatedAt, &view.UpdatedAt); err != nil {
+		return view, err
+	}
+
+	view.Columns = make([]string, 0)
+	json.Unmarshal([]byte(columns), &view.Columns)
+
+	return view, nil
+}
+
+const viewColumns = `id, name, table_name, filters, sort_by, column_list, created_at, updated_at`
+
+func (exp Explorer) getView(r *http.Request) (SavedView, error) {
+	row := exp.db().QueryRow(`SELECT `+viewColumns+` FROM `+viewsTable+` WHERE id = ? AND owner = ?`, exp.getId(r.URL.Path), viewOwner(r))
+	return scanView(row)
+}
+
+func (exp Explorer) handlerGetViews(w http.ResponseWriter, r *http.Request) {
+	query := `SELECT ` + viewColumns + ` FROM ` + viewsTable + ` WHERE owner = ?`
+	args := []any{viewOwner(r)}
+	if table := r.URL.Query().Get("table"); table != "" {
+		query += ` AND table_name = ?`
+		args = append(args, table)
+	}
+
+	rows, err := exp.db().Query(query+` ORDER BY name, id`, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	defer rows.Close()
+
+	views := make([]SavedView, 0)
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			writeInternalError(w, r, err)
+			return
+		}
+
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, GetViewsResponse{Views: views})
+}
+
+func (exp Explorer) handlerGetView(w http.ResponseWriter, r *http.Request) {
+	view, err := exp.getView(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("view not found"))
+		return
+	}
+
+	writeResponse(w, GetViewResponse{View: view})
+}
+
+func (exp Explorer) handlerCreateView(w http.ResponseWriter, r *http.Request) {
+	form := ViewForm{}
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	view := SavedView{Columns: form.Columns}
+	if form.Name != nil {
+		view.Name = *form.Name
+	}
+	if form.Table != nil {
+		view.Table = *form.Table
+	}
+	if form.Filters != nil {
+		view.Filters = *form.Filters
+	}
+	if form.Sort != nil {
+		view.Sort = *form.Sort
+	}
+	if view.Columns == nil {
+		view.Columns = make([]string, 0)
+	}
+
+	if err := exp.checkView(view); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := exp.authorize(r.Context(), PrincipalFromContext(r.Context()), Action{Table: view.Table, Op: OpRead, Method: http.MethodGet}); err != nil {
+		exp.writeForbidden(w, r, err)
+		return
+	}
+
+	columns, _ := json.Marshal(view.Columns)
+	now := time.Now().UTC()
+	id, err := exp.insertReturningID(exp.db(), `INSERT INTO `+viewsTable+` (owner, name, table_name, filters, sort_by, column_list, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, "id",
+		viewOwner(r), view.Name, view.Table, view.Filters, view.Sort, string(columns), now, now)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	writeResponse(w, CreateViewResponse{ID: id})
+}
+
+func (exp Explorer) handlerUpdateView(w http.ResponseWriter, r *http.Request) {
+	form := ViewForm{}
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		writeBodyError(w, err)
+		return
+	}
+
+	view, err := exp.getView(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("view not found"))
+		return
+	}
+
+	if form.Name != nil {
+		view.Name = *form.Name
+	}
+	if form.Table != nil {
+		view.Table = *form.Table
+	}
+	if form.Filters != nil {
+		view.Filters = *form.Filters
+	}
+	if form.Sort != nil {
+		view.Sort = *form.Sort
+	}
+	if form.Columns != nil {
+		view.Columns = form.Columns
+	}
+
+	if err := exp.checkView(view); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := exp.authorize(r.Context(), PrincipalFromContext(r.Context()), Action{Table: view.Table, Op: OpRead, Method: http.MethodGet}); err != nil {
+		exp.writeForbidden(w, r, err)
+		return
+	}
+
+	setColumns := []string{"name = ?", "table_name = ?", "filters = ?", "sort_by = ?", "column_list = ?", "updated_at = ?"}
+	columns, _ := json.Marshal(view.Columns)
+	args := []any{view.Name, view.Table, view.Filters, view.Sort, string(columns), time.Now().UTC(), view.ID, viewOwner(r)}
+
+	result, err := exp.db().Exec(`UPDATE `+viewsTable+` SET `+strings.Join(setColumns, ", ")+` WHERE id = ? AND owner = ?`, args...)
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	// the view was read above, so it matched even when the update left it as it was
+	res := UpdateTableItemResponse{Matched: 1}
+	if affected > 0 {
+		res.Updated = 1
+		res.Changed = 1
+	}
+
+	writeResponse(w, res)
+}
+
+func (exp Explorer) handlerDeleteView(w http.ResponseWriter, r *http.Request) {
+	result, err := exp.db().Exec(`DELETE FROM `+viewsTable+` WHERE id = ? AND owner = ?`, exp.getId(r.URL.Path), viewOwner(r))
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		writeInternalError(w, r, err)
+		return
+	}
+
+	deleted := 0
+	if affected > 0 {
+		deleted = 1
+	}
+
+	writeResponse(w, DeleteTableItemResponse{Deleted: deleted})
+}
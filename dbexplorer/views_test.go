@@ -0,0 +1,29 @@
+package dbexplorer
+
+import (
+	"testing"
+)
+
+func TestCheckView(t *testing.T) {
+	exp := newItemsExplorer()
+
+	cases := []struct {
+		view  SavedView
+		valid bool
+	}{
+		{SavedView{Name: "new", Table: "items", Filters: "status=new&filter=id__in=1,2", Sort: "-id", Columns: []string{"id"}}, true},
+		{SavedView{Name: "all", Table: "items"}, true},
+		{SavedView{Table: "items"}, false},
+		{SavedView{Name: "x", Table: "missing"}, false},
+		{SavedView{Name: "x", Table: "items", Filters: "missing=1"}, false},
+		{SavedView{Name: "x", Table: "items", Sort: "missing"}, false},
+		{SavedView{Name: "x", Table: "items", Filters: "%zz"}, false},
+		{SavedView{Name: "x", Table: "items", Columns: []string{"secret"}}, false},
+	}
+
+	for _, c := range cases {
+		if err := exp.checkView(c.view); (err == nil) != c.valid {
+			t.Errorf("%+v: unexpected error %v", c.view, err)
+		}
+	}
+}
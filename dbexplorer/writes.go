@@ -0,0 +1,136 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+)
+
+const (
+	writeCreate = "create"
+	writeUpdate = "update"
+	writeDelete = "delete"
+)
+
+// writeOp is a validated change of a single record.
+type writeOp struct {
+	Op         string
+	Table      string
+	PrimaryKey string
+	ID         any
+	Form       map[string]any
+	// LockToken is the X-Lock-Token of the request, updates and deletes of locked records need it.
+	LockToken string
+}
+
+type writeResult struct {
+	ID       any
+	Affected int64
+	// Row is the record after a create or update, or before a delete, when it was read.
+	Row map[string]any
+}
+
+// runWrite executes op on behalf of principal. With the audit log enabled the change
+// and its audit entry are written in one transaction.
+func (exp Explorer) runWrite(ctx context.Context, principal *Principal, op writeOp) (writeResult, error) {
+	if !exp.auditLog {
+		return exp.executeWrite(ctx, exp.db(), op, auditMeta{})
+	}
+
+	tx, err := exp.db().Begin()
+	if err != nil {
+		return writeResult{}, err
+	}
+
+	result, err := exp.executeWrite(ctx, tx, op, auditMeta{Principal: principal})
+	if err != nil {
+		tx.Rollback()
+		return result, err
+	}
+
+	return result, tx.Commit()
+}
+
+func (exp Explorer) executeWrite(ctx context.Context, q queryer, op writeOp, meta auditMeta) (writeResult, error) {
+	result := writeResult{
+		ID: op.ID,
+	}
+
+	if err := exp.checkRecordLock(q, op); err != nil {
+		return result, err
+	}
+
+	if exp.backend != nil {
+		return exp.executeBackendWrite(ctx, op)
+	}
+
+	var (
+		before, after map[string]any
+		err           error
+	)
+
+	// with RETURNING the audited row comes back from the write itself instead of a separate SELECT
+	returning := exp.auditLog && exp.returning && op.Op != writeUpdate
+
+	if exp.auditLog && op.Op != writeCreate && !returning {
+		before, err = exp.getItem(q, op.Table, op.PrimaryKey, op.ID)
+		if err == sql.ErrNoRows {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+
+	switch op.Op {
+	case writeCreate:
+		if returning {
+			after, err = exp.createItemReturning(q, op.Table, op.Form)
+			result.ID = after[op.PrimaryKey]
+		} else {
+			result.ID, err = exp.createItem(q, op.Table, op.Form, nil, op.PrimaryKey)
+		}
+		result.Affected = 1
+	case writeUpdate:
+		result.Affected, err = exp.updateItem(q, op.Table, op.Form, nil, op.PrimaryKey, op.ID)
+	case writeDelete:
+		if returning {
+			before, err = exp.deleteItemReturning(q, op.Table, op.PrimaryKey, op.ID)
+			if before != nil {
+				result.Affected = 1
+			}
+		} else {
+			result.Affected, err = exp.deleteItem(q, op.Table, op.PrimaryKey, op.ID)
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+
+	exp.countAffected(op.Table, op.Op, result.Affected)
+
+	if !exp.auditLog || result.Affected == 0 {
+		return result, nil
+	}
+
+	if op.Op != writeDelete && after == nil {
+		after, err = exp.getItem(q, op.Table, op.PrimaryKey, result.ID)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	result.Row = after
+	if op.Op == writeDelete {
+		result.Row = before
+	}
+
+	err = exp.writeAudit(q, AuditEntry{
+		Table:     op.Table,
+		RecordID:  result.ID,
+		Operation: op.Op,
+		Before:    before,
+		After:     after,
+	}, meta)
+
+	return result, err
+}
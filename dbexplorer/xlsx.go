@@ -0,0 +1,246 @@
+package dbexplorer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		ID   string `xml:"id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText is a shared or inline string, either plain or made of rich text runs.
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Style  int      `xml:"s,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readZipXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("xlsx: missing %s", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	defer rc.Close()
+
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// xlsxColumnIndex converts the column letters of a cell reference like "AB12" to a zero based index.
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A') + 1
+	}
+
+	return index - 1
+}
+
+// isDateFormat reports whether the number format shows dates, built-in formats 14-22 and 45-47 do.
+func isDateFormat(id int, code string) bool {
+	if (id >= 14 && id <= 22) || (id >= 45 && id <= 47) {
+		return true
+	}
+
+	// drop quoted literals and colors like [Red] before looking for date parts
+	var b strings.Builder
+	inQuotes, inBrackets := false, false
+	for _, r := range code {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == '[' && !inQuotes:
+			inBrackets = true
+		case r == ']' && !inQuotes:
+			inBrackets = false
+		case !inQuotes && !inBrackets:
+			b.WriteRune(r)
+		}
+	}
+
+	return strings.ContainsAny(strings.ToLower(b.String()), "dmyhs")
+}
+
+// xlsxDate converts an Excel serial date of the 1900 date system to text.
+func xlsxDate(serial float64) string {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	t := epoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+
+	if seconds == 0 {
+		return t.Format("2006-01-02")
+	}
+
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// readXLSX reads the first sheet of a workbook as text rows. Empty rows are skipped, dates are
+// formatted as YYYY-MM-DD[ hh:mm:ss] and booleans as 1 or 0.
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var workbook xlsxWorkbook
+	if err := readZipXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, fmt.Errorf("xlsx: no sheets")
+	}
+
+	var relationships xlsxRelationships
+	if err := readZipXML(files, "xl/_rels/workbook.xml.rels", &relationships); err != nil {
+		return nil, err
+	}
+
+	sheetPath := ""
+	for _, rel := range relationships.Relationships {
+		if rel.ID == workbook.Sheets[0].ID {
+			sheetPath = rel.Target
+		}
+	}
+	if strings.HasPrefix(sheetPath, "/") {
+		sheetPath = strings.TrimPrefix(sheetPath, "/")
+	} else {
+		sheetPath = path.Join("xl", sheetPath)
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := readZipXML(files, "xl/sharedStrings.xml", &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	var styles xlsxStyles
+	if _, ok := files["xl/styles.xml"]; ok {
+		if err := readZipXML(files, "xl/styles.xml", &styles); err != nil {
+			return nil, err
+		}
+	}
+
+	formatCodes := make(map[int]string)
+	for _, f := range styles.NumFmts {
+		formatCodes[f.ID] = f.Code
+	}
+	dateStyles := make(map[int]bool)
+	for i, xf := range styles.CellXfs {
+		dateStyles[i] = isDateFormat(xf.NumFmtID, formatCodes[xf.NumFmtID])
+	}
+
+	var sheet xlsxSheet
+	if err := readZipXML(files, sheetPath, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, r := range sheet.Rows {
+		row := make([]string, 0)
+		for i, c := range r.Cells {
+			index := i
+			if c.Ref != "" {
+				index = xlsxColumnIndex(c.Ref)
+			}
+			for len(row) <= index {
+				row = append(row, "")
+			}
+
+			value := c.Value
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 || n >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("xlsx: invalid shared string %s in %s", value, c.Ref)
+				}
+				value = sharedStrings.Items[n].String()
+			case "inlineStr":
+				value = c.Inline.String()
+			case "", "n":
+				if serial, err := strconv.ParseFloat(value, 64); err == nil && dateStyles[c.Style] {
+					value = xlsxDate(serial)
+				}
+			}
+			row[index] = value
+		}
+
+		if strings.Join(row, "") != "" {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
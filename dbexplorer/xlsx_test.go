@@ -0,0 +1,71 @@
+package dbexplorer
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func buildXLSX(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	data := buildXLSX(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Items" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>title</t></si><si><t>updated</t></si><si><r><t>mem</t></r><r><t>cache</t></r></si></sst>`,
+		"xl/styles.xml":              `<styleSheet><cellXfs><xf numFmtId="0"/><xf numFmtId="14"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>id</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2" s="1"><v>45292</v></c><c r="C2"><v>7</v></c></row>
+<row r="3"></row>
+<row r="4"><c r="C4"><v>8</v></c></row>
+</sheetData></worksheet>`,
+	})
+
+	rows, err := readXLSX(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{"title", "updated", "id"},
+		{"memcache", "2024-01-01", "7"},
+		{"", "", "8"},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %#v, got %#v", expected, rows)
+	}
+}
+
+func TestIsDateFormat(t *testing.T) {
+	cases := map[string]bool{
+		"yyyy-mm-dd":     true,
+		"dd/mm/yy hh:mm": true,
+		"0.00":           false,
+		`"day "0`:        false,
+		"[Red]#,##0":     false,
+	}
+
+	for code, expected := range cases {
+		if got := isDateFormat(164, code); got != expected {
+			t.Errorf("%s: expected %v, got %v", code, expected, got)
+		}
+	}
+}